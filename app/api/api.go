@@ -1,4 +1,124 @@
 package api
 
+//go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg api -path App,API
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/common"
+	v2net "v2ray.com/core/common/net"
+)
+
+// Service is a management service that can be exposed through the API server.
+type Service interface {
+	// Register registers the service on the given gRPC server.
+	Register(*grpc.Server)
+}
+
+// ApiServer is an application that serves management requests over gRPC.
 type ApiServer struct {
+	sync.Mutex
+	config   *Config
+	services []Service
+	server   *grpc.Server
+}
+
+// New creates a new ApiServer with the given config.
+func New(ctx context.Context, config *Config) (*ApiServer, error) {
+	if config.Port == 0 {
+		return nil, newError("API port is not specified")
+	}
+
+	s := &ApiServer{
+		config: config,
+	}
+
+	for _, rawConfig := range config.Service {
+		settings, err := rawConfig.GetInstance()
+		if err != nil {
+			return nil, err
+		}
+		rawService, err := common.CreateObject(ctx, settings)
+		if err != nil {
+			return nil, newError("failed to create service").Base(err)
+		}
+		service, ok := rawService.(Service)
+		if !ok {
+			return nil, newError("not a Service: ", rawConfig.Type)
+		}
+		s.services = append(s.services, service)
+	}
+
+	return s, nil
+}
+
+// Interface implements app.Application.
+func (*ApiServer) Interface() interface{} {
+	return (*ApiServer)(nil)
+}
+
+// Start implements app.Application. It returns an error if the server is already started.
+func (s *ApiServer) Start() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.server != nil {
+		return newError("API server is already started")
+	}
+
+	address := v2net.LocalHostIP
+	if s.config.Listen != nil {
+		address = s.config.Listen.AsAddress()
+	}
+
+	listener, err := net.Listen("tcp", v2net.TCPDestination(address, v2net.Port(s.config.Port)).NetAddr())
+	if err != nil {
+		return newError("failed to listen on ", address, ":", s.config.Port).Base(err)
+	}
+
+	s.server = grpc.NewServer()
+	for _, service := range s.services {
+		service.Register(s.server)
+	}
+
+	go func(server *grpc.Server) {
+		if err := server.Serve(listener); err != nil {
+			log.Trace(newError("API server stopped").Base(err).AtInfo())
+		}
+	}(s.server)
+
+	log.Trace(newError("API server listening on ", listener.Addr()))
+	return nil
+}
+
+// Close implements app.Application.
+func (s *ApiServer) Close() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.server != nil {
+		s.server.Stop()
+		s.server = nil
+	}
+}
+
+// FromSpace returns the ApiServer in the given space, or nil if not present.
+func FromSpace(space app.Space) *ApiServer {
+	a := space.GetApplication((*ApiServer)(nil))
+	if a == nil {
+		return nil
+	}
+	return a.(*ApiServer)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
 }
@@ -0,0 +1,63 @@
+package api_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"v2ray.com/core/app"
+	. "v2ray.com/core/app/api"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/proxyman/command"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/dice"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestApiServer(t *testing.T) {
+	assert := assert.On(t)
+
+	port := v2net.Port(dice.Roll(20000) + 10000)
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.InboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, &Config{
+		Port:    uint32(port),
+		Service: []*serial.TypedMessage{serial.ToTypedMessage(&command.Config{})},
+	})).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	server := FromSpace(space)
+	assert.Pointer(server).IsNotNil()
+	assert.Error(server.Start()).IsNil()
+	assert.Error(server.Start()).IsNotNil()
+
+	conn, err := grpc.Dial(v2net.TCPDestination(v2net.LocalHostIP, port).NetAddr(), grpc.WithInsecure())
+	assert.Error(err).IsNil()
+	defer conn.Close()
+
+	list, err := command.NewHandlerServiceClient(conn).ListOutbounds(context.Background(), &command.ListOutboundsRequest{})
+	assert.Error(err).IsNil()
+	assert.Int(len(list.Tag)).Equals(0)
+
+	server.Close()
+	_, err = net.Dial("tcp", v2net.TCPDestination(v2net.LocalHostIP, port).NetAddr())
+	assert.Error(err).IsNotNil()
+
+	// The server can be started again after closed.
+	assert.Error(server.Start()).IsNil()
+	server.Close()
+}
+
+func TestApiServerNoPort(t *testing.T) {
+	assert := assert.On(t)
+
+	_, err := New(context.Background(), &Config{})
+	assert.Error(err).IsNotNil()
+}
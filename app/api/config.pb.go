@@ -0,0 +1,79 @@
+package api
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v2ray_core_common_net "v2ray.com/core/common/net"
+import v2ray_core_common_serial "v2ray.com/core/common/serial"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Config struct {
+	// Address to listen on. Defaults to 127.0.0.1.
+	Listen *v2ray_core_common_net.IPOrDomain `protobuf:"bytes,1,opt,name=listen" json:"listen,omitempty"`
+	// Port to listen on for management requests.
+	Port uint32 `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
+	// Services to be exposed through the API server.
+	Service []*v2ray_core_common_serial.TypedMessage `protobuf:"bytes,3,rep,name=service" json:"service,omitempty"`
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Config) GetListen() *v2ray_core_common_net.IPOrDomain {
+	if m != nil {
+		return m.Listen
+	}
+	return nil
+}
+
+func (m *Config) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *Config) GetService() []*v2ray_core_common_serial.TypedMessage {
+	if m != nil {
+		return m.Service
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.api.Config")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/api/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 254 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0xc1, 0x4a, 0x03, 0x31,
+	0x10, 0x86, 0xd9, 0xae, 0xac, 0x90, 0xe2, 0x25, 0x07, 0x59, 0x7a, 0x5a, 0x15, 0x74, 0x4f, 0x13,
+	0x59, 0xbd, 0x78, 0xb3, 0xd6, 0x8b, 0x07, 0xb1, 0x2c, 0xe2, 0xc1, 0x8b, 0x8c, 0xd9, 0xb1, 0x04,
+	0x9a, 0x64, 0x48, 0x42, 0x61, 0x1f, 0xc5, 0x57, 0xf0, 0x29, 0xa5, 0xbb, 0x16, 0x44, 0x7b, 0x0b,
+	0xe4, 0xff, 0xfe, 0xf9, 0x66, 0xc4, 0xd9, 0xa6, 0x09, 0xd8, 0x83, 0xf6, 0x56, 0x69, 0x1f, 0x48,
+	0x21, 0xb3, 0x42, 0x36, 0x4a, 0x7b, 0xf7, 0x61, 0x56, 0xc0, 0xc1, 0x27, 0x2f, 0xe5, 0x2e, 0x14,
+	0x08, 0x90, 0x19, 0x90, 0xcd, 0xec, 0xe2, 0x0f, 0xa8, 0xbd, 0xb5, 0xde, 0x29, 0x47, 0x49, 0x61,
+	0xd7, 0x05, 0x8a, 0x71, 0x84, 0x67, 0x97, 0xfb, 0x83, 0x91, 0x82, 0xc1, 0xb5, 0x4a, 0x3d, 0x53,
+	0xf7, 0x66, 0x29, 0x46, 0x5c, 0xd1, 0x48, 0x9c, 0x7e, 0x66, 0xa2, 0x58, 0x0c, 0xf3, 0xe5, 0x8d,
+	0x28, 0xd6, 0x26, 0x26, 0x72, 0x65, 0x56, 0x65, 0xf5, 0xb4, 0x39, 0x81, 0x5f, 0x2a, 0x63, 0x13,
+	0x38, 0x4a, 0xf0, 0xb0, 0x7c, 0x0a, 0xf7, 0xde, 0xa2, 0x71, 0xed, 0x0f, 0x20, 0xa5, 0x38, 0x60,
+	0x1f, 0x52, 0x39, 0xa9, 0xb2, 0xfa, 0xa8, 0x1d, 0xde, 0xf2, 0x56, 0x1c, 0x46, 0x0a, 0x1b, 0xa3,
+	0xa9, 0xcc, 0xab, 0xbc, 0x9e, 0x36, 0xe7, 0x7b, 0xfa, 0x46, 0x33, 0x78, 0xde, 0x9a, 0x3d, 0x8e,
+	0x62, 0xed, 0x0e, 0xbb, 0xbb, 0x16, 0xc7, 0xda, 0x5b, 0xf8, 0x7f, 0x90, 0x65, 0xf6, 0x9a, 0x23,
+	0x9b, 0xaf, 0x89, 0x7c, 0x69, 0x5a, 0xec, 0x61, 0xb1, 0xfd, 0x9b, 0x33, 0xc3, 0x9c, 0xcd, 0x7b,
+	0x31, 0x2c, 0x76, 0xf5, 0x3d, 0x00, 0x04, 0x50, 0x2b, 0x93, 0x6e, 0x01, 0x00, 0x00,
+}
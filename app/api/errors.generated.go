@@ -1,5 +1,5 @@
-package dns
+package api
 
 import "v2ray.com/core/common/errors"
 
-func newError(values ...interface{}) *errors.Error { return errors.New(values...).Path("App", "DNS") }
+func newError(values ...interface{}) *errors.Error { return errors.New(values...).Path("App", "API") }
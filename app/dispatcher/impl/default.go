@@ -8,12 +8,16 @@ import (
 
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/dns/resolver"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/app/router"
+	"v2ray.com/core/app/stats"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport/ray"
 )
@@ -28,8 +32,11 @@ var (
 
 // DefaultDispatcher is a default implementation of Dispatcher.
 type DefaultDispatcher struct {
-	ohm    proxyman.OutboundHandlerManager
-	router *router.Router
+	ohm     proxyman.OutboundHandlerManager
+	router  *router.Router
+	stats   stats.Manager
+	policy  policy.Manager
+	fakeDNS resolver.FakeDNS
 }
 
 // NewDefaultDispatcher create a new DefaultDispatcher.
@@ -45,6 +52,11 @@ func NewDefaultDispatcher(ctx context.Context, config *dispatcher.Config) (*Defa
 			return newError("OutboundHandlerManager is not found in the space")
 		}
 		d.router = router.FromSpace(space)
+		d.stats = stats.FromSpace(space)
+		d.policy = policy.FromSpace(space)
+		if fakeDNS, ok := resolver.FromSpace(space).(resolver.FakeDNS); ok {
+			d.fakeDNS = fakeDNS
+		}
 		return nil
 	})
 	return d, nil
@@ -63,14 +75,37 @@ func (*DefaultDispatcher) Interface() interface{} {
 	return (*dispatcher.Interface)(nil)
 }
 
+// Dependencies implements app.HasDependencies.
+func (*DefaultDispatcher) Dependencies() []interface{} {
+	return []interface{}{
+		(*proxyman.OutboundHandlerManager)(nil),
+		(*stats.Manager)(nil),
+		(*policy.Manager)(nil),
+		(*router.Router)(nil),
+	}
+}
+
 // Dispatch implements Dispatcher.Interface.
 func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destination) (ray.InboundRay, error) {
 	if !destination.IsValid() {
 		panic("Dispatcher: Invalid destination.")
 	}
+	if d.fakeDNS != nil && !destination.Address.Family().IsDomain() {
+		if domain, found := d.fakeDNS.GetFakeDomain(destination.Address.IP()); found {
+			log.Trace(newError("restored domain ", domain, " from fake IP ", destination.Address).AtDebug())
+			destination.Address = net.DomainAddress(domain)
+		}
+	}
 	ctx = proxy.ContextWithTarget(ctx, destination)
 
-	outbound := ray.NewRay(ctx)
+	user := protocol.UserFromContext(ctx)
+	outbound := ray.NewRay(ctx, d.rayOptions(user)...)
+	if user != nil && len(user.Email) > 0 {
+		d.countTraffic(outbound, stats.UserTrafficCounterName(user.Email, true), stats.UserTrafficCounterName(user.Email, false))
+	}
+	if tag, ok := proxy.InboundTagFromContext(ctx); ok && len(tag) > 0 {
+		d.countTraffic(outbound, stats.InboundTrafficCounterName(tag, true), stats.InboundTrafficCounterName(tag, false))
+	}
 	sniferList := proxyman.ProtocoSniffersFromContext(ctx)
 	if destination.Address.Family().IsDomain() || len(sniferList) == 0 {
 		go d.routedDispatch(ctx, outbound, destination)
@@ -88,6 +123,39 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 	return outbound, nil
 }
 
+// rayOptions returns the options of the Ray for the given user, based on the policy of the user level.
+func (d *DefaultDispatcher) rayOptions(user *protocol.User) []ray.Option {
+	if d.policy == nil {
+		return nil
+	}
+	var level uint32
+	if user != nil {
+		level = user.Level
+	}
+	size := d.policy.GetPolicy(level).GetBuffer().GetConnection()
+	switch {
+	case size > 0:
+		return []ray.Option{ray.WithBufferSize(uint64(size))}
+	case size < 0:
+		return []ray.Option{ray.WithBufferSize(0)}
+	default:
+		return nil
+	}
+}
+
+// countTraffic attaches the named uplink and downlink counters to the given ray, if stats are enabled.
+func (d *DefaultDispatcher) countTraffic(r ray.OutboundRay, uplink string, downlink string) {
+	if d.stats == nil {
+		return
+	}
+	if c, err := stats.GetOrRegisterCounter(d.stats, uplink); err == nil {
+		r.OutboundInput().AddCounter(c)
+	}
+	if c, err := stats.GetOrRegisterCounter(d.stats, downlink); err == nil {
+		r.OutboundOutput().AddCounter(c)
+	}
+}
+
 func snifer(ctx context.Context, sniferList []proxyman.KnownProtocols, outbound ray.OutboundRay) (string, error) {
 	payload := buf.New()
 	defer payload.Release()
@@ -120,7 +188,12 @@ func snifer(ctx context.Context, sniferList []proxyman.KnownProtocols, outbound
 
 func (d *DefaultDispatcher) routedDispatch(ctx context.Context, outbound ray.OutboundRay, destination net.Destination) {
 	dispatcher := d.ohm.GetDefaultHandler()
-	if d.router != nil {
+	if tag, ok := proxy.OutboundTagFromContext(ctx); ok {
+		dispatcher = d.ohm.GetHandler(tag)
+		if dispatcher == nil {
+			log.Trace(newError("nonexisting tag: ", tag).AtWarning())
+		}
+	} else if d.router != nil {
 		if tag, err := d.router.TakeDetour(ctx); err == nil {
 			if handler := d.ohm.GetHandler(tag); handler != nil {
 				log.Trace(newError("taking detour [", tag, "] for [", destination, "]"))
@@ -132,6 +205,15 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, outbound ray.Out
 			log.Trace(newError("default route for ", destination))
 		}
 	}
+	if dispatcher == nil {
+		log.Trace(newError("no outbound handler available for ", destination).AtWarning())
+		outbound.OutboundInput().CloseError()
+		outbound.OutboundOutput().CloseError()
+		return
+	}
+	if tag := dispatcher.Tag(); len(tag) > 0 {
+		d.countTraffic(outbound, stats.OutboundTrafficCounterName(tag, true), stats.OutboundTrafficCounterName(tag, false))
+	}
 	dispatcher.Dispatch(ctx, outbound)
 }
 
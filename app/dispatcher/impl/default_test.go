@@ -0,0 +1,38 @@
+package impl_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	_ "v2ray.com/core/app/dispatcher/impl"
+	"v2ray.com/core/app/dns"
+	_ "v2ray.com/core/app/dns/server"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestDispatcherDependencies(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	for _, config := range []interface{}{
+		new(dispatcher.Config),
+		new(dns.Config),
+		new(router.Config),
+		new(policy.Config),
+		new(proxyman.InboundConfig),
+		new(proxyman.OutboundConfig),
+	} {
+		assert.Error(app.AddApplicationToSpace(ctx, config)).IsNil()
+	}
+	assert.Error(space.Initialize()).IsNil()
+	assert.Error(space.Start()).IsNil()
+	space.Close()
+}
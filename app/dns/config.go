@@ -2,19 +2,45 @@ package dns
 
 import (
 	"net"
-
-	"v2ray.com/core/app/log"
+	"time"
 )
 
-func (c *Config) GetInternalHosts() map[string]net.IP {
-	hosts := make(map[string]net.IP)
-	for domain, ipOrDomain := range c.GetHosts() {
-		address := ipOrDomain.AsAddress()
-		if address.Family().IsDomain() {
-			log.Trace(newError("ignoring domain address in static hosts: ", address.Domain()).AtWarning())
-			continue
+// GetCacheSizeValue returns the maximum number of domains in the cache, or the default value if not set.
+func (c *Config) GetCacheSizeValue() int {
+	if c.CacheSize == 0 {
+		return 4096
+	}
+	return int(c.CacheSize)
+}
+
+// GetNegativeTTLValue returns the duration to cache NXDOMAIN and empty answers for, or the default value if not set.
+func (c *Config) GetNegativeTTLValue() time.Duration {
+	if c.NegativeTtl == 0 {
+		return time.Second * 60
+	}
+	return time.Second * time.Duration(c.NegativeTtl)
+}
+
+// GetIPPoolValue returns the pool of fake IPs, or the default pool if not set.
+func (c *FakeDns) GetIPPoolValue() *net.IPNet {
+	if c.IpPool == nil || len(c.IpPool.Ip) == 0 {
+		return &net.IPNet{
+			IP:   net.IP{198, 18, 0, 0},
+			Mask: net.CIDRMask(15, 32),
 		}
-		hosts[domain] = address.IP()
 	}
-	return hosts
+	ip := net.IP(c.IpPool.Ip)
+	mask := net.CIDRMask(int(c.IpPool.Prefix), len(ip)*8)
+	return &net.IPNet{
+		IP:   ip.Mask(mask),
+		Mask: mask,
+	}
+}
+
+// GetPoolSizeValue returns the maximum number of domains that have fake IPs, or the default value if not set.
+func (c *FakeDns) GetPoolSizeValue() int {
+	if c.PoolSize == 0 {
+		return 65535
+	}
+	return int(c.PoolSize)
 }
@@ -4,7 +4,8 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import v2ray_core_common_net "v2ray.com/core/common/net"
-import v2ray_core_common_net2 "v2ray.com/core/common/net"
+import v2ray_core_common_net1 "v2ray.com/core/common/net"
+import v2ray_core_app_router "v2ray.com/core/app/router"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -17,20 +18,263 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type QueryStrategy int32
+
+const (
+	// Query A records only.
+	QueryStrategy_USE_IP4 QueryStrategy = 0
+	// Query AAAA records only.
+	QueryStrategy_USE_IP6 QueryStrategy = 1
+	// Query both A and AAAA records, and return IPv4 addresses if there are any.
+	QueryStrategy_PREFER_IP4 QueryStrategy = 2
+	// Query both A and AAAA records, and return IPv6 addresses if there are any.
+	QueryStrategy_PREFER_IP6 QueryStrategy = 3
+)
+
+var QueryStrategy_name = map[int32]string{
+	0: "USE_IP4",
+	1: "USE_IP6",
+	2: "PREFER_IP4",
+	3: "PREFER_IP6",
+}
+var QueryStrategy_value = map[string]int32{
+	"USE_IP4":    0,
+	"USE_IP6":    1,
+	"PREFER_IP4": 2,
+	"PREFER_IP6": 3,
+}
+
+func (x QueryStrategy) String() string {
+	return proto.EnumName(QueryStrategy_name, int32(x))
+}
+func (QueryStrategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type NameServer_Protocol int32
+
+const (
+	// Traditional DNS over UDP or TCP, depending on the network of the address. Default to UDP.
+	NameServer_Plain NameServer_Protocol = 0
+	// DNS over TLS, as in RFC 7858. Default port is 853.
+	NameServer_TLS NameServer_Protocol = 1
+	// DNS over HTTPS, as in RFC 8484.
+	NameServer_HTTPS NameServer_Protocol = 2
+)
+
+var NameServer_Protocol_name = map[int32]string{
+	0: "Plain",
+	1: "TLS",
+	2: "HTTPS",
+}
+var NameServer_Protocol_value = map[string]int32{
+	"Plain": 0,
+	"TLS":   1,
+	"HTTPS": 2,
+}
+
+func (x NameServer_Protocol) String() string {
+	return proto.EnumName(NameServer_Protocol_name, int32(x))
+}
+func (NameServer_Protocol) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
+
+// HostMapping maps domains matching a pattern to static IPs, or to another domain.
+type HostMapping struct {
+	Type   v2ray_core_app_router.Domain_Type `protobuf:"varint,1,opt,name=type,enum=v2ray.core.app.router.Domain_Type" json:"type,omitempty"`
+	Domain string                            `protobuf:"bytes,2,opt,name=domain" json:"domain,omitempty"`
+	// IPs of the domain, either 4 or 16 bytes each.
+	Ip [][]byte `protobuf:"bytes,3,rep,name=ip,proto3" json:"ip,omitempty"`
+	// If set, the domain is an alias of this domain, which is resolved instead. ip is ignored.
+	ProxiedDomain string `protobuf:"bytes,4,opt,name=proxied_domain,json=proxiedDomain" json:"proxied_domain,omitempty"`
+}
+
+func (m *HostMapping) Reset()                    { *m = HostMapping{} }
+func (m *HostMapping) String() string            { return proto.CompactTextString(m) }
+func (*HostMapping) ProtoMessage()               {}
+func (*HostMapping) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *HostMapping) GetType() v2ray_core_app_router.Domain_Type {
+	if m != nil {
+		return m.Type
+	}
+	return v2ray_core_app_router.Domain_Plain
+}
+
+func (m *HostMapping) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *HostMapping) GetIp() [][]byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *HostMapping) GetProxiedDomain() string {
+	if m != nil {
+		return m.ProxiedDomain
+	}
+	return ""
+}
+
+type NameServer struct {
+	// Address of the server. For DNS over HTTPS, it is optional and overrides the host in url when connecting.
+	Address  *v2ray_core_common_net1.Endpoint `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Protocol NameServer_Protocol              `protobuf:"varint,2,opt,name=protocol,enum=v2ray.core.app.dns.NameServer_Protocol" json:"protocol,omitempty"`
+	// Server name to verify the certificate of the server. Default to the address of the server.
+	ServerName string `protobuf:"bytes,3,opt,name=server_name,json=serverName" json:"server_name,omitempty"`
+	// URL of DNS over HTTPS service, e.g., "https://dns.example.com/dns-query".
+	Url string `protobuf:"bytes,4,opt,name=url" json:"url,omitempty"`
+	// Domains that this server is preferred for. Matching servers are queried before others.
+	PrioritizedDomain []*v2ray_core_app_router.Domain `protobuf:"bytes,5,rep,name=prioritized_domain,json=prioritizedDomain" json:"prioritized_domain,omitempty"`
+	// IP ranges that answers of this server are expected in. IPs out of them are discarded, and the next server is
+	// queried if no IP is left. Empty means all IPs are accepted.
+	ExpectedIp []*v2ray_core_app_router.CIDR `protobuf:"bytes,6,rep,name=expected_ip,json=expectedIp" json:"expected_ip,omitempty"`
+	// Client subnet sent to this server in EDNS0, so that it answers with IPs close to the client. It is ignored by
+	// the local server.
+	ClientSubnet *v2ray_core_app_router.CIDR `protobuf:"bytes,7,opt,name=client_subnet,json=clientSubnet" json:"client_subnet,omitempty"`
+	// If true, the subnet of the requesting client is sent instead of client_subnet, when the client has a public IP.
+	// The subnet is /24 for IPv4 and /56 for IPv6.
+	ClientSubnetFromSource bool `protobuf:"varint,8,opt,name=client_subnet_from_source,json=clientSubnetFromSource" json:"client_subnet_from_source,omitempty"`
+}
+
+func (m *NameServer) Reset()                    { *m = NameServer{} }
+func (m *NameServer) String() string            { return proto.CompactTextString(m) }
+func (*NameServer) ProtoMessage()               {}
+func (*NameServer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *NameServer) GetAddress() *v2ray_core_common_net1.Endpoint {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *NameServer) GetProtocol() NameServer_Protocol {
+	if m != nil {
+		return m.Protocol
+	}
+	return NameServer_Plain
+}
+
+func (m *NameServer) GetServerName() string {
+	if m != nil {
+		return m.ServerName
+	}
+	return ""
+}
+
+func (m *NameServer) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *NameServer) GetPrioritizedDomain() []*v2ray_core_app_router.Domain {
+	if m != nil {
+		return m.PrioritizedDomain
+	}
+	return nil
+}
+
+func (m *NameServer) GetExpectedIp() []*v2ray_core_app_router.CIDR {
+	if m != nil {
+		return m.ExpectedIp
+	}
+	return nil
+}
+
+func (m *NameServer) GetClientSubnet() *v2ray_core_app_router.CIDR {
+	if m != nil {
+		return m.ClientSubnet
+	}
+	return nil
+}
+
+func (m *NameServer) GetClientSubnetFromSource() bool {
+	if m != nil {
+		return m.ClientSubnetFromSource
+	}
+	return false
+}
+
+// FakeDns answers queries with fake IPs from a reserved pool, and remembers the domains they are allocated to.
+type FakeDns struct {
+	// Pool of fake IPs. Default to 198.18.0.0/15.
+	IpPool *v2ray_core_app_router.CIDR `protobuf:"bytes,1,opt,name=ip_pool,json=ipPool" json:"ip_pool,omitempty"`
+	// Maximum number of domains that have fake IPs. The least recently used one is evicted when it is full.
+	// Default to 65535, and it is limited by the size of the pool.
+	PoolSize uint32 `protobuf:"varint,2,opt,name=pool_size,json=poolSize" json:"pool_size,omitempty"`
+	// File to save the fake IPs to on exit, and load them from on start. Fake IPs are not saved if empty.
+	PersistenceFile string `protobuf:"bytes,3,opt,name=persistence_file,json=persistenceFile" json:"persistence_file,omitempty"`
+}
+
+func (m *FakeDns) Reset()                    { *m = FakeDns{} }
+func (m *FakeDns) String() string            { return proto.CompactTextString(m) }
+func (*FakeDns) ProtoMessage()               {}
+func (*FakeDns) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *FakeDns) GetIpPool() *v2ray_core_app_router.CIDR {
+	if m != nil {
+		return m.IpPool
+	}
+	return nil
+}
+
+func (m *FakeDns) GetPoolSize() uint32 {
+	if m != nil {
+		return m.PoolSize
+	}
+	return 0
+}
+
+func (m *FakeDns) GetPersistenceFile() string {
+	if m != nil {
+		return m.PersistenceFile
+	}
+	return ""
+}
+
 type Config struct {
-	// Nameservers used by this DNS. Only traditional UDP servers are support at the moment.
+	// Nameservers used by this DNS. Both UDP and TCP servers are supported.
 	// A special value 'localhost' as a domain address can be set to use DNS on local system.
-	NameServers []*v2ray_core_common_net2.Endpoint `protobuf:"bytes,1,rep,name=NameServers" json:"NameServers,omitempty"`
-	// Static hosts. Domain to IP.
+	NameServers []*v2ray_core_common_net1.Endpoint `protobuf:"bytes,1,rep,name=NameServers" json:"NameServers,omitempty"`
+	// Static hosts. Domain to IP, or to another domain as an alias.
 	Hosts map[string]*v2ray_core_common_net.IPOrDomain `protobuf:"bytes,2,rep,name=Hosts" json:"Hosts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Nameservers with detailed settings. They are used after the ones in NameServers.
+	NameServer []*NameServer `protobuf:"bytes,3,rep,name=name_server,json=nameServer" json:"name_server,omitempty"`
+	// Strategy of resolving IP addresses of domains.
+	QueryStrategy QueryStrategy `protobuf:"varint,4,opt,name=query_strategy,json=queryStrategy,enum=v2ray.core.app.dns.QueryStrategy" json:"query_strategy,omitempty"`
+	// Maximum number of domains in the cache. The least recently used domain is evicted when the cache is full.
+	// Default to 4096.
+	CacheSize uint32 `protobuf:"varint,5,opt,name=cache_size,json=cacheSize" json:"cache_size,omitempty"`
+	// Seconds to cache NXDOMAIN and empty answers for. Default to 60. Failed lookups, e.g., timeouts, are not cached.
+	NegativeTtl uint32 `protobuf:"varint,6,opt,name=negative_ttl,json=negativeTtl" json:"negative_ttl,omitempty"`
+	// Whether to return expired records, while refreshing them in background.
+	ServeStale bool `protobuf:"varint,7,opt,name=serve_stale,json=serveStale" json:"serve_stale,omitempty"`
+	// Whether to refresh frequently used records in background, shortly before they expire.
+	Prefetch bool `protobuf:"varint,8,opt,name=prefetch" json:"prefetch,omitempty"`
+	// Number of name servers to query in parallel, and the first valid answer is used. Name servers out of the first
+	// ones are queried one by one, if all of them fail. 0 or 1 means to query name servers one by one.
+	Concurrency uint32 `protobuf:"varint,9,opt,name=concurrency" json:"concurrency,omitempty"`
+	// Fake DNS settings. Fake DNS is disabled if not set.
+	FakeDns *FakeDns `protobuf:"bytes,10,opt,name=fake_dns,json=fakeDns" json:"fake_dns,omitempty"`
+	// Static hosts with domain patterns. Exact matches take precedence, then the longest matching domains,
+	// then keywords and regular expressions in order.
+	StaticHosts []*HostMapping `protobuf:"bytes,11,rep,name=static_hosts,json=staticHosts" json:"static_hosts,omitempty"`
+	// Files in /etc/hosts format to load static hosts from.
+	HostsFile []string `protobuf:"bytes,12,rep,name=hosts_file,json=hostsFile" json:"hosts_file,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
-func (m *Config) GetNameServers() []*v2ray_core_common_net2.Endpoint {
+func (m *Config) GetNameServers() []*v2ray_core_common_net1.Endpoint {
 	if m != nil {
 		return m.NameServers
 	}
@@ -44,30 +288,143 @@ func (m *Config) GetHosts() map[string]*v2ray_core_common_net.IPOrDomain {
 	return nil
 }
 
+func (m *Config) GetNameServer() []*NameServer {
+	if m != nil {
+		return m.NameServer
+	}
+	return nil
+}
+
+func (m *Config) GetQueryStrategy() QueryStrategy {
+	if m != nil {
+		return m.QueryStrategy
+	}
+	return QueryStrategy_USE_IP4
+}
+
+func (m *Config) GetCacheSize() uint32 {
+	if m != nil {
+		return m.CacheSize
+	}
+	return 0
+}
+
+func (m *Config) GetNegativeTtl() uint32 {
+	if m != nil {
+		return m.NegativeTtl
+	}
+	return 0
+}
+
+func (m *Config) GetServeStale() bool {
+	if m != nil {
+		return m.ServeStale
+	}
+	return false
+}
+
+func (m *Config) GetPrefetch() bool {
+	if m != nil {
+		return m.Prefetch
+	}
+	return false
+}
+
+func (m *Config) GetConcurrency() uint32 {
+	if m != nil {
+		return m.Concurrency
+	}
+	return 0
+}
+
+func (m *Config) GetFakeDns() *FakeDns {
+	if m != nil {
+		return m.FakeDns
+	}
+	return nil
+}
+
+func (m *Config) GetStaticHosts() []*HostMapping {
+	if m != nil {
+		return m.StaticHosts
+	}
+	return nil
+}
+
+func (m *Config) GetHostsFile() []string {
+	if m != nil {
+		return m.HostsFile
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterType((*HostMapping)(nil), "v2ray.core.app.dns.HostMapping")
+	proto.RegisterType((*NameServer)(nil), "v2ray.core.app.dns.NameServer")
+	proto.RegisterType((*FakeDns)(nil), "v2ray.core.app.dns.FakeDns")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.dns.Config")
+	proto.RegisterEnum("v2ray.core.app.dns.QueryStrategy", QueryStrategy_name, QueryStrategy_value)
+	proto.RegisterEnum("v2ray.core.app.dns.NameServer_Protocol", NameServer_Protocol_name, NameServer_Protocol_value)
 }
 
 func init() { proto.RegisterFile("v2ray.com/core/app/dns/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 286 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0x41, 0x4b, 0x33, 0x31,
-	0x10, 0x86, 0xc9, 0x96, 0x16, 0xbe, 0xf4, 0xf2, 0x91, 0x83, 0x2c, 0xbd, 0x58, 0x15, 0xb1, 0x20,
-	0x4c, 0x60, 0x15, 0x14, 0x3d, 0xd5, 0xb6, 0xa0, 0x17, 0x2d, 0x2b, 0x78, 0xd0, 0x53, 0xdc, 0x44,
-	0x59, 0x34, 0x33, 0x21, 0x89, 0x0b, 0xfb, 0x97, 0xfc, 0x5f, 0xfe, 0x0f, 0xe9, 0x06, 0xb1, 0xa8,
-	0xbd, 0x85, 0xe4, 0x79, 0xf2, 0xbe, 0x33, 0x7c, 0xaf, 0x29, 0xbc, 0x6a, 0xa1, 0x22, 0x2b, 0x2b,
-	0xf2, 0x46, 0x2a, 0xe7, 0xa4, 0xc6, 0x20, 0x2b, 0xc2, 0xa7, 0xfa, 0x19, 0x9c, 0xa7, 0x48, 0x42,
-	0x7c, 0x41, 0xde, 0x80, 0x72, 0x0e, 0x34, 0x86, 0xd1, 0xc1, 0x0f, 0xb1, 0x22, 0x6b, 0x09, 0x25,
-	0x9a, 0x28, 0x95, 0xd6, 0xde, 0x84, 0x90, 0xe4, 0xd1, 0xe1, 0x66, 0x50, 0x9b, 0x10, 0x6b, 0x54,
-	0xb1, 0x26, 0x4c, 0xf0, 0xee, 0x07, 0xe3, 0x83, 0x59, 0x17, 0x2d, 0xa6, 0x7c, 0x78, 0xad, 0xac,
-	0xb9, 0x35, 0xbe, 0x31, 0x3e, 0xe4, 0x6c, 0xdc, 0x9b, 0x0c, 0x8b, 0x6d, 0x58, 0xab, 0x92, 0x7e,
-	0x02, 0x34, 0x11, 0x16, 0xa8, 0x1d, 0xd5, 0x18, 0xcb, 0x75, 0x47, 0x9c, 0xf3, 0xfe, 0x25, 0x85,
-	0x18, 0xf2, 0xac, 0x93, 0xf7, 0xe1, 0xf7, 0x1c, 0x90, 0xd2, 0xa0, 0xe3, 0x16, 0x18, 0x7d, 0x5b,
-	0x26, 0x67, 0xf4, 0xc0, 0xf9, 0xf7, 0xa5, 0xf8, 0xcf, 0x7b, 0x2f, 0xa6, 0xcd, 0xd9, 0x98, 0x4d,
-	0xfe, 0x95, 0xab, 0xa3, 0x38, 0xe1, 0xfd, 0x46, 0xbd, 0xbe, 0x99, 0x3c, 0x1b, 0xb3, 0xc9, 0xb0,
-	0xd8, 0xd9, 0xd0, 0xec, 0x6a, 0x79, 0xe3, 0xe7, 0x64, 0x55, 0x8d, 0x65, 0xe2, 0xcf, 0xb2, 0x53,
-	0x76, 0x71, 0xcc, 0xb7, 0x2a, 0xb2, 0x7f, 0xf4, 0x59, 0xb2, 0xfb, 0x9e, 0xc6, 0xf0, 0x9e, 0x89,
-	0xbb, 0xa2, 0x54, 0x2d, 0xcc, 0x56, 0x6f, 0x53, 0xe7, 0x60, 0x8e, 0xe1, 0x71, 0xd0, 0x2d, 0xe9,
-	0xe8, 0x33, 0x00, 0x00, 0xff, 0xff, 0x2d, 0xe1, 0x96, 0x19, 0xb5, 0x01, 0x00, 0x00,
+	// 881 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xee, 0x7a, 0xeb, 0xbf, 0xb3, 0x89, 0x31, 0x73, 0x11, 0x2d, 0xa9, 0x42, 0x1d, 0xa3, 0x52,
+	0x17, 0xa4, 0xb5, 0x64, 0xa2, 0x40, 0x01, 0x09, 0xda, 0xfc, 0x28, 0x96, 0x0a, 0x98, 0xb1, 0xe1,
+	0x02, 0x2e, 0x56, 0xd3, 0xdd, 0xe3, 0x64, 0x94, 0xf5, 0xcc, 0x74, 0x66, 0x1c, 0xd5, 0x79, 0x01,
+	0x5e, 0x80, 0xa7, 0x40, 0xbc, 0x1d, 0x2f, 0x80, 0x76, 0x76, 0x1d, 0x3b, 0xad, 0xd3, 0xf6, 0x6e,
+	0xe6, 0x9b, 0xef, 0x3b, 0x73, 0xe6, 0x9c, 0xef, 0x0c, 0x7c, 0x76, 0x35, 0xd0, 0x6c, 0x11, 0x25,
+	0x72, 0xd6, 0x4f, 0xa4, 0xc6, 0x3e, 0x53, 0xaa, 0x9f, 0x0a, 0xd3, 0x4f, 0xa4, 0x98, 0xf2, 0xf3,
+	0x48, 0x69, 0x69, 0x25, 0x21, 0x4b, 0x92, 0xc6, 0x88, 0x29, 0x15, 0xa5, 0xc2, 0xec, 0x3e, 0x7e,
+	0x43, 0x98, 0xc8, 0xd9, 0x4c, 0x8a, 0xbe, 0x40, 0xdb, 0x67, 0x69, 0xaa, 0xd1, 0x98, 0x42, 0xbc,
+	0xfb, 0xe5, 0xdd, 0xc4, 0x14, 0x8d, 0xe5, 0x82, 0x59, 0x2e, 0x45, 0x49, 0xfe, 0x7c, 0x43, 0x3a,
+	0x5a, 0xce, 0x2d, 0xea, 0x5b, 0x19, 0x75, 0xff, 0xf6, 0x20, 0x38, 0x93, 0xc6, 0xfe, 0xc4, 0x94,
+	0xe2, 0xe2, 0x9c, 0x1c, 0xc2, 0x7d, 0xbb, 0x50, 0x18, 0x7a, 0x1d, 0xaf, 0xd7, 0x1a, 0x74, 0xa3,
+	0x37, 0x12, 0x2e, 0x42, 0x44, 0xc7, 0x72, 0xc6, 0xb8, 0x88, 0x26, 0x0b, 0x85, 0xd4, 0xf1, 0xc9,
+	0x0e, 0xd4, 0x52, 0x07, 0x86, 0x95, 0x8e, 0xd7, 0x6b, 0xd2, 0x72, 0x47, 0x5a, 0x50, 0xe1, 0x2a,
+	0xf4, 0x3b, 0x7e, 0x6f, 0x8b, 0x56, 0xb8, 0x22, 0x8f, 0xa0, 0xa5, 0xb4, 0x7c, 0xcd, 0x31, 0x8d,
+	0x4b, 0xfe, 0x7d, 0xc7, 0xdf, 0x2e, 0xd1, 0x22, 0x72, 0xf7, 0x3f, 0x1f, 0xe0, 0x67, 0x36, 0xc3,
+	0x31, 0xea, 0x2b, 0xd4, 0xe4, 0x29, 0xd4, 0xcb, 0x5a, 0xb8, 0xc4, 0x82, 0xc1, 0xc3, 0xf5, 0xc4,
+	0x8a, 0x42, 0x44, 0x02, 0x6d, 0x74, 0x22, 0x52, 0x25, 0xb9, 0xb0, 0x74, 0xc9, 0x27, 0x47, 0xd0,
+	0x70, 0x2f, 0x4d, 0x64, 0xe6, 0x52, 0x6b, 0x0d, 0x1e, 0x47, 0x6f, 0x77, 0x21, 0x5a, 0x5d, 0x16,
+	0x8d, 0x4a, 0x3a, 0xbd, 0x11, 0x92, 0x87, 0x10, 0x18, 0x77, 0x18, 0x0b, 0x36, 0xc3, 0xd0, 0x77,
+	0x29, 0x43, 0x01, 0xe5, 0x4a, 0xd2, 0x06, 0x7f, 0xae, 0xb3, 0xf2, 0x2d, 0xf9, 0x92, 0xbc, 0x00,
+	0xa2, 0x34, 0x97, 0x9a, 0x5b, 0x7e, 0xbd, 0x7a, 0x6c, 0xb5, 0xe3, 0xf7, 0x82, 0xc1, 0xde, 0x3b,
+	0xcb, 0x4a, 0x3f, 0x5e, 0x13, 0x16, 0x10, 0xf9, 0x1e, 0x02, 0x7c, 0xad, 0x30, 0xb1, 0x98, 0xc6,
+	0x5c, 0x85, 0x35, 0x17, 0xe6, 0xc1, 0x1d, 0x61, 0x8e, 0x86, 0xc7, 0x94, 0xc2, 0x92, 0x3f, 0x54,
+	0xe4, 0x47, 0xd8, 0x4e, 0x32, 0x8e, 0xc2, 0xc6, 0x66, 0xfe, 0x52, 0xa0, 0x0d, 0xeb, 0x1d, 0xef,
+	0x7d, 0xfa, 0xad, 0x42, 0x31, 0x76, 0x02, 0xf2, 0x14, 0x3e, 0xb9, 0x15, 0x21, 0x9e, 0x6a, 0x39,
+	0x8b, 0x8d, 0x9c, 0xeb, 0x04, 0xc3, 0x46, 0xc7, 0xeb, 0x35, 0xe8, 0xce, 0xba, 0xe0, 0x54, 0xcb,
+	0xd9, 0xd8, 0x9d, 0x76, 0x9f, 0x40, 0x63, 0x59, 0x51, 0xd2, 0x84, 0xea, 0x28, 0x63, 0x5c, 0xb4,
+	0xef, 0x91, 0x3a, 0xf8, 0x93, 0x17, 0xe3, 0xb6, 0x97, 0x63, 0x67, 0x93, 0xc9, 0x68, 0xdc, 0xae,
+	0x74, 0xff, 0xf2, 0xa0, 0x7e, 0xca, 0x2e, 0xf1, 0x58, 0x18, 0x72, 0x00, 0x75, 0xae, 0x62, 0x25,
+	0x65, 0x16, 0x7a, 0xef, 0xcf, 0xb6, 0xc6, 0xd5, 0x48, 0xca, 0x8c, 0x3c, 0x80, 0x66, 0x2e, 0x89,
+	0x0d, 0xbf, 0x46, 0xd7, 0xee, 0x6d, 0xda, 0xc8, 0x81, 0x31, 0xbf, 0x46, 0xf2, 0x04, 0xda, 0x0a,
+	0xb5, 0xe1, 0xc6, 0xa2, 0x48, 0x30, 0x9e, 0xf2, 0x6c, 0xd9, 0xca, 0x8f, 0xd6, 0xf0, 0x53, 0x9e,
+	0x61, 0xf7, 0xdf, 0x2a, 0xd4, 0x8e, 0xdc, 0x9c, 0x90, 0x67, 0x10, 0xac, 0xcc, 0x91, 0xfb, 0xcf,
+	0xff, 0x10, 0xff, 0xad, 0x6b, 0xc8, 0x77, 0x50, 0xcd, 0x67, 0xcc, 0x84, 0x15, 0x27, 0x7e, 0xb4,
+	0xc9, 0x80, 0xc5, 0x6d, 0x91, 0xe3, 0x9d, 0x08, 0xab, 0x17, 0xb4, 0xd0, 0x90, 0x1f, 0x20, 0xc8,
+	0x4d, 0x17, 0x17, 0x6e, 0x73, 0xa3, 0x14, 0x0c, 0x3e, 0x7d, 0xb7, 0x87, 0x29, 0x88, 0x9b, 0x35,
+	0x39, 0x83, 0xd6, 0xab, 0x39, 0xea, 0x45, 0x6c, 0xac, 0x66, 0x16, 0xcf, 0x17, 0xce, 0xa6, 0xad,
+	0xc1, 0xfe, 0xa6, 0x18, 0xbf, 0xe6, 0xcc, 0x71, 0x49, 0xa4, 0xdb, 0xaf, 0xd6, 0xb7, 0x64, 0x0f,
+	0x20, 0x61, 0xc9, 0x05, 0x16, 0xe5, 0xad, 0xba, 0xf2, 0x36, 0x1d, 0xe2, 0xea, 0xbb, 0x0f, 0x5b,
+	0x02, 0xcf, 0x99, 0xe5, 0x57, 0x18, 0x5b, 0x9b, 0x85, 0x35, 0x47, 0x08, 0x96, 0xd8, 0xc4, 0xae,
+	0x06, 0x29, 0x36, 0x96, 0x65, 0xe8, 0x7c, 0xd8, 0x28, 0x07, 0x69, 0x9c, 0x23, 0x64, 0x37, 0x1f,
+	0x57, 0x9c, 0xa2, 0x4d, 0x2e, 0x4a, 0x5f, 0xdd, 0xec, 0x49, 0x07, 0x82, 0x44, 0x8a, 0x64, 0xae,
+	0x35, 0x8a, 0x64, 0x11, 0x36, 0x8b, 0xf0, 0x6b, 0x10, 0x39, 0x84, 0xc6, 0x94, 0x5d, 0x62, 0x9c,
+	0x0a, 0x13, 0xc2, 0x66, 0xd7, 0xe4, 0x8f, 0x2c, 0x3d, 0x46, 0xeb, 0xd3, 0x62, 0x41, 0x9e, 0xc3,
+	0x96, 0xb1, 0xcc, 0xf2, 0x24, 0xbe, 0x70, 0x7d, 0x0a, 0xde, 0x6e, 0xf2, 0x52, 0xbb, 0xf6, 0x59,
+	0xd2, 0xa0, 0x10, 0x15, 0x7d, 0xda, 0x03, 0x70, 0xe2, 0xc2, 0x57, 0x5b, 0x1d, 0xbf, 0xd7, 0xa4,
+	0x4d, 0x87, 0xe4, 0x8e, 0xda, 0xfd, 0x13, 0x60, 0xd5, 0xdb, 0xfc, 0xbf, 0xb8, 0xc4, 0x85, 0x73,
+	0x76, 0x93, 0xe6, 0x4b, 0xf2, 0x35, 0x54, 0xaf, 0x58, 0x36, 0x2f, 0x5c, 0x1b, 0x0c, 0xf6, 0xef,
+	0x30, 0xd8, 0x70, 0xf4, 0x8b, 0x2e, 0xbf, 0x89, 0x82, 0xff, 0x6d, 0xe5, 0x1b, 0xef, 0x8b, 0x21,
+	0x6c, 0xdf, 0x6a, 0x1c, 0x09, 0xa0, 0xfe, 0xdb, 0xf8, 0x24, 0x1e, 0x8e, 0x0e, 0xda, 0xf7, 0x56,
+	0x9b, 0xc3, 0xb6, 0x47, 0x5a, 0x00, 0x23, 0x7a, 0x72, 0x7a, 0x42, 0xdd, 0x61, 0xe5, 0xd6, 0xfe,
+	0xb0, 0xed, 0x3f, 0x3f, 0x80, 0x9d, 0x44, 0xce, 0x36, 0xbc, 0x7c, 0xe4, 0xfd, 0xe1, 0xa7, 0xc2,
+	0xfc, 0x53, 0x21, 0xbf, 0x0f, 0x28, 0x5b, 0x44, 0x47, 0xf9, 0xd9, 0x33, 0xa5, 0xa2, 0x63, 0x61,
+	0x5e, 0xd6, 0xdc, 0x57, 0xf9, 0xd5, 0xff, 0x03, 0x00, 0xab, 0x6a, 0xcf, 0x65, 0x06, 0x07, 0x00,
+	0x00,
 }
@@ -0,0 +1,7 @@
+package resolver
+
+import "v2ray.com/core/common/errors"
+
+func newError(values ...interface{}) *errors.Error {
+	return errors.New(values...).Path("App", "DNS", "Resolver")
+}
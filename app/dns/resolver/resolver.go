@@ -0,0 +1,92 @@
+// Package resolver defines the interfaces of the DNS app. It is separated from the DNS config, so that apps used by
+// the DNS app, such as the router, can depend on it.
+package resolver
+
+//go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg resolver -path App,DNS,Resolver
+
+import (
+	"context"
+	"net"
+
+	"v2ray.com/core/app"
+)
+
+// A Server is a DNS server for responding DNS queries.
+type Server interface {
+	// Get returns IPs of the given domain. Whether IPv4 or IPv6 addresses are returned depends on the implementation.
+	Get(domain string) []net.IP
+}
+
+// ContextServer is a Server that resolves domains on behalf of the client in the context, e.g., to send the
+// subnet of the client to name servers.
+type ContextServer interface {
+	// GetWithContext returns IPs of the given domain, like Server.Get.
+	GetWithContext(ctx context.Context, domain string) []net.IP
+}
+
+// LookupIP returns IPs of the given domain with the given server, on behalf of the client in the context if the
+// server supports it.
+func LookupIP(ctx context.Context, server Server, domain string) []net.IP {
+	if s, ok := server.(ContextServer); ok {
+		return s.GetWithContext(ctx, domain)
+	}
+	return server.Get(domain)
+}
+
+// ErrNotFound is returned by a ResolveServer when name servers answer that the domain has no IP.
+var ErrNotFound = newError("domain not found")
+
+// ResolveServer is a Server that tells why a domain is not resolved.
+type ResolveServer interface {
+	// Resolve returns IPs of the given domain on behalf of the client in the context. The error is ErrNotFound if
+	// name servers answer without IP, or another error if no name server answers.
+	Resolve(ctx context.Context, domain string) ([]net.IP, error)
+}
+
+// Resolve returns IPs of the given domain with the given server, like LookupIP. If the server is not a ResolveServer,
+// ErrNotFound is returned when it returns no IP.
+func Resolve(ctx context.Context, server Server, domain string) ([]net.IP, error) {
+	if s, ok := server.(ResolveServer); ok {
+		return s.Resolve(ctx, domain)
+	}
+	ips := LookupIP(ctx, server, domain)
+	if len(ips) == 0 {
+		return nil, ErrNotFound
+	}
+	return ips, nil
+}
+
+// CacheStats is the status of the cache of a Server.
+type CacheStats struct {
+	// Size is the number of domains in the cache.
+	Size int
+	// Hits is the number of queries answered by the cache.
+	Hits uint64
+	// Misses is the number of queries sent to name servers.
+	Misses uint64
+}
+
+// CachedServer is a Server that caches answers of name servers.
+type CachedServer interface {
+	// CacheStats returns the status of the cache.
+	CacheStats() CacheStats
+}
+
+// FakeDNS answers queries with fake IPs from a reserved pool, and maps them back to domains.
+type FakeDNS interface {
+	// GetFakeIP returns the fake IP of the given domain, allocating one if necessary. It returns nil if the domain
+	// should not have a fake IP, e.g., fake DNS is disabled.
+	GetFakeIP(domain string) net.IP
+
+	// GetFakeDomain returns the domain that the given fake IP is allocated to.
+	GetFakeDomain(ip net.IP) (string, bool)
+}
+
+// FromSpace fetches a DNS server from context.
+func FromSpace(space app.Space) Server {
+	app := space.GetApplication((*Server)(nil))
+	if app == nil {
+		return nil
+	}
+	return app.(Server)
+}
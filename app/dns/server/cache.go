@@ -0,0 +1,103 @@
+package server
+
+import (
+	"container/list"
+	"time"
+
+	dnsmsg "github.com/miekg/dns"
+)
+
+// cacheEntry is the cached records of a domain.
+type cacheEntry struct {
+	domain string
+	record DomainRecord
+	// hits is the number of times the records are used since last update.
+	hits uint32
+}
+
+func (e *cacheEntry) get(qtype uint16) *ARecord {
+	if qtype == dnsmsg.TypeAAAA {
+		return e.record.AAAA
+	}
+	return e.record.A
+}
+
+func (e *cacheEntry) set(qtype uint16, a *ARecord) {
+	if qtype == dnsmsg.TypeAAAA {
+		e.record.AAAA = a
+	} else {
+		e.record.A = a
+	}
+	e.hits = 0
+}
+
+// expire returns the time when all records of the domain expire.
+func (e *cacheEntry) expire() time.Time {
+	var expire time.Time
+	for _, a := range []*ARecord{e.record.A, e.record.AAAA} {
+		if a != nil && a.Expire.After(expire) {
+			expire = a.Expire
+		}
+	}
+	return expire
+}
+
+// recordCache is a cache of DNS records bounded by the number of domains. When it is full, the least recently used
+// domain is evicted. It is not safe for concurrent use.
+type recordCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List
+}
+
+func newRecordCache(capacity int) *recordCache {
+	return &recordCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Get returns the entry of the given domain, or nil if there isn't one. The entry is marked as recently used.
+func (c *recordCache) Get(domain string) *cacheEntry {
+	element, found := c.entries[domain]
+	if !found {
+		return nil
+	}
+	c.lru.MoveToFront(element)
+	return element.Value.(*cacheEntry)
+}
+
+// Put returns the entry of the given domain, creating it if there isn't one. The entry is marked as recently used.
+func (c *recordCache) Put(domain string) *cacheEntry {
+	if entry := c.Get(domain); entry != nil {
+		return entry
+	}
+	entry := &cacheEntry{domain: domain}
+	c.entries[domain] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.capacity {
+		c.remove(c.lru.Back())
+	}
+	return entry
+}
+
+// RemoveExpired removes entries of which all records expire before the given time.
+func (c *recordCache) RemoveExpired(before time.Time) {
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*cacheEntry).expire().Before(before) {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *recordCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).domain)
+}
+
+// Len returns the number of domains in the cache.
+func (c *recordCache) Len() int {
+	return c.lru.Len()
+}
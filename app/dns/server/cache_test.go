@@ -0,0 +1,274 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dnsmsg "github.com/miekg/dns"
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/dns/resolver"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/testing/assert"
+)
+
+// staticNameServer answers all queries with the same IP, and counts the queries.
+type staticNameServer struct {
+	ip      string
+	queries int32
+}
+
+func (s *staticNameServer) QueryIP(domain string, qtype uint16, subnet *net.IPNet) <-chan *ARecord {
+	atomic.AddInt32(&s.queries, 1)
+	response := make(chan *ARecord, 1)
+	response <- &ARecord{
+		IPs:    []net.IP{net.ParseIP(s.ip)},
+		Expire: time.Now().Add(time.Minute),
+	}
+	close(response)
+	return response
+}
+
+func newTestCacheServer(config *dns.Config, servers ...NameServer) *CacheServer {
+	s := &CacheServer{
+		pending: make(map[queryKey]*pendingQuery),
+	}
+	for _, ns := range servers {
+		s.servers = append(s.servers, &serverEntry{NameServer: ns})
+	}
+	s.applyConfig(config)
+	return s
+}
+
+func TestRecordCacheEviction(t *testing.T) {
+	assert := assert.On(t)
+
+	cache := newRecordCache(2)
+	cache.Put("a").set(dnsmsg.TypeA, &ARecord{})
+	cache.Put("b").set(dnsmsg.TypeA, &ARecord{})
+	assert.Pointer(cache.Get("a")).IsNotNil()
+	cache.Put("c").set(dnsmsg.TypeA, &ARecord{})
+
+	assert.Int(cache.Len()).Equals(2)
+	assert.Pointer(cache.Get("a")).IsNotNil()
+	assert.Pointer(cache.Get("b")).IsNil()
+	assert.Pointer(cache.Get("c")).IsNotNil()
+}
+
+func TestRecordCacheRemoveExpired(t *testing.T) {
+	assert := assert.On(t)
+
+	now := time.Now()
+	cache := newRecordCache(10)
+	cache.Put("a").set(dnsmsg.TypeA, &ARecord{Expire: now.Add(-time.Second)})
+	entry := cache.Put("b")
+	entry.set(dnsmsg.TypeA, &ARecord{Expire: now.Add(-time.Second)})
+	entry.set(dnsmsg.TypeAAAA, &ARecord{Expire: now.Add(time.Second)})
+
+	cache.RemoveExpired(now)
+	assert.Int(cache.Len()).Equals(1)
+	assert.Pointer(cache.Get("a")).IsNil()
+	assert.Pointer(cache.Get("b")).IsNotNil()
+}
+
+// emptyNameServer answers all queries without IP, as for NXDOMAIN, or fails to answer if failed is set.
+type emptyNameServer struct {
+	failed  bool
+	queries int32
+}
+
+func (s *emptyNameServer) QueryIP(domain string, qtype uint16, subnet *net.IPNet) <-chan *ARecord {
+	atomic.AddInt32(&s.queries, 1)
+	response := make(chan *ARecord, 1)
+	if !s.failed {
+		response <- &ARecord{
+			IPs:    []net.IP{},
+			Expire: time.Now().Add(time.Hour),
+		}
+	}
+	close(response)
+	return response
+}
+
+func TestNegativeCache(t *testing.T) {
+	assert := assert.On(t)
+
+	ns := new(emptyNameServer)
+	s := newTestCacheServer(&dns.Config{}, ns)
+
+	_, err := s.Resolve(context.Background(), "v2ray.com")
+	assert.Error(err).Equals(resolver.ErrNotFound)
+	_, err = s.Resolve(context.Background(), "v2ray.com")
+	assert.Error(err).Equals(resolver.ErrNotFound)
+
+	stats := s.CacheStats()
+	assert.Int64(int64(stats.Misses)).Equals(1)
+	assert.Int64(int64(stats.Hits)).Equals(1)
+	assert.Int(int(atomic.LoadInt32(&ns.queries))).Equals(1)
+
+	// Empty answers expire after the negative TTL, instead of their own TTL.
+	entry := s.cache.Get("v2ray.com.")
+	assert.Bool(entry.get(dnsmsg.TypeA).Expire.Before(time.Now().Add(s.negativeTTL + time.Second))).IsTrue()
+}
+
+func TestFailedLookupNotCached(t *testing.T) {
+	assert := assert.On(t)
+
+	ns := &emptyNameServer{failed: true}
+	s := newTestCacheServer(&dns.Config{}, ns)
+
+	for i := 0; i < 2; i++ {
+		_, err := s.Resolve(context.Background(), "v2ray.com")
+		assert.Error(err).IsNotNil()
+		assert.Bool(err == resolver.ErrNotFound).IsFalse()
+	}
+	assert.Int(int(atomic.LoadInt32(&ns.queries))).Equals(2)
+	assert.Int(s.CacheStats().Size).Equals(0)
+}
+
+func TestFailedRefreshKeepsRecord(t *testing.T) {
+	assert := assert.On(t)
+
+	for _, serveStale := range []bool{false, true} {
+		ns := &emptyNameServer{failed: true}
+		s := newTestCacheServer(&dns.Config{Prefetch: true, ServeStale: serveStale}, ns)
+		s.store("v2ray.com.", dnsmsg.TypeA, &ARecord{
+			IPs:    []net.IP{net.ParseIP("127.0.0.2")},
+			Expire: time.Now().Add(PrefetchWindow / 2),
+		})
+
+		for i := 0; i < PrefetchThreshold; i++ {
+			assert.Int(len(s.Get("v2ray.com"))).Equals(1)
+		}
+
+		time.Sleep(time.Millisecond * 100)
+		assert.Int(int(atomic.LoadInt32(&ns.queries))).Equals(1)
+		ips := s.GetCachedByType("v2ray.com.", dnsmsg.TypeA)
+		assert.Int(len(ips)).Equals(1)
+		assert.String(ips[0].String()).Equals("127.0.0.2")
+	}
+}
+
+func TestParseResponseRcode(t *testing.T) {
+	assert := assert.On(t)
+
+	query := new(dnsmsg.Msg).SetQuestion("v2ray.com.", dnsmsg.TypeA)
+	for _, rcode := range []int{dnsmsg.RcodeServerFailure, dnsmsg.RcodeRefused} {
+		assert.Pointer(parseResponse(new(dnsmsg.Msg).SetRcode(query, rcode))).IsNil()
+	}
+	a := parseResponse(new(dnsmsg.Msg).SetRcode(query, dnsmsg.RcodeNameError))
+	assert.Pointer(a).IsNotNil()
+	assert.Int(len(a.IPs)).Equals(0)
+}
+
+func TestGetCached(t *testing.T) {
+	assert := assert.On(t)
+
+	s := newTestCacheServer(&dns.Config{}, &staticNameServer{ip: "127.0.0.3"})
+	expire := time.Now().Add(time.Minute)
+	s.store("v2ray.com.", dnsmsg.TypeA, &ARecord{IPs: []net.IP{net.ParseIP("127.0.0.2")}, Expire: expire})
+	s.store("v2ray.com.", dnsmsg.TypeAAAA, &ARecord{IPs: []net.IP{net.ParseIP("::2")}, Expire: expire})
+
+	ips := s.GetCachedByType("v2ray.com.", dnsmsg.TypeAAAA)
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("::2")
+
+	// The deprecated GetCached() returns A records only.
+	ips = s.GetCached("v2ray.com.")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.2")
+	assert.Bool(s.GetCached("v2ray.org.") == nil).IsTrue()
+}
+
+func TestServeStale(t *testing.T) {
+	assert := assert.On(t)
+
+	ns := &staticNameServer{ip: "127.0.0.3"}
+	s := newTestCacheServer(&dns.Config{ServeStale: true}, ns)
+	s.store("v2ray.com.", dnsmsg.TypeA, &ARecord{
+		IPs:    []net.IP{net.ParseIP("127.0.0.2")},
+		Expire: time.Now().Add(-time.Second),
+	})
+
+	ips := s.Get("v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.2")
+
+	// The stale record is refreshed in background.
+	time.Sleep(time.Millisecond * 100)
+	ips = s.GetCachedByType("v2ray.com.", dnsmsg.TypeA)
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.3")
+	assert.Int(int(atomic.LoadInt32(&ns.queries))).Equals(1)
+}
+
+func TestPrefetch(t *testing.T) {
+	assert := assert.On(t)
+
+	ns := &staticNameServer{ip: "127.0.0.3"}
+	s := newTestCacheServer(&dns.Config{Prefetch: true}, ns)
+	s.store("v2ray.com.", dnsmsg.TypeA, &ARecord{
+		IPs:    []net.IP{net.ParseIP("127.0.0.2")},
+		Expire: time.Now().Add(PrefetchWindow / 2),
+	})
+
+	for i := 0; i < PrefetchThreshold; i++ {
+		ips := s.Get("v2ray.com")
+		assert.Int(len(ips)).Equals(1)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	ips := s.GetCachedByType("v2ray.com.", dnsmsg.TypeA)
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.3")
+	assert.Int(int(atomic.LoadInt32(&ns.queries))).Equals(1)
+}
+
+func TestQueryParallel(t *testing.T) {
+	assert := assert.On(t)
+
+	// Answers without IP or without expected IP don't win over the valid one.
+	static := &staticNameServer{ip: "127.0.0.3"}
+	unexpected := &serverEntry{NameServer: &staticNameServer{ip: "10.0.0.1"}}
+	expectedIPs, err := router.NewIPMatcher([]*router.CIDR{{Ip: []byte{127, 0, 0, 0}, Prefix: 8}}, false)
+	assert.Error(err).IsNil()
+	unexpected.expectedIPs = expectedIPs
+
+	config := &dns.Config{Concurrency: 4}
+	s := newTestCacheServer(config, new(emptyNameServer), &emptyNameServer{failed: true}, static)
+	s.servers = append(s.servers, unexpected)
+	for i := 0; i < 10; i++ {
+		// The cache is dropped, so that each lookup queries all servers again.
+		s.applyConfig(config)
+		ips := s.Get("v2ray.com")
+		assert.Int(len(ips)).Equals(1)
+		assert.String(ips[0].String()).Equals("127.0.0.3")
+	}
+
+	// An answer without IP is used if no server answers with IPs.
+	s = newTestCacheServer(&dns.Config{Concurrency: 2}, &emptyNameServer{failed: true}, new(emptyNameServer))
+	_, err = s.Resolve(context.Background(), "v2ray.com")
+	assert.Error(err).Equals(resolver.ErrNotFound)
+}
+
+func TestQueryParallelPrioritized(t *testing.T) {
+	assert := assert.On(t)
+
+	other := &staticNameServer{ip: "127.0.0.3"}
+	preferred := &staticNameServer{ip: "127.0.0.5"}
+	s := newTestCacheServer(&dns.Config{Concurrency: 2}, other, preferred)
+	domains, err := router.NewDomainMatcher([]*router.Domain{{Type: router.Domain_Domain, Value: "v2ray.com"}})
+	assert.Error(err).IsNil()
+	s.servers[1].domains = domains
+
+	// Preferred servers are not queried in parallel with the others.
+	ips := s.Get("www.v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.5")
+	assert.Int(int(atomic.LoadInt32(&other.queries))).Equals(0)
+
+	ips = s.Get("example.com")
+	assert.Int(len(ips)).Equals(1)
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"bufio"
+	"container/list"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// fakeIPEntry is a domain and the fake IP allocated to it.
+type fakeIPEntry struct {
+	domain string
+	ip     net.IP
+}
+
+// FakeIPPool allocates fake IPs to domains from a reserved IP range. It remembers a bounded number of domains.
+// When it is full, the least recently used domain is evicted, and its IP is reused.
+type FakeIPPool struct {
+	sync.Mutex
+	network    *net.IPNet
+	capacity   int
+	nextOffset uint64
+	lru        *list.List
+	domains    map[string]*list.Element
+	ips        map[string]*list.Element
+}
+
+// NewFakeIPPool creates a new FakeIPPool on the given network, remembering at most capacity domains.
+// The capacity is limited by the size of the network, and the first IP of the network is never allocated.
+func NewFakeIPPool(network *net.IPNet, capacity int) *FakeIPPool {
+	if ip4 := network.IP.To4(); ip4 != nil && len(network.Mask) == net.IPv4len {
+		network = &net.IPNet{IP: ip4, Mask: network.Mask}
+	}
+	ones, bits := network.Mask.Size()
+	if hostBits := uint(bits - ones); hostBits < 31 {
+		if available := (1 << hostBits) - 1; capacity > available {
+			capacity = available
+		}
+	}
+	return &FakeIPPool{
+		network:    network,
+		capacity:   capacity,
+		nextOffset: 1,
+		lru:        list.New(),
+		domains:    make(map[string]*list.Element),
+		ips:        make(map[string]*list.Element),
+	}
+}
+
+// GetFakeIP returns the fake IP of the given domain, allocating one if necessary.
+func (p *FakeIPPool) GetFakeIP(domain string) net.IP {
+	domain = strings.ToLower(domain)
+
+	p.Lock()
+	defer p.Unlock()
+
+	if element, found := p.domains[domain]; found {
+		p.lru.MoveToFront(element)
+		return element.Value.(*fakeIPEntry).ip
+	}
+
+	var ip net.IP
+	if p.lru.Len() < p.capacity && p.nextOffset <= uint64(p.capacity) {
+		ip = p.ipAt(p.nextOffset)
+		p.nextOffset++
+	} else if back := p.lru.Back(); back != nil {
+		ip = back.Value.(*fakeIPEntry).ip
+		p.remove(back)
+	} else {
+		return nil
+	}
+	p.add(domain, ip)
+	return ip
+}
+
+// GetDomain returns the domain that the given fake IP is allocated to.
+func (p *FakeIPPool) GetDomain(ip net.IP) (string, bool) {
+	if !p.network.Contains(ip) {
+		return "", false
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	element, found := p.ips[string(ip.To16())]
+	if !found {
+		return "", false
+	}
+	p.lru.MoveToFront(element)
+	return element.Value.(*fakeIPEntry).domain, true
+}
+
+// Len returns the number of domains that have fake IPs.
+func (p *FakeIPPool) Len() int {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.lru.Len()
+}
+
+// Save writes all fake IPs to the given file, one "IP domain" pair per line, from the least recently used one.
+func (p *FakeIPPool) Save(path string) error {
+	p.Lock()
+	var content []byte
+	for element := p.lru.Back(); element != nil; element = element.Prev() {
+		entry := element.Value.(*fakeIPEntry)
+		content = append(content, entry.ip.String()+" "+entry.domain+"\n"...)
+	}
+	p.Unlock()
+
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0600); err != nil {
+		return newError("failed to write fake IPs to ", tmpPath).Base(err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return newError("failed to save fake IPs to ", path).Base(err)
+	}
+	return nil
+}
+
+// Load reads fake IPs from the given file written by Save. Invalid lines, and IPs out of the pool are ignored.
+func (p *FakeIPPool) Load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	p.Lock()
+	defer p.Unlock()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		ip := net.ParseIP(fields[0])
+		domain := strings.ToLower(fields[1])
+		if ip == nil || !p.network.Contains(ip) {
+			continue
+		}
+		if ip4 := ip.To4(); ip4 != nil && len(p.network.IP) == net.IPv4len {
+			ip = ip4
+		}
+		if element, found := p.domains[domain]; found {
+			p.remove(element)
+		}
+		if element, found := p.ips[string(ip.To16())]; found {
+			p.remove(element)
+		}
+		p.add(domain, ip)
+		if offset := p.offsetOf(ip); offset >= p.nextOffset {
+			p.nextOffset = offset + 1
+		}
+	}
+	for p.lru.Len() > p.capacity {
+		p.remove(p.lru.Back())
+	}
+	if err := scanner.Err(); err != nil {
+		return newError("failed to load fake IPs from ", path).Base(err)
+	}
+	return nil
+}
+
+func (p *FakeIPPool) add(domain string, ip net.IP) {
+	element := p.lru.PushFront(&fakeIPEntry{
+		domain: domain,
+		ip:     ip,
+	})
+	p.domains[domain] = element
+	p.ips[string(ip.To16())] = element
+}
+
+func (p *FakeIPPool) remove(element *list.Element) {
+	entry := element.Value.(*fakeIPEntry)
+	p.lru.Remove(element)
+	delete(p.domains, entry.domain)
+	delete(p.ips, string(entry.ip.To16()))
+}
+
+// ipAt returns the IP at the given offset from the first IP of the pool.
+func (p *FakeIPPool) ipAt(offset uint64) net.IP {
+	ip := make(net.IP, len(p.network.IP))
+	copy(ip, p.network.IP)
+	if len(ip) == net.IPv4len {
+		binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(ip)+uint32(offset))
+	} else {
+		binary.BigEndian.PutUint64(ip[8:], binary.BigEndian.Uint64(ip[8:])+offset)
+	}
+	return ip
+}
+
+// offsetOf returns the offset of the given IP from the first IP of the pool. The IP must be in the pool.
+func (p *FakeIPPool) offsetOf(ip net.IP) uint64 {
+	if len(p.network.IP) == net.IPv4len {
+		return uint64(binary.BigEndian.Uint32(ip.To4()) - binary.BigEndian.Uint32(p.network.IP))
+	}
+	ip = ip.To16()
+	return binary.BigEndian.Uint64(ip[8:]) - binary.BigEndian.Uint64(p.network.IP[8:])
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"v2ray.com/core/testing/assert"
+)
+
+func TestFakeIPPool(t *testing.T) {
+	assert := assert.On(t)
+
+	_, network, err := net.ParseCIDR("198.18.0.0/15")
+	assert.Error(err).IsNil()
+	pool := NewFakeIPPool(network, 2)
+
+	ip1 := pool.GetFakeIP("v2ray.com")
+	assert.String(ip1.String()).Equals("198.18.0.1")
+	assert.String(pool.GetFakeIP("V2Ray.com").String()).Equals("198.18.0.1")
+	ip2 := pool.GetFakeIP("www.v2ray.com")
+	assert.String(ip2.String()).Equals("198.18.0.2")
+
+	domain, found := pool.GetDomain(net.ParseIP("198.18.0.1"))
+	assert.Bool(found).IsTrue()
+	assert.String(domain).Equals("v2ray.com")
+
+	// www.v2ray.com is the least recently used one, so its IP is reused.
+	ip3 := pool.GetFakeIP("example.com")
+	assert.String(ip3.String()).Equals("198.18.0.2")
+	domain, found = pool.GetDomain(ip2)
+	assert.Bool(found).IsTrue()
+	assert.String(domain).Equals("example.com")
+	assert.Int(pool.Len()).Equals(2)
+
+	_, found = pool.GetDomain(net.ParseIP("10.0.0.1"))
+	assert.Bool(found).IsFalse()
+}
+
+func TestFakeIPPoolLimitedByNetwork(t *testing.T) {
+	assert := assert.On(t)
+
+	_, network, err := net.ParseCIDR("198.18.0.0/30")
+	assert.Error(err).IsNil()
+	pool := NewFakeIPPool(network, 100)
+
+	assert.String(pool.GetFakeIP("a").String()).Equals("198.18.0.1")
+	assert.String(pool.GetFakeIP("b").String()).Equals("198.18.0.2")
+	assert.String(pool.GetFakeIP("c").String()).Equals("198.18.0.3")
+	assert.String(pool.GetFakeIP("d").String()).Equals("198.18.0.1")
+}
+
+func TestFakeIPPoolIPv6(t *testing.T) {
+	assert := assert.On(t)
+
+	_, network, err := net.ParseCIDR("fc00::/64")
+	assert.Error(err).IsNil()
+	pool := NewFakeIPPool(network, 100)
+
+	assert.String(pool.GetFakeIP("v2ray.com").String()).Equals("fc00::1")
+	domain, found := pool.GetDomain(net.ParseIP("fc00::1"))
+	assert.Bool(found).IsTrue()
+	assert.String(domain).Equals("v2ray.com")
+}
+
+func TestFakeIPPoolPersistence(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-fakedns")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fakedns.txt")
+
+	_, network, err := net.ParseCIDR("198.18.0.0/15")
+	assert.Error(err).IsNil()
+	pool := NewFakeIPPool(network, 10)
+	pool.GetFakeIP("v2ray.com")
+	pool.GetFakeIP("www.v2ray.com")
+	assert.Error(pool.Save(path)).IsNil()
+
+	pool = NewFakeIPPool(network, 10)
+	assert.Error(pool.Load(path)).IsNil()
+	assert.Int(pool.Len()).Equals(2)
+	domain, found := pool.GetDomain(net.ParseIP("198.18.0.2"))
+	assert.Bool(found).IsTrue()
+	assert.String(domain).Equals("www.v2ray.com")
+
+	// New domains don't take saved IPs.
+	assert.String(pool.GetFakeIP("example.com").String()).Equals("198.18.0.3")
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/router"
+)
+
+// hostEntry is the IPs of a domain in static hosts, or the domain it is an alias of.
+type hostEntry struct {
+	ips   []net.IP
+	alias string
+}
+
+type keywordHost struct {
+	keyword string
+	entry   *hostEntry
+}
+
+type regexHost struct {
+	pattern *regexp.Regexp
+	entry   *hostEntry
+}
+
+// StaticHosts matches domains against static hosts. Full domains and domains are looked up in maps,
+// so that large hosts lists are cheap to match. Keywords and regular expressions are matched one by one.
+type StaticHosts struct {
+	full     map[string]*hostEntry
+	domains  map[string]*hostEntry
+	keywords []keywordHost
+	regexps  []regexHost
+}
+
+// NewStaticHosts creates an empty StaticHosts.
+func NewStaticHosts() *StaticHosts {
+	return &StaticHosts{
+		full:    make(map[string]*hostEntry),
+		domains: make(map[string]*hostEntry),
+	}
+}
+
+// buildStaticHosts creates StaticHosts from the given config. Invalid entries and hosts files are skipped with a warning.
+func buildStaticHosts(config *dns.Config) *StaticHosts {
+	hosts := NewStaticHosts()
+	for domain, ipOrDomain := range config.GetHosts() {
+		mapping := &dns.HostMapping{
+			Type:   router.Domain_Full,
+			Domain: domain,
+		}
+		address := ipOrDomain.AsAddress()
+		if address.Family().IsDomain() {
+			mapping.ProxiedDomain = address.Domain()
+		} else {
+			mapping.Ip = [][]byte{address.IP()}
+		}
+		if err := hosts.Add(mapping); err != nil {
+			log.Trace(newError("ignoring static host ", domain).Base(err).AtWarning())
+		}
+	}
+	for _, mapping := range config.StaticHosts {
+		if err := hosts.Add(mapping); err != nil {
+			log.Trace(newError("ignoring static host ", mapping.Domain).Base(err).AtWarning())
+		}
+	}
+	for _, path := range config.HostsFile {
+		if err := hosts.Load(path); err != nil {
+			log.Trace(newError("failed to load hosts file").Base(err).AtWarning())
+		}
+	}
+	return hosts
+}
+
+// Add adds the given mapping. IPs of mappings of the same pattern are merged, and an alias overrides IPs.
+func (h *StaticHosts) Add(mapping *dns.HostMapping) error {
+	entry := &hostEntry{
+		alias: strings.ToLower(mapping.ProxiedDomain),
+	}
+	if len(entry.alias) == 0 {
+		for _, ip := range mapping.Ip {
+			if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+				return newError("invalid IP: ", ip)
+			}
+			entry.ips = append(entry.ips, net.IP(ip))
+		}
+		if len(entry.ips) == 0 {
+			return newError("neither IP nor proxied domain is set")
+		}
+	}
+
+	domain := strings.ToLower(strings.TrimSuffix(mapping.Domain, "."))
+	switch mapping.Type {
+	case router.Domain_Full:
+		h.full[domain] = mergeHostEntry(h.full[domain], entry)
+	case router.Domain_Domain:
+		h.domains[domain] = mergeHostEntry(h.domains[domain], entry)
+	case router.Domain_Plain:
+		h.keywords = append(h.keywords, keywordHost{
+			keyword: domain,
+			entry:   entry,
+		})
+	case router.Domain_Regex:
+		pattern, err := regexp.Compile(mapping.Domain)
+		if err != nil {
+			return newError("invalid regular expression: ", mapping.Domain).Base(err)
+		}
+		h.regexps = append(h.regexps, regexHost{
+			pattern: pattern,
+			entry:   entry,
+		})
+	default:
+		return newError("unknown domain type: ", mapping.Type)
+	}
+	return nil
+}
+
+func mergeHostEntry(existing *hostEntry, entry *hostEntry) *hostEntry {
+	if existing == nil || len(existing.alias) > 0 || len(entry.alias) > 0 {
+		return entry
+	}
+	existing.ips = append(existing.ips, entry.ips...)
+	return existing
+}
+
+// Load adds full domains from the given file in /etc/hosts format. Each line is an IP followed by one or more domains.
+// Text after '#' is a comment. Lines with an invalid IP are ignored.
+func (h *StaticHosts) Load(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return newError("failed to open hosts file ", path).Base(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		ip := net.ParseIP(fields[0])
+		if ip == nil {
+			continue
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		for _, domain := range fields[1:] {
+			domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+			h.full[domain] = mergeHostEntry(h.full[domain], &hostEntry{ips: []net.IP{ip}})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return newError("failed to read hosts file ", path).Base(err)
+	}
+	return nil
+}
+
+// Lookup returns the static IPs of the given domain, or the domain it is an alias of. Full domains take precedence,
+// then the longest matching domain, then keywords and regular expressions in the order they are added.
+func (h *StaticHosts) Lookup(domain string) (ips []net.IP, alias string, found bool) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if entry, found := h.full[domain]; found {
+		return entry.ips, entry.alias, true
+	}
+	for suffix := domain; len(suffix) > 0; {
+		if entry, found := h.domains[suffix]; found {
+			return entry.ips, entry.alias, true
+		}
+		idx := strings.IndexByte(suffix, '.')
+		if idx < 0 {
+			break
+		}
+		suffix = suffix[idx+1:]
+	}
+	for _, host := range h.keywords {
+		if strings.Contains(domain, host.keyword) {
+			return host.entry.ips, host.entry.alias, true
+		}
+	}
+	for _, host := range h.regexps {
+		if host.pattern.MatchString(domain) {
+			return host.entry.ips, host.entry.alias, true
+		}
+	}
+	return nil, "", false
+}
+
+// Len returns the number of entries.
+func (h *StaticHosts) Len() int {
+	return len(h.full) + len(h.domains) + len(h.keywords) + len(h.regexps)
+}
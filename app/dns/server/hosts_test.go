@@ -0,0 +1,142 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/router"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestStaticHostsLookup(t *testing.T) {
+	assert := assert.On(t)
+
+	hosts := NewStaticHosts()
+	for _, mapping := range []*dns.HostMapping{
+		{Type: router.Domain_Full, Domain: "www.v2ray.com", Ip: [][]byte{{127, 0, 0, 1}}},
+		{Type: router.Domain_Domain, Domain: "v2ray.com", Ip: [][]byte{{127, 0, 0, 2}}},
+		{Type: router.Domain_Domain, Domain: "api.v2ray.com", Ip: [][]byte{{127, 0, 0, 3}}},
+		{Type: router.Domain_Plain, Domain: "ads", Ip: [][]byte{{0, 0, 0, 0}}},
+		{Type: router.Domain_Regex, Domain: "^track[0-9]+\\.", Ip: [][]byte{{0, 0, 0, 0}}},
+		{Type: router.Domain_Full, Domain: "mirror.v2ray.com", ProxiedDomain: "www.v2ray.com"},
+	} {
+		assert.Error(hosts.Add(mapping)).IsNil()
+	}
+	assert.Error(hosts.Add(&dns.HostMapping{Type: router.Domain_Full, Domain: "empty.v2ray.com"})).IsNotNil()
+	assert.Error(hosts.Add(&dns.HostMapping{Type: router.Domain_Regex, Domain: "(", Ip: [][]byte{{0, 0, 0, 0}}})).IsNotNil()
+
+	cases := []struct {
+		domain string
+		ip     string
+		alias  string
+		found  bool
+	}{
+		{"www.v2ray.com", "127.0.0.1", "", true},
+		{"WWW.v2ray.com.", "127.0.0.1", "", true},
+		{"v2ray.com", "127.0.0.2", "", true},
+		{"a.b.v2ray.com", "127.0.0.2", "", true},
+		{"x.api.v2ray.com", "127.0.0.3", "", true},
+		{"myv2ray.com", "", "", false},
+		{"ads.example.com", "0.0.0.0", "", true},
+		{"track12.example.com", "0.0.0.0", "", true},
+		{"mirror.v2ray.com", "", "www.v2ray.com", true},
+		{"example.com", "", "", false},
+	}
+	for _, c := range cases {
+		ips, alias, found := hosts.Lookup(c.domain)
+		assert.Bool(found).Equals(c.found)
+		assert.String(alias).Equals(c.alias)
+		if len(c.ip) > 0 {
+			assert.Int(len(ips)).Equals(1)
+			assert.String(ips[0].String()).Equals(c.ip)
+		} else {
+			assert.Int(len(ips)).Equals(0)
+		}
+	}
+}
+
+func TestStaticHostsLoad(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-hosts")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "hosts")
+	assert.Error(ioutil.WriteFile(path, []byte(`# comment
+127.0.0.1	localhost
+::1	localhost ip6-localhost # trailing comment
+10.0.0.1 a.v2ray.com B.v2ray.com
+invalid c.v2ray.com
+`), 0600)).IsNil()
+
+	hosts := NewStaticHosts()
+	assert.Error(hosts.Load(path)).IsNil()
+	assert.Error(hosts.Load(filepath.Join(dir, "missing"))).IsNotNil()
+	assert.Int(hosts.Len()).Equals(4)
+
+	ips, _, found := hosts.Lookup("localhost")
+	assert.Bool(found).IsTrue()
+	assert.Int(len(ips)).Equals(2)
+	assert.String(ips[0].String()).Equals("127.0.0.1")
+	assert.String(ips[1].String()).Equals("::1")
+
+	ips, _, found = hosts.Lookup("b.v2ray.com")
+	assert.Bool(found).IsTrue()
+	assert.String(ips[0].String()).Equals("10.0.0.1")
+
+	_, _, found = hosts.Lookup("c.v2ray.com")
+	assert.Bool(found).IsFalse()
+}
+
+func TestGetStaticHosts(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &dns.Config{
+		Hosts: map[string]*v2net.IPOrDomain{
+			"legacy.v2ray.com": v2net.NewIPOrDomain(v2net.ParseAddress("127.0.0.5")),
+			"cname.v2ray.com":  v2net.NewIPOrDomain(v2net.ParseAddress("legacy.v2ray.com")),
+		},
+		StaticHosts: []*dns.HostMapping{
+			{Type: router.Domain_Domain, Domain: "v2ray.com", Ip: [][]byte{{127, 0, 0, 1}, {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}}},
+			{Type: router.Domain_Full, Domain: "alias.v2ray.com", ProxiedDomain: "www.v2ray.com"},
+			{Type: router.Domain_Full, Domain: "loop1.v2ray.com", ProxiedDomain: "loop2.v2ray.com"},
+			{Type: router.Domain_Full, Domain: "loop2.v2ray.com", ProxiedDomain: "loop1.v2ray.com"},
+			{Type: router.Domain_Full, Domain: "ip6.v2ray.com", Ip: [][]byte{{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2}}},
+		},
+	}
+	s := newTestCacheServer(config, &staticNameServer{ip: "127.0.0.3"})
+
+	ips := s.Get("www.v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.1")
+
+	ips = s.Get("alias.v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.1")
+
+	ips = s.Get("cname.v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.5")
+
+	assert.Int(len(s.Get("loop1.v2ray.com"))).Equals(0)
+
+	// Static IPs of other families are returned, rather than resolving the domain.
+	ips = s.Get("ip6.v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("::2")
+
+	ips = s.Get("example.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.3")
+
+	config.QueryStrategy = dns.QueryStrategy_PREFER_IP6
+	s = newTestCacheServer(config)
+	ips = s.Get("www.v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("::1")
+}
@@ -19,6 +19,8 @@ const (
 	DefaultTTL       = uint32(3600)
 	CleanupInterval  = time.Second * 120
 	CleanupThreshold = 512
+	// EDNSBufferSize is the UDP payload size advertised in queries with EDNS0. It fits in a buffer of buf.Size.
+	EDNSBufferSize = 1232
 )
 
 var (
@@ -31,7 +33,9 @@ type ARecord struct {
 }
 
 type NameServer interface {
-	QueryA(domain string) <-chan *ARecord
+	// QueryIP queries IPs of the given domain. qtype is either dns.TypeA or dns.TypeAAAA. subnet, if not nil,
+	// is the client subnet to send in EDNS0.
+	QueryIP(domain string, qtype uint16, subnet *net.IPNet) <-chan *ARecord
 }
 
 type PendingRequest struct {
@@ -107,11 +111,7 @@ func (v *UDPNameServer) HandleResponse(payload *buf.Buffer) {
 		log.Trace(newError("failed to parse DNS response").Base(err).AtWarning())
 		return
 	}
-	record := &ARecord{
-		IPs: make([]net.IP, 0, 16),
-	}
 	id := msg.Id
-	ttl := DefaultTTL
 	log.Trace(newError("handling response for id ", id, " content: ", msg.String()).AtDebug())
 
 	v.Lock()
@@ -123,6 +123,21 @@ func (v *UDPNameServer) HandleResponse(payload *buf.Buffer) {
 	delete(v.requests, id)
 	v.Unlock()
 
+	request.response <- parseResponse(msg)
+	close(request.response)
+}
+
+// parseResponse extracts the IPs in a DNS response. It returns nil if the server fails to answer, e.g., SERVFAIL or
+// REFUSED, so that the next server is queried. NXDOMAIN is an answer without IP.
+func parseResponse(msg *dns.Msg) *ARecord {
+	if msg.Rcode != dns.RcodeSuccess && msg.Rcode != dns.RcodeNameError {
+		log.Trace(newError("name server fails with ", dns.RcodeToString[msg.Rcode]).AtDebug())
+		return nil
+	}
+	record := &ARecord{
+		IPs: make([]net.IP, 0, 16),
+	}
+	ttl := DefaultTTL
 	for _, rr := range msg.Answer {
 		switch rr := rr.(type) {
 		case *dns.A:
@@ -138,22 +153,55 @@ func (v *UDPNameServer) HandleResponse(payload *buf.Buffer) {
 		}
 	}
 	record.Expire = time.Now().Add(time.Second * time.Duration(ttl))
-
-	request.response <- record
-	close(request.response)
+	return record
 }
 
-func (v *UDPNameServer) BuildQueryA(domain string, id uint16) *buf.Buffer {
-
+// buildQuery creates a DNS message querying records of the given type for the given domain. If subnet is not nil,
+// it is attached as the EDNS0 client subnet option.
+func buildQuery(domain string, id uint16, qtype uint16, subnet *net.IPNet) *dns.Msg {
 	msg := new(dns.Msg)
 	msg.Id = id
 	msg.RecursionDesired = true
 	msg.Question = []dns.Question{
 		{
 			Name:   dns.Fqdn(domain),
-			Qtype:  dns.TypeA,
+			Qtype:  qtype,
 			Qclass: dns.ClassINET,
 		}}
+	if subnet != nil {
+		msg.Extra = append(msg.Extra, buildClientSubnet(subnet))
+	}
+	return msg
+}
+
+// buildClientSubnet creates an OPT record with the EDNS0 client subnet option of the given subnet, as in RFC 7871.
+func buildClientSubnet(subnet *net.IPNet) *dns.OPT {
+	ones, _ := subnet.Mask.Size()
+	option := &dns.EDNS0_SUBNET{
+		Code:          dns.EDNS0SUBNET,
+		SourceNetmask: uint8(ones),
+	}
+	if ip4 := subnet.IP.To4(); ip4 != nil {
+		option.Family = 1
+		option.Address = ip4
+	} else {
+		option.Family = 2
+		option.Address = subnet.IP
+	}
+
+	opt := &dns.OPT{
+		Hdr: dns.RR_Header{
+			Name:   ".",
+			Rrtype: dns.TypeOPT,
+		},
+		Option: []dns.EDNS0{option},
+	}
+	opt.SetUDPSize(EDNSBufferSize)
+	return opt
+}
+
+func (v *UDPNameServer) BuildQuery(domain string, id uint16, qtype uint16, subnet *net.IPNet) *buf.Buffer {
+	msg := buildQuery(domain, id, qtype, subnet)
 
 	buffer := buf.New()
 	buffer.AppendSupplier(func(b []byte) (int, error) {
@@ -164,12 +212,26 @@ func (v *UDPNameServer) BuildQueryA(domain string, id uint16) *buf.Buffer {
 	return buffer
 }
 
+// BuildQueryA builds a query of A records for the given domain.
+//
+// Deprecated: Use BuildQuery instead.
+func (v *UDPNameServer) BuildQueryA(domain string, id uint16) *buf.Buffer {
+	return v.BuildQuery(domain, id, dns.TypeA, nil)
+}
+
+// QueryA queries A records of the given domain.
+//
+// Deprecated: Use QueryIP instead.
 func (v *UDPNameServer) QueryA(domain string) <-chan *ARecord {
+	return v.QueryIP(domain, dns.TypeA, nil)
+}
+
+func (v *UDPNameServer) QueryIP(domain string, qtype uint16, subnet *net.IPNet) <-chan *ARecord {
 	response := make(chan *ARecord, 1)
 	id := v.AssignUnusedID(response)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*8)
-	v.udpServer.Dispatch(ctx, v.address, v.BuildQueryA(domain, id), v.HandleResponse)
+	v.udpServer.Dispatch(ctx, v.address, v.BuildQuery(domain, id, qtype, subnet), v.HandleResponse)
 
 	go func() {
 		for i := 0; i < 2; i++ {
@@ -178,7 +240,7 @@ func (v *UDPNameServer) QueryA(domain string) <-chan *ARecord {
 			_, found := v.requests[id]
 			v.Unlock()
 			if found {
-				v.udpServer.Dispatch(ctx, v.address, v.BuildQueryA(domain, id), v.HandleResponse)
+				v.udpServer.Dispatch(ctx, v.address, v.BuildQuery(domain, id, qtype, subnet), v.HandleResponse)
 			} else {
 				break
 			}
@@ -192,7 +254,8 @@ func (v *UDPNameServer) QueryA(domain string) <-chan *ARecord {
 type LocalNameServer struct {
 }
 
-func (v *LocalNameServer) QueryA(domain string) <-chan *ARecord {
+// QueryIP implements NameServer. The client subnet is not supported by the system resolver, so it is ignored.
+func (v *LocalNameServer) QueryIP(domain string, qtype uint16, subnet *net.IPNet) <-chan *ARecord {
 	response := make(chan *ARecord, 1)
 
 	go func() {
@@ -204,8 +267,15 @@ func (v *LocalNameServer) QueryA(domain string) <-chan *ARecord {
 			return
 		}
 
+		filtered := make([]net.IP, 0, len(ips))
+		for _, ip := range ips {
+			if (ip.To4() != nil) == (qtype == dns.TypeA) {
+				filtered = append(filtered, ip)
+			}
+		}
+
 		response <- &ARecord{
-			IPs:    ips,
+			IPs:    filtered,
 			Expire: time.Now().Add(time.Second * time.Duration(DefaultTTL)),
 		}
 	}()
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/http2"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/proxyman/outbound"
+	v2net "v2ray.com/core/common/net"
+)
+
+const dnsMessageType = "application/dns-message"
+
+// DoHNameServer is a NameServer that sends queries over HTTPS, as in RFC 8484.
+// Connections are made through the dispatcher, and one HTTP/2 connection is shared by all queries.
+type DoHNameServer struct {
+	url    string
+	client *http.Client
+}
+
+// NewDoHNameServer creates a new DoHNameServer for the given URL. If dest is valid, connections are made to it,
+// instead of the host in the URL. If serverName is empty, the host in the URL is used to verify the certificate.
+func NewDoHNameServer(rawURL string, dest v2net.Destination, serverName string, dispatcher dispatcher.Interface) (*DoHNameServer, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, newError("invalid DNS over HTTPS URL: ", rawURL).Base(err)
+	}
+	if u.Scheme != "https" {
+		return nil, newError("DNS over HTTPS URL must be https: ", rawURL)
+	}
+
+	if !dest.IsValid() {
+		host, port := u.Hostname(), u.Port()
+		if len(port) == 0 {
+			port = "443"
+		}
+		p, err := v2net.PortFromString(port)
+		if err != nil {
+			return nil, newError("invalid port in DNS over HTTPS URL: ", rawURL).Base(err)
+		}
+		dest = v2net.TCPDestination(v2net.ParseAddress(host), p)
+	}
+	dest.Network = v2net.Network_TCP
+
+	transport := &http2.Transport{
+		TLSClientConfig: &tls.Config{
+			ServerName: serverName,
+			NextProtos: []string{http2.NextProtoTLS},
+		},
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			ctx, cancel := context.WithCancel(context.Background())
+			stream, err := dispatcher.Dispatch(ctx, dest)
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			rawConn := &dispatchedConn{Connection: outbound.NewConnection(stream), cancel: cancel}
+			// The dialing is not bound to the query that triggers it, so the dispatching is canceled if the
+			// handshake stalls, instead of being left behind after the query times out.
+			timer := time.AfterFunc(QueryTimeout, func() {
+				rawConn.Close()
+			})
+			conn := tls.Client(rawConn, cfg)
+			err = conn.Handshake()
+			if !timer.Stop() && err == nil {
+				err = newError("TLS handshake timed out")
+			}
+			if err != nil {
+				rawConn.Close()
+				return nil, err
+			}
+			return conn, nil
+		},
+	}
+
+	return &DoHNameServer{
+		url: u.String(),
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   QueryTimeout,
+		},
+	}, nil
+}
+
+// QueryIP implements NameServer.
+func (s *DoHNameServer) QueryIP(domain string, qtype uint16, subnet *net.IPNet) <-chan *ARecord {
+	response := make(chan *ARecord, 1)
+
+	go func() {
+		defer close(response)
+
+		msg, err := s.query(buildQuery(domain, 0, qtype, subnet))
+		if err != nil {
+			log.Trace(newError("failed to query ", domain, " on ", s.url).Base(err).AtWarning())
+			return
+		}
+		response <- parseResponse(msg)
+	}()
+
+	return response
+}
+
+func (s *DoHNameServer) query(msg *dns.Msg) (*dns.Msg, error) {
+	payload, err := msg.Pack()
+	if err != nil {
+		return nil, newError("failed to build query").Base(err)
+	}
+
+	req, err := http.NewRequest("POST", s.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", dnsMessageType)
+	req.Header.Set("Content-Type", dnsMessageType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, newError("unexpected status: ", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, newError("failed to read response").Base(err)
+	}
+
+	answer := new(dns.Msg)
+	if err := answer.Unpack(body); err != nil {
+		return nil, newError("failed to parse DNS response").Base(err)
+	}
+	return answer, nil
+}
+
+// dispatchedConn is a connection made through the dispatcher. Closing it also cancels the dispatching.
+type dispatchedConn struct {
+	*outbound.Connection
+	cancel context.CancelFunc
+}
+
+func (c *dispatchedConn) Close() error {
+	err := c.Connection.Close()
+	c.cancel()
+	return err
+}
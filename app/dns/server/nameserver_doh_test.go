@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/http2"
+	"v2ray.com/core/common/buf"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/ray"
+)
+
+// testDispatcher connects to destinations over TCP directly. It is used by tests that need to access internals
+// of name servers.
+type testDispatcher struct {
+	dispatched int32
+}
+
+func (d *testDispatcher) Dispatch(ctx context.Context, dest v2net.Destination) (ray.InboundRay, error) {
+	conn, err := net.Dial("tcp", dest.NetAddr())
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&d.dispatched, 1)
+
+	stream := ray.NewRay(ctx)
+	go func() {
+		buf.Copy(stream.OutboundInput(), buf.NewWriter(conn))
+		conn.Close()
+	}()
+	go func() {
+		buf.Copy(buf.NewReader(conn), stream.OutboundOutput())
+		stream.OutboundOutput().Close()
+	}()
+	return stream, nil
+}
+
+func TestDoHNameServer(t *testing.T) {
+	assert := assert.On(t)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Int(r.ProtoMajor).Equals(2)
+		assert.String(r.Header.Get("Content-Type")).Equals(dnsMessageType)
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Error(err).IsNil()
+		query := new(dns.Msg)
+		assert.Error(query.Unpack(body)).IsNil()
+
+		msg := new(dns.Msg).SetReply(query)
+		rr, err := dns.NewRR(query.Question[0].Name + " 60 IN A 127.0.0.3")
+		assert.Error(err).IsNil()
+		msg.Answer = append(msg.Answer, rr)
+		payload, err := msg.Pack()
+		assert.Error(err).IsNil()
+
+		w.Header().Set("Content-Type", dnsMessageType)
+		w.Write(payload)
+	}))
+	assert.Error(http2.ConfigureServer(ts.Config, nil)).IsNil()
+	ts.TLS = ts.Config.TLSConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	dispatcher := new(testDispatcher)
+	server, err := NewDoHNameServer(ts.URL+"/dns-query", v2net.Destination{}, "", dispatcher)
+	assert.Error(err).IsNil()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	server.client.Transport.(*http2.Transport).TLSClientConfig.RootCAs = roots
+
+	for i := 0; i < 3; i++ {
+		select {
+		case record, open := <-server.QueryIP("v2ray.com", dns.TypeA, nil):
+			assert.Bool(open).IsTrue()
+			assert.Int(len(record.IPs)).Equals(1)
+			assert.String(record.IPs[0].String()).Equals("127.0.0.3")
+		case <-time.After(time.Second * 5):
+			t.Fatal("timeout")
+		}
+	}
+
+	// All queries are sent over the same HTTP/2 connection.
+	assert.Int(int(atomic.LoadInt32(&dispatcher.dispatched))).Equals(1)
+}
+
+func TestDoHNameServerInvalidURL(t *testing.T) {
+	assert := assert.On(t)
+
+	_, err := NewDoHNameServer("http://dns.example.com/dns-query", v2net.Destination{}, "", new(testDispatcher))
+	assert.Error(err).IsNotNil()
+}
+
+// stallingDispatcher dispatches to an outbound that never answers.
+type stallingDispatcher struct {
+	ctx chan context.Context
+}
+
+func (d *stallingDispatcher) Dispatch(ctx context.Context, dest v2net.Destination) (ray.InboundRay, error) {
+	d.ctx <- ctx
+	return ray.NewRay(ctx), nil
+}
+
+func TestDoHNameServerStalledHandshake(t *testing.T) {
+	assert := assert.On(t)
+
+	dispatcher := &stallingDispatcher{ctx: make(chan context.Context, 1)}
+	server, err := NewDoHNameServer("https://dns.v2ray.com/dns-query", v2net.Destination{}, "", dispatcher)
+	assert.Error(err).IsNil()
+	assert.String(server.client.Transport.(*http2.Transport).TLSClientConfig.NextProtos[0]).Equals("h2")
+
+	response := server.QueryIP("v2ray.com", dns.TypeA, nil)
+	ctx := <-dispatcher.ctx
+	select {
+	case <-ctx.Done():
+	case <-time.After(QueryTimeout + time.Second*2):
+		t.Fatal("dispatching is not canceled")
+	}
+	_, open := <-response
+	assert.Bool(open).IsFalse()
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/dice"
+	v2net "v2ray.com/core/common/net"
+)
+
+// tcpConnection is a connection to a TCP name server, with queries waiting for responses on it.
+type tcpConnection struct {
+	net.Conn
+	cancel    context.CancelFunc
+	writeLock sync.Mutex
+	requests  map[uint16]*PendingRequest
+}
+
+// TCPNameServer is a NameServer that sends queries over TCP, or over TLS if a TLS config is given.
+// Queries are pipelined over one connection, which is reused until it fails.
+type TCPNameServer struct {
+	sync.Mutex
+	address    v2net.Destination
+	dispatcher dispatcher.Interface
+	tlsConfig  *tls.Config
+	conn       *tcpConnection
+}
+
+// NewTCPNameServer creates a new TCPNameServer that sends queries in plain text.
+func NewTCPNameServer(address v2net.Destination, dispatcher dispatcher.Interface) *TCPNameServer {
+	address.Network = v2net.Network_TCP
+	return &TCPNameServer{
+		address:    address,
+		dispatcher: dispatcher,
+	}
+}
+
+// NewTLSNameServer creates a new TCPNameServer that sends queries over TLS. If serverName is empty,
+// the address of the server is used to verify its certificate.
+func NewTLSNameServer(address v2net.Destination, serverName string, dispatcher dispatcher.Interface) *TCPNameServer {
+	if len(serverName) == 0 {
+		serverName = address.Address.String()
+	}
+	s := NewTCPNameServer(address, dispatcher)
+	s.tlsConfig = &tls.Config{
+		ServerName: serverName,
+	}
+	return s
+}
+
+func (s *TCPNameServer) dial() (*tcpConnection, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := s.dispatcher.Dispatch(ctx, s.address)
+	if err != nil {
+		cancel()
+		return nil, newError("failed to dispatch to ", s.address).Base(err)
+	}
+	var conn net.Conn = outbound.NewConnection(stream)
+	if s.tlsConfig != nil {
+		conn = tls.Client(conn, s.tlsConfig)
+	}
+	return &tcpConnection{
+		Conn:     conn,
+		cancel:   cancel,
+		requests: make(map[uint16]*PendingRequest),
+	}, nil
+}
+
+// assignRequest registers a pending request on the current connection, or a new one if there isn't.
+func (s *TCPNameServer) assignRequest(response chan<- *ARecord) (*tcpConnection, uint16, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dial()
+		if err != nil {
+			return nil, 0, err
+		}
+		s.conn = conn
+		go s.readResponses(conn)
+	}
+	conn := s.conn
+
+	now := time.Now()
+	for id, r := range conn.requests {
+		if r.expire.Before(now) {
+			delete(conn.requests, id)
+			close(r.response)
+		}
+	}
+
+	for {
+		id := dice.RollUint16()
+		if _, found := conn.requests[id]; found {
+			continue
+		}
+		conn.requests[id] = &PendingRequest{
+			expire:   now.Add(QueryTimeout),
+			response: response,
+		}
+		return conn, id, nil
+	}
+}
+
+// closeConnection closes the given connection, and fails all requests pending on it.
+func (s *TCPNameServer) closeConnection(conn *tcpConnection) {
+	s.Lock()
+	if s.conn == conn {
+		s.conn = nil
+	}
+	requests := conn.requests
+	conn.requests = make(map[uint16]*PendingRequest)
+	s.Unlock()
+
+	for _, r := range requests {
+		close(r.response)
+	}
+	conn.Close()
+	conn.cancel()
+}
+
+// cancelRequest removes the given request from the connection, and closes its response channel.
+func (s *TCPNameServer) cancelRequest(conn *tcpConnection, id uint16) {
+	s.Lock()
+	request, found := conn.requests[id]
+	if found {
+		delete(conn.requests, id)
+	}
+	s.Unlock()
+
+	if found {
+		close(request.response)
+	}
+}
+
+func (s *TCPNameServer) readResponses(conn *tcpConnection) {
+	defer s.closeConnection(conn)
+
+	var length [2]byte
+	for {
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			log.Trace(newError("failed to read response from ", s.address).Base(err).AtDebug())
+			return
+		}
+		payload := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			log.Trace(newError("failed to read response from ", s.address).Base(err).AtDebug())
+			return
+		}
+
+		msg := new(dns.Msg)
+		if err := msg.Unpack(payload); err != nil {
+			log.Trace(newError("failed to parse DNS response").Base(err).AtWarning())
+			continue
+		}
+
+		s.Lock()
+		request, found := conn.requests[msg.Id]
+		if found {
+			delete(conn.requests, msg.Id)
+		}
+		s.Unlock()
+
+		if found {
+			request.response <- parseResponse(msg)
+			close(request.response)
+		}
+	}
+}
+
+// QueryIP implements NameServer.
+func (s *TCPNameServer) QueryIP(domain string, qtype uint16, subnet *net.IPNet) <-chan *ARecord {
+	response := make(chan *ARecord, 1)
+
+	go func() {
+		conn, id, err := s.assignRequest(response)
+		if err != nil {
+			log.Trace(newError("failed to query ", domain, " on ", s.address).Base(err).AtWarning())
+			close(response)
+			return
+		}
+
+		payload, err := buildQuery(domain, id, qtype, subnet).Pack()
+		if err != nil {
+			log.Trace(newError("failed to build query for ", domain).Base(err).AtWarning())
+			s.cancelRequest(conn, id)
+			return
+		}
+		frame := make([]byte, 2+len(payload))
+		binary.BigEndian.PutUint16(frame, uint16(len(payload)))
+		copy(frame[2:], payload)
+
+		conn.writeLock.Lock()
+		_, err = conn.Write(frame)
+		conn.writeLock.Unlock()
+		if err != nil {
+			log.Trace(newError("failed to send query to ", s.address).Base(err).AtWarning())
+			s.closeConnection(conn)
+		}
+	}()
+
+	return response
+}
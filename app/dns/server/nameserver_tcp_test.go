@@ -0,0 +1,75 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+	tlsgen "v2ray.com/core/testing/tls"
+)
+
+func TestTLSNameServer(t *testing.T) {
+	assert := assert.On(t)
+
+	cert := tlsgen.GenerateCertificateForTest()
+	keyPair, err := tls.X509KeyPair(cert.Certificate, cert.Key)
+	assert.Error(err).IsNil()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+	})
+	assert.Error(err).IsNil()
+
+	dnsServer := &dns.Server{
+		Listener: listener,
+		Net:      "tcp-tls",
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := new(dns.Msg).SetReply(r)
+			rr, err := dns.NewRR(r.Question[0].Name + " 60 IN A 127.0.0.4")
+			assert.Error(err).IsNil()
+			msg.Answer = append(msg.Answer, rr)
+			w.WriteMsg(msg)
+		}),
+	}
+	go dnsServer.ActivateAndServe()
+	defer dnsServer.Shutdown()
+
+	block, _ := pem.Decode(cert.Certificate)
+	x509Cert, err := x509.ParseCertificate(block.Bytes)
+	assert.Error(err).IsNil()
+	roots := x509.NewCertPool()
+	roots.AddCert(x509Cert)
+
+	dispatcher := new(testDispatcher)
+	nameServer := NewTLSNameServer(v2net.DestinationFromAddr(listener.Addr()), "www.v2ray.com", dispatcher)
+	nameServer.tlsConfig.RootCAs = roots
+
+	for i := 0; i < 3; i++ {
+		select {
+		case record, open := <-nameServer.QueryIP("v2ray.com", dns.TypeA, nil):
+			assert.Bool(open).IsTrue()
+			assert.Int(len(record.IPs)).Equals(1)
+			assert.String(record.IPs[0].String()).Equals("127.0.0.4")
+		case <-time.After(time.Second * 5):
+			t.Fatal("timeout")
+		}
+	}
+
+	// Queries are sent over the same TLS connection.
+	assert.Int(int(atomic.LoadInt32(&dispatcher.dispatched))).Equals(1)
+
+	// The certificate doesn't match the server name.
+	nameServer = NewTLSNameServer(v2net.DestinationFromAddr(listener.Addr()), "", dispatcher)
+	nameServer.tlsConfig.RootCAs = roots
+	select {
+	case _, open := <-nameServer.QueryIP("v2ray.com", dns.TypeA, nil):
+		assert.Bool(open).IsFalse()
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout")
+	}
+}
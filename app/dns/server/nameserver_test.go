@@ -0,0 +1,116 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	. "v2ray.com/core/app/dns/server"
+	"v2ray.com/core/common/buf"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/ray"
+)
+
+// directDispatcher connects to destinations directly, without going through any outbound handler.
+type directDispatcher struct {
+	sync.Mutex
+	dispatched int
+}
+
+func (d *directDispatcher) Dispatch(ctx context.Context, dest v2net.Destination) (ray.InboundRay, error) {
+	conn, err := net.Dial(dest.Network.SystemString(), dest.NetAddr())
+	if err != nil {
+		return nil, err
+	}
+	d.Lock()
+	d.dispatched++
+	d.Unlock()
+
+	stream := ray.NewRay(ctx)
+	go func() {
+		buf.Copy(stream.OutboundInput(), buf.NewWriter(conn))
+		conn.Close()
+	}()
+	go func() {
+		buf.Copy(buf.NewReader(conn), stream.OutboundOutput())
+		stream.OutboundOutput().Close()
+	}()
+	return stream, nil
+}
+
+// startTCPDNSServer starts a DNS server on TCP, which answers A queries with ip, and AAAA queries with ::2.
+func startTCPDNSServer(assert *assert.Assert, ip string) (*dns.Server, v2net.Destination) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Error(err).IsNil()
+
+	server := &dns.Server{
+		Listener: listener,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := new(dns.Msg).SetReply(r)
+			for _, q := range r.Question {
+				switch {
+				case q.Qtype == dns.TypeA:
+					rr, err := dns.NewRR(q.Name + " 60 IN A " + ip)
+					assert.Error(err).IsNil()
+					msg.Answer = append(msg.Answer, rr)
+				case q.Qtype == dns.TypeAAAA && q.Name != "ipv4.v2ray.com.":
+					rr, err := dns.NewRR(q.Name + " 60 IN AAAA ::2")
+					assert.Error(err).IsNil()
+					msg.Answer = append(msg.Answer, rr)
+				}
+			}
+			w.WriteMsg(msg)
+		}),
+	}
+	go server.ActivateAndServe()
+	return server, v2net.DestinationFromAddr(listener.Addr())
+}
+
+func TestTCPNameServer(t *testing.T) {
+	assert := assert.On(t)
+
+	dnsServer, dest := startTCPDNSServer(assert, "127.0.0.2")
+	defer dnsServer.Shutdown()
+
+	dispatcher := new(directDispatcher)
+	nameServer := NewTCPNameServer(dest, dispatcher)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case record, open := <-nameServer.QueryIP("v2ray.com", dns.TypeA, nil):
+				assert.Bool(open).IsTrue()
+				assert.Int(len(record.IPs)).Equals(1)
+				assert.String(record.IPs[0].String()).Equals("127.0.0.2")
+			case <-time.After(time.Second * 5):
+				t.Error("timeout")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// All queries are sent over the same connection.
+	assert.Int(dispatcher.dispatched).Equals(1)
+}
+
+func TestUDPNameServerBuildQueryA(t *testing.T) {
+	assert := assert.On(t)
+
+	nameServer := NewUDPNameServer(v2net.UDPDestination(v2net.LocalHostIP, 53), new(directDispatcher))
+	b := nameServer.BuildQueryA("v2ray.com", 1)
+	defer b.Release()
+
+	msg := new(dns.Msg)
+	assert.Error(msg.Unpack(b.Bytes())).IsNil()
+	assert.Int(int(msg.Id)).Equals(1)
+	assert.Int(len(msg.Question)).Equals(1)
+	assert.String(msg.Question[0].Name).Equals("v2ray.com.")
+	assert.Int(int(msg.Question[0].Qtype)).Equals(int(dns.TypeA))
+}
@@ -5,31 +5,96 @@ package server
 import (
 	"context"
 	"net"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	dnsmsg "github.com/miekg/dns"
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/dns/resolver"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/router"
 	"v2ray.com/core/common"
 	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
 )
 
 const (
 	QueryTimeout = time.Second * 8
+
+	// MaxStaleTime is how long expired records may be returned for, when serve stale is enabled.
+	MaxStaleTime = time.Hour * 24
+	// PrefetchWindow is how long before expiry records are refreshed, when prefetch is enabled.
+	PrefetchWindow = time.Second * 10
+	// PrefetchThreshold is the number of times a record must be used since last update before it is prefetched.
+	PrefetchThreshold = 3
+	// MaxAliasDepth is the maximum number of aliases in static hosts followed to resolve a domain.
+	MaxAliasDepth = 8
+)
+
+var (
+	_ resolver.CachedServer  = (*CacheServer)(nil)
+	_ resolver.ResolveServer = (*CacheServer)(nil)
+	_ resolver.FakeDNS       = (*CacheServer)(nil)
 )
 
 type DomainRecord struct {
-	A *ARecord
+	A    *ARecord
+	AAAA *ARecord
+}
+
+// queryKey identifies a query by the cache key of the request and the query type.
+type queryKey struct {
+	key   string
+	qtype uint16
+}
+
+// lookupRequest is a lookup of a domain on behalf of a client.
+type lookupRequest struct {
+	// domain is the fully qualified domain.
+	domain string
+	// client is the subnet of the client, or nil if the answer doesn't depend on the client.
+	client *net.IPNet
+	// prioritized is the number of servers preferred for the domain, which are queried before the others.
+	prioritized int
+}
+
+// key returns the key of the request in the cache. Answers for different client subnets are cached separately.
+func (r lookupRequest) key() string {
+	if r.client == nil {
+		return r.domain
+	}
+	return r.domain + "@" + r.client.String()
+}
+
+// pendingQuery is a query in progress, shared by concurrent lookups of the same record.
+type pendingQuery struct {
+	done   chan struct{}
+	record *ARecord
+	err    error
 }
 
 type CacheServer struct {
+	hits   uint64
+	misses uint64
 	sync.RWMutex
-	hosts   map[string]net.IP
-	records map[string]*DomainRecord
-	servers []NameServer
+	hosts       *StaticHosts
+	cache       *recordCache
+	nextCleanup time.Time
+	pending     map[queryKey]*pendingQuery
+	servers     []*serverEntry
+	strategy    dns.QueryStrategy
+	negativeTTL time.Duration
+	serveStale  bool
+	prefetch    bool
+	concurrency int
+	fakeDNS     *FakeIPPool
+	fakeConfig  *dns.FakeDns
+	disp        dispatcher.Interface
 }
 
 func NewCacheServer(ctx context.Context, config *dns.Config) (*CacheServer, error) {
@@ -38,90 +103,680 @@ func NewCacheServer(ctx context.Context, config *dns.Config) (*CacheServer, erro
 		return nil, newError("no space in context")
 	}
 	server := &CacheServer{
-		records: make(map[string]*DomainRecord),
-		servers: make([]NameServer, len(config.NameServers)),
-		hosts:   config.GetInternalHosts(),
+		pending: make(map[queryKey]*pendingQuery),
 	}
+	server.applyConfig(config)
+	server.fakeDNS, server.fakeConfig = newFakeIPPool(config.FakeDns), config.FakeDns
+	// The dispatcher is not a dependency of the DNS server, as the dispatcher depends on the router, which in turn
+	// depends on the DNS server. It is resolved once all apps are added to the space.
 	space.OnInitialize(func() error {
 		disp := dispatcher.FromSpace(space)
 		if disp == nil {
 			return newError("dispatcher is not found in the space")
 		}
-		for idx, destPB := range config.NameServers {
-			address := destPB.Address.AsAddress()
-			if address.Family().IsDomain() && address.Domain() == "localhost" {
-				server.servers[idx] = &LocalNameServer{}
-			} else {
-				dest := destPB.AsDestination()
-				if dest.Network == v2net.Network_Unknown {
-					dest.Network = v2net.Network_UDP
-				}
-				if dest.Network == v2net.Network_UDP {
-					server.servers[idx] = NewUDPNameServer(dest, disp)
-				}
-			}
-		}
-		if len(config.NameServers) == 0 {
-			server.servers = append(server.servers, &LocalNameServer{})
+		server.disp = disp
+		servers, err := server.buildNameServers(config)
+		if err != nil {
+			return err
 		}
+		server.servers = servers
 		return nil
 	})
 	return server, nil
 }
 
+// newFakeIPPool creates a FakeIPPool based on the given config, and loads saved fake IPs if there are.
+// It returns nil if the config is nil.
+func newFakeIPPool(config *dns.FakeDns) *FakeIPPool {
+	if config == nil {
+		return nil
+	}
+	pool := NewFakeIPPool(config.GetIPPoolValue(), config.GetPoolSizeValue())
+	if len(config.PersistenceFile) > 0 {
+		if err := pool.Load(config.PersistenceFile); err != nil && !os.IsNotExist(err) {
+			log.Trace(newError("failed to load fake IPs").Base(err).AtWarning())
+		}
+	}
+	return pool
+}
+
+// saveFakeIPs saves fake IPs of the given pool, if persistence is enabled in the config.
+func saveFakeIPs(pool *FakeIPPool, config *dns.FakeDns) {
+	if pool == nil || len(config.PersistenceFile) == 0 {
+		return
+	}
+	if err := pool.Save(config.PersistenceFile); err != nil {
+		log.Trace(newError("failed to save fake IPs").Base(err).AtWarning())
+	}
+}
+
+// applyConfig applies the given config, except name servers. The caller must hold the lock if necessary.
+func (s *CacheServer) applyConfig(config *dns.Config) {
+	s.hosts = buildStaticHosts(config)
+	s.cache = newRecordCache(config.GetCacheSizeValue())
+	s.nextCleanup = time.Now().Add(CleanupInterval)
+	s.strategy = config.QueryStrategy
+	s.negativeTTL = config.GetNegativeTTLValue()
+	s.serveStale = config.ServeStale
+	s.prefetch = config.Prefetch
+	s.concurrency = int(config.Concurrency)
+}
+
+// serverEntry is a NameServer with the domains it is preferred for, and the IP ranges its answers are expected in.
+type serverEntry struct {
+	NameServer
+	domains          router.Condition
+	expectedIPs      router.Condition
+	clientSubnet     *net.IPNet
+	subnetFromSource bool
+}
+
+// matchDomain returns true if the server is preferred for the given domain.
+func (e *serverEntry) matchDomain(domain string) bool {
+	if e.domains == nil {
+		return false
+	}
+	return e.domains.Apply(proxy.ContextWithTarget(context.Background(), v2net.TCPDestination(v2net.DomainAddress(domain), 0)))
+}
+
+// filterIPs returns the IPs that are in the expected IP ranges of the server.
+func (e *serverEntry) filterIPs(ips []net.IP) []net.IP {
+	if e.expectedIPs == nil {
+		return ips
+	}
+	filtered := make([]net.IP, 0, len(ips))
+	for _, ip := range ips {
+		if e.expectedIPs.Apply(proxy.ContextWithTarget(context.Background(), v2net.TCPDestination(v2net.IPAddress(ip), 0))) {
+			filtered = append(filtered, ip)
+		}
+	}
+	return filtered
+}
+
+// subnet returns the client subnet to send to the server, given the subnet of the requesting client, which may be nil.
+func (e *serverEntry) subnet(client *net.IPNet) *net.IPNet {
+	if e.subnetFromSource && client != nil {
+		return client
+	}
+	return e.clientSubnet
+}
+
+// query queries the given domain on the server. It returns nil if the server doesn't answer in time,
+// or none of the IPs in the answer is expected.
+func (e *serverEntry) query(domain string, qtype uint16, client *net.IPNet) *ARecord {
+	select {
+	case a, open := <-e.QueryIP(domain, qtype, e.subnet(client)):
+		if !open || a == nil {
+			return nil
+		}
+		ips := e.filterIPs(a.IPs)
+		if len(ips) == len(a.IPs) {
+			return a
+		}
+		if len(ips) == 0 {
+			log.Trace(newError("discarding unexpected IPs ", a.IPs, " for domain ", domain).AtWarning())
+			return nil
+		}
+		return &ARecord{
+			IPs:    ips,
+			Expire: a.Expire,
+		}
+	case <-time.After(QueryTimeout):
+		return nil
+	}
+}
+
+// buildNameServers builds all name servers in the given config. LocalNameServer is used if there is none.
+func (s *CacheServer) buildNameServers(config *dns.Config) ([]*serverEntry, error) {
+	servers := make([]*serverEntry, 0, len(config.NameServers)+len(config.NameServer))
+	for _, destPB := range config.NameServers {
+		server, err := s.buildNameServer(&dns.NameServer{Address: destPB})
+		if err != nil {
+			return nil, err
+		}
+		if server != nil {
+			servers = append(servers, &serverEntry{NameServer: server})
+		}
+	}
+	for _, ns := range config.NameServer {
+		entry, err := buildServerEntry(ns)
+		if err != nil {
+			return nil, newError("invalid domains, IPs or client subnet of name server").Base(err)
+		}
+		server, err := s.buildNameServer(ns)
+		if err != nil {
+			return nil, err
+		}
+		if server != nil {
+			entry.NameServer = server
+			servers = append(servers, entry)
+		}
+	}
+	if len(servers) == 0 {
+		servers = append(servers, &serverEntry{NameServer: &LocalNameServer{}})
+	}
+	return servers, nil
+}
+
+// buildServerEntry builds the conditions of prioritized domains and expected IPs of the given name server.
+// They are matched in the same way as routing rules.
+func buildServerEntry(config *dns.NameServer) (*serverEntry, error) {
+	entry := new(serverEntry)
+	if len(config.PrioritizedDomain) > 0 {
+		matcher, err := router.NewDomainMatcher(config.PrioritizedDomain)
+		if err != nil {
+			return nil, err
+		}
+		entry.domains = matcher
+	}
+	if len(config.ExpectedIp) > 0 {
+		matcher, err := router.NewIPMatcher(config.ExpectedIp, false)
+		if err != nil {
+			return nil, err
+		}
+		entry.expectedIPs = matcher
+	}
+	if subnet := config.ClientSubnet; subnet != nil {
+		if len(subnet.Ip) != net.IPv4len && len(subnet.Ip) != net.IPv6len {
+			return nil, newError("invalid client subnet: ", subnet.Ip)
+		}
+		mask := net.CIDRMask(int(subnet.Prefix), len(subnet.Ip)*8)
+		entry.clientSubnet = &net.IPNet{
+			IP:   net.IP(subnet.Ip).Mask(mask),
+			Mask: mask,
+		}
+	}
+	entry.subnetFromSource = config.ClientSubnetFromSource
+	return entry, nil
+}
+
+// sortServers returns the servers to query for the given domain, and the number of servers preferred for the domain.
+// Preferred servers come first, and the others follow, both in the configured order.
+func sortServers(domain string, servers []*serverEntry) ([]*serverEntry, int) {
+	sorted := make([]*serverEntry, 0, len(servers))
+	for _, server := range servers {
+		if server.matchDomain(domain) {
+			sorted = append(sorted, server)
+		}
+	}
+	prioritized := len(sorted)
+	if prioritized == 0 {
+		return servers, 0
+	}
+	for _, server := range servers {
+		if !server.matchDomain(domain) {
+			sorted = append(sorted, server)
+		}
+	}
+	return sorted, prioritized
+}
+
+func (s *CacheServer) buildNameServer(config *dns.NameServer) (NameServer, error) {
+	if config.Protocol == dns.NameServer_HTTPS {
+		var dest v2net.Destination
+		if config.Address != nil && config.Address.Address != nil {
+			dest = config.Address.AsDestination()
+			if dest.Port == 0 {
+				dest.Port = v2net.Port(443)
+			}
+		}
+		server, err := NewDoHNameServer(config.Url, dest, config.ServerName, s.disp)
+		if err != nil {
+			return nil, newError("invalid DNS over HTTPS server").Base(err)
+		}
+		return server, nil
+	}
+
+	if config.Address == nil || config.Address.Address == nil {
+		return nil, newError("name server has no address")
+	}
+	address := config.Address.Address.AsAddress()
+	if address.Family().IsDomain() && address.Domain() == "localhost" {
+		return &LocalNameServer{}, nil
+	}
+
+	dest := config.Address.AsDestination()
+	switch config.Protocol {
+	case dns.NameServer_TLS:
+		if dest.Port == 0 {
+			dest.Port = v2net.Port(853)
+		}
+		return NewTLSNameServer(dest, config.ServerName, s.disp), nil
+	default:
+		if dest.Port == 0 {
+			dest.Port = v2net.Port(53)
+		}
+		switch dest.Network {
+		case v2net.Network_Unknown, v2net.Network_UDP:
+			dest.Network = v2net.Network_UDP
+			return NewUDPNameServer(dest, s.disp), nil
+		case v2net.Network_TCP:
+			return NewTCPNameServer(dest, s.disp), nil
+		default:
+			return nil, newError("unsupported network of name server: ", dest)
+		}
+	}
+}
+
+// Reload implements app.Reloadable. Cached records are dropped. Fake IPs are kept, unless fake DNS settings change.
+func (s *CacheServer) Reload(config interface{}) error {
+	c, ok := config.(*dns.Config)
+	if !ok {
+		return newError("not a DNS config")
+	}
+	servers, err := s.buildNameServers(c)
+	if err != nil {
+		return err
+	}
+
+	s.RLock()
+	pool, fakeConfig := s.fakeDNS, s.fakeConfig
+	s.RUnlock()
+	if !proto.Equal(c.FakeDns, fakeConfig) {
+		saveFakeIPs(pool, fakeConfig)
+		pool, fakeConfig = newFakeIPPool(c.FakeDns), c.FakeDns
+	}
+
+	s.Lock()
+	s.servers = servers
+	s.applyConfig(c)
+	s.fakeDNS, s.fakeConfig = pool, fakeConfig
+	s.Unlock()
+
+	return nil
+}
+
 func (*CacheServer) Interface() interface{} {
-	return (*dns.Server)(nil)
+	return (*resolver.Server)(nil)
 }
 
 func (*CacheServer) Start() error {
 	return nil
 }
 
-func (*CacheServer) Close() {}
+// Close implements app.Application. Fake IPs are saved if persistence is enabled.
+func (s *CacheServer) Close() {
+	s.RLock()
+	pool, config := s.fakeDNS, s.fakeConfig
+	s.RUnlock()
 
-func (s *CacheServer) GetCached(domain string) []net.IP {
+	saveFakeIPs(pool, config)
+}
+
+// GetFakeIP implements resolver.FakeDNS. Domains in static hosts don't have fake IPs.
+func (s *CacheServer) GetFakeIP(domain string) net.IP {
 	s.RLock()
-	defer s.RUnlock()
+	pool := s.fakeDNS
+	_, _, isHost := s.hosts.Lookup(domain)
+	s.RUnlock()
 
-	if record, found := s.records[domain]; found && record.A.Expire.After(time.Now()) {
-		return record.A.IPs
+	if pool == nil || isHost {
+		return nil
+	}
+	return pool.GetFakeIP(domain)
+}
+
+// GetFakeDomain implements resolver.FakeDNS.
+func (s *CacheServer) GetFakeDomain(ip net.IP) (string, bool) {
+	s.RLock()
+	pool := s.fakeDNS
+	s.RUnlock()
+
+	if pool == nil {
+		return "", false
+	}
+	return pool.GetDomain(ip)
+}
+
+// GetCached returns the cached IPv4 addresses of the given fully qualified domain.
+//
+// Deprecated: Use GetCachedByType instead.
+func (s *CacheServer) GetCached(domain string) []net.IP {
+	return s.GetCachedByType(domain, dnsmsg.TypeA)
+}
+
+// GetCachedByType returns the cached IPs of the given fully qualified domain for the given query type, either
+// dns.TypeA or dns.TypeAAAA. It returns nil if there is no unexpired record, or an empty slice if the domain is known
+// to have no IP.
+func (s *CacheServer) GetCachedByType(domain string, qtype uint16) []net.IP {
+	s.Lock()
+	defer s.Unlock()
+
+	if entry := s.cache.Get(domain); entry != nil {
+		if a := entry.get(qtype); a != nil && a.Expire.After(time.Now()) {
+			return a.IPs
+		}
 	}
 	return nil
 }
 
+// CacheStats implements resolver.CachedServer.
+func (s *CacheServer) CacheStats() resolver.CacheStats {
+	s.RLock()
+	size := s.cache.Len()
+	s.RUnlock()
+
+	return resolver.CacheStats{
+		Size:   size,
+		Hits:   atomic.LoadUint64(&s.hits),
+		Misses: atomic.LoadUint64(&s.misses),
+	}
+}
+
+// Get implements resolver.Server. IPv4 and IPv6 addresses are returned according to the query strategy.
 func (s *CacheServer) Get(domain string) []net.IP {
-	if ip, found := s.hosts[domain]; found {
-		return []net.IP{ip}
+	ips, _ := s.get(domain, nil, 0)
+	return ips
+}
+
+// GetWithContext implements resolver.ContextServer. The subnet of the client in the context is sent to name servers
+// that are configured to use it.
+func (s *CacheServer) GetWithContext(ctx context.Context, domain string) []net.IP {
+	ips, _ := s.get(domain, sourceSubnet(ctx), 0)
+	return ips
+}
+
+// Resolve implements resolver.ResolveServer.
+func (s *CacheServer) Resolve(ctx context.Context, domain string) ([]net.IP, error) {
+	return s.get(domain, sourceSubnet(ctx), 0)
+}
+
+// get resolves the given domain for the given client subnet, which may be nil. depth is the number of aliases in
+// static hosts followed so far. The error is resolver.ErrNotFound if name servers answer without IP.
+func (s *CacheServer) get(domain string, client *net.IPNet, depth int) ([]net.IP, error) {
+	s.RLock()
+	hosts := s.hosts
+	servers := s.servers
+	strategy := s.strategy
+	s.RUnlock()
+
+	if ips, alias, found := hosts.Lookup(domain); found {
+		if len(alias) == 0 {
+			return filterHostIPs(ips, strategy), nil
+		}
+		if depth >= MaxAliasDepth {
+			err := newError("too many aliases in static hosts for domain ", domain).AtWarning()
+			log.Trace(err)
+			return nil, err
+		}
+		return s.get(alias, client, depth+1)
+	}
+
+	servers, prioritized := sortServers(domain, servers)
+	request := lookupRequest{
+		domain:      dnsmsg.Fqdn(domain),
+		prioritized: prioritized,
+	}
+	if client != nil && useSourceSubnet(servers) {
+		request.client = client
+	}
+	switch strategy {
+	case dns.QueryStrategy_USE_IP6:
+		return s.lookup(request, dnsmsg.TypeAAAA, servers)
+	case dns.QueryStrategy_PREFER_IP4:
+		ip4, ip6, err := s.lookupBoth(request, servers)
+		if len(ip4) > 0 {
+			return ip4, nil
+		}
+		if len(ip6) > 0 {
+			return ip6, nil
+		}
+		return nil, err
+	case dns.QueryStrategy_PREFER_IP6:
+		ip4, ip6, err := s.lookupBoth(request, servers)
+		if len(ip6) > 0 {
+			return ip6, nil
+		}
+		if len(ip4) > 0 {
+			return ip4, nil
+		}
+		return nil, err
+	default:
+		return s.lookup(request, dnsmsg.TypeA, servers)
+	}
+}
+
+// useSourceSubnet returns true if any of the servers sends the subnet of the requesting client.
+func useSourceSubnet(servers []*serverEntry) bool {
+	for _, server := range servers {
+		if server.subnetFromSource {
+			return true
+		}
 	}
+	return false
+}
 
-	domain = dnsmsg.Fqdn(domain)
-	ips := s.GetCached(domain)
-	if ips != nil {
+// filterHostIPs returns static IPs according to the query strategy. All IPs are returned if none is of the
+// requested families, so that static hosts are never resolved by name servers.
+func filterHostIPs(ips []net.IP, strategy dns.QueryStrategy) []net.IP {
+	var ip4, ip6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			ip4 = append(ip4, ip)
+		} else {
+			ip6 = append(ip6, ip)
+		}
+	}
+	switch {
+	case strategy == dns.QueryStrategy_USE_IP4 && len(ip4) > 0:
+		return ip4
+	case strategy == dns.QueryStrategy_USE_IP6 && len(ip6) > 0:
+		return ip6
+	case strategy == dns.QueryStrategy_PREFER_IP4:
+		if len(ip4) > 0 {
+			return ip4
+		}
+		return ip6
+	case strategy == dns.QueryStrategy_PREFER_IP6:
+		if len(ip6) > 0 {
+			return ip6
+		}
+		return ip4
+	default:
 		return ips
 	}
+}
 
-	for _, server := range s.servers {
-		response := server.QueryA(domain)
-		select {
-		case a, open := <-response:
-			if !open || a == nil {
-				continue
-			}
-			s.Lock()
-			s.records[domain] = &DomainRecord{
-				A: a,
+// lookupBoth queries A and AAAA records of the given domain in parallel. The error is resolver.ErrNotFound only if
+// both types are not found.
+func (s *CacheServer) lookupBoth(request lookupRequest, servers []*serverEntry) (ip4 []net.IP, ip6 []net.IP, err error) {
+	var err4, err6 error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		ip4, err4 = s.lookup(request, dnsmsg.TypeA, servers)
+	}()
+	go func() {
+		defer wg.Done()
+		ip6, err6 = s.lookup(request, dnsmsg.TypeAAAA, servers)
+	}()
+	wg.Wait()
+
+	if err4 != nil && err4 != resolver.ErrNotFound {
+		return ip4, ip6, err4
+	}
+	if err6 != nil {
+		return ip4, ip6, err6
+	}
+	return ip4, ip6, err4
+}
+
+// lookup returns IPs of the requested domain for the given query type, from cache or from name servers.
+// The error is resolver.ErrNotFound if there is no IP.
+func (s *CacheServer) lookup(request lookupRequest, qtype uint16, servers []*serverEntry) ([]net.IP, error) {
+	now := time.Now()
+
+	s.Lock()
+	var cached *ARecord
+	var hits uint32
+	if entry := s.cache.Get(request.key()); entry != nil {
+		entry.hits++
+		cached, hits = entry.get(qtype), entry.hits
+	}
+	serveStale, prefetch := s.serveStale, s.prefetch
+	s.Unlock()
+
+	if cached != nil {
+		if cached.Expire.After(now) {
+			atomic.AddUint64(&s.hits, 1)
+			if prefetch && hits >= PrefetchThreshold && cached.Expire.Sub(now) < PrefetchWindow {
+				s.refresh(request, qtype, servers)
 			}
-			s.Unlock()
-			log.Trace(newError("returning ", len(a.IPs), " IPs for domain ", domain).AtDebug())
-			return a.IPs
-		case <-time.After(QueryTimeout):
+			return recordIPs(cached)
 		}
+		// Failed lookups are not served stale, as they are likely to succeed on retry.
+		if serveStale && len(cached.IPs) > 0 && now.Sub(cached.Expire) < MaxStaleTime {
+			atomic.AddUint64(&s.hits, 1)
+			s.refresh(request, qtype, servers)
+			return cached.IPs, nil
+		}
+	}
+
+	atomic.AddUint64(&s.misses, 1)
+	a, err := s.query(request, qtype, servers)
+	if err != nil {
+		return nil, err
+	}
+	return recordIPs(a)
+}
+
+// recordIPs returns IPs of the given record, or resolver.ErrNotFound if it has none.
+func recordIPs(a *ARecord) ([]net.IP, error) {
+	if len(a.IPs) == 0 {
+		return nil, resolver.ErrNotFound
+	}
+	return a.IPs, nil
+}
+
+// refresh queries the requested domain in background, unless it is being queried already.
+func (s *CacheServer) refresh(request lookupRequest, qtype uint16, servers []*serverEntry) {
+	s.RLock()
+	_, found := s.pending[queryKey{key: request.key(), qtype: qtype}]
+	s.RUnlock()
+
+	if !found {
+		go s.query(request, qtype, servers)
+	}
+}
+
+// query queries the requested domain on name servers, and caches the answer. If no server answers, nothing is
+// cached, and an error is returned. Concurrent queries of the same record share one query.
+func (s *CacheServer) query(request lookupRequest, qtype uint16, servers []*serverEntry) (*ARecord, error) {
+	key := queryKey{key: request.key(), qtype: qtype}
+
+	s.Lock()
+	if p, found := s.pending[key]; found {
+		s.Unlock()
+		<-p.done
+		return p.record, p.err
+	}
+	p := &pendingQuery{
+		done: make(chan struct{}),
 	}
+	s.pending[key] = p
+	concurrency := s.concurrency
+	s.Unlock()
 
-	log.Trace(newError("returning nil for domain ", domain).AtDebug())
+	p.record, p.err = s.queryServers(request, qtype, servers, concurrency)
+	log.Trace(newError("returning ", len(p.record.IPs), " IPs of type ", dnsmsg.TypeToString[qtype], " for domain ", request.domain).AtDebug())
+
+	s.Lock()
+	delete(s.pending, key)
+	s.Unlock()
+	close(p.done)
+
+	return p.record, p.err
+}
+
+// queryServers queries the requested domain on the servers. Servers preferred for the domain are queried before
+// the others, and servers in the two groups are never queried in parallel.
+func (s *CacheServer) queryServers(request lookupRequest, qtype uint16, servers []*serverEntry, concurrency int) (*ARecord, error) {
+	for _, group := range [][]*serverEntry{servers[:request.prioritized], servers[request.prioritized:]} {
+		if a := queryGroup(request, qtype, group, concurrency); a != nil {
+			s.store(request.key(), qtype, a)
+			return a, nil
+		}
+	}
+
+	err := newError("no name server answers for domain ", request.domain).AtDebug()
+	log.Trace(err)
+	return &ARecord{
+		IPs: []net.IP{},
+	}, err
+}
+
+// queryGroup queries the requested domain on the first servers in parallel, as many as the concurrency, and then
+// on the rest one by one. It returns nil if no server answers.
+func queryGroup(request lookupRequest, qtype uint16, servers []*serverEntry, concurrency int) *ARecord {
+	if concurrency > len(servers) {
+		concurrency = len(servers)
+	}
+	if concurrency > 1 {
+		if a := queryParallel(request, qtype, servers[:concurrency]); a != nil {
+			return a
+		}
+		servers = servers[concurrency:]
+	}
+
+	for _, server := range servers {
+		if a := server.query(request.domain, qtype, request.client); a != nil {
+			return a
+		}
+	}
 	return nil
 }
 
+// queryParallel queries the requested domain on all the servers at the same time, and returns the first answer with
+// IPs. An answer without IP is returned only if no server answers with IPs, and nil if no server answers at all.
+func queryParallel(request lookupRequest, qtype uint16, servers []*serverEntry) *ARecord {
+	answers := make(chan *ARecord, len(servers))
+	for _, server := range servers {
+		go func(server *serverEntry) {
+			answers <- server.query(request.domain, qtype, request.client)
+		}(server)
+	}
+	var empty *ARecord
+	for range servers {
+		a := <-answers
+		if a == nil {
+			continue
+		}
+		if len(a.IPs) > 0 {
+			return a
+		}
+		if empty == nil {
+			empty = a
+		}
+	}
+	return empty
+}
+
+// store caches the given answer under the given key. Answers without IP, i.e., NXDOMAIN or no record of the type,
+// expire after the negative TTL at most. Failed lookups are never stored, so they don't replace existing records.
+func (s *CacheServer) store(key string, qtype uint16, a *ARecord) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	if len(a.IPs) == 0 {
+		if expire := now.Add(s.negativeTTL); a.Expire.After(expire) {
+			a.Expire = expire
+		}
+	}
+
+	s.cache.Put(key).set(qtype, a)
+
+	if now.After(s.nextCleanup) {
+		if s.serveStale {
+			s.cache.RemoveExpired(now.Add(-MaxStaleTime))
+		} else {
+			s.cache.RemoveExpired(now)
+		}
+		s.nextCleanup = now.Add(CleanupInterval)
+	}
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*dns.Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return NewCacheServer(ctx, config.(*dns.Config))
@@ -0,0 +1,231 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dnsmsg "github.com/miekg/dns"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/dns"
+	. "v2ray.com/core/app/dns/server"
+	"v2ray.com/core/app/router"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/testing/assert"
+)
+
+type dispatcherApp struct {
+	*directDispatcher
+}
+
+func (dispatcherApp) Interface() interface{} {
+	return (*dispatcher.Interface)(nil)
+}
+
+func (dispatcherApp) Start() error {
+	return nil
+}
+
+func (dispatcherApp) Close() {}
+
+func TestQueryStrategy(t *testing.T) {
+	assert := assert.On(t)
+
+	dnsServer, dest := startTCPDNSServer(assert, "127.0.0.2")
+	defer dnsServer.Shutdown()
+
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(dispatcherApp{new(directDispatcher)})).IsNil()
+
+	config := &dns.Config{
+		NameServer: []*dns.NameServer{tcpNameServer(dest)},
+	}
+	server, err := NewCacheServer(app.ContextWithSpace(context.Background(), space), config)
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	for _, tc := range []struct {
+		strategy dns.QueryStrategy
+		domain   string
+		expected string
+	}{
+		{dns.QueryStrategy_USE_IP4, "v2ray.com", "127.0.0.2"},
+		{dns.QueryStrategy_USE_IP6, "v2ray.com", "::2"},
+		{dns.QueryStrategy_PREFER_IP4, "v2ray.com", "127.0.0.2"},
+		{dns.QueryStrategy_PREFER_IP6, "v2ray.com", "::2"},
+		{dns.QueryStrategy_PREFER_IP6, "ipv4.v2ray.com", "127.0.0.2"},
+	} {
+		config.QueryStrategy = tc.strategy
+		assert.Error(server.Reload(config)).IsNil()
+
+		ips := server.Get(tc.domain)
+		assert.Int(len(ips)).Equals(1)
+		assert.String(ips[0].String()).Equals(tc.expected)
+	}
+}
+
+func tcpNameServer(dest v2net.Destination) *dns.NameServer {
+	return &dns.NameServer{
+		Address: &v2net.Endpoint{
+			Network: v2net.Network_TCP,
+			Address: v2net.NewIPOrDomain(dest.Address),
+			Port:    uint32(dest.Port),
+		},
+	}
+}
+
+func TestNameServerSelection(t *testing.T) {
+	assert := assert.On(t)
+
+	remoteServer, remoteDest := startTCPDNSServer(assert, "10.0.0.1")
+	defer remoteServer.Shutdown()
+	localServer, localDest := startTCPDNSServer(assert, "127.0.0.2")
+	defer localServer.Shutdown()
+
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(dispatcherApp{new(directDispatcher)})).IsNil()
+
+	remote := tcpNameServer(remoteDest)
+	local := tcpNameServer(localDest)
+	local.PrioritizedDomain = []*router.Domain{
+		{Type: router.Domain_Domain, Value: "v2ray.com"},
+	}
+	config := &dns.Config{
+		NameServer: []*dns.NameServer{remote, local},
+	}
+	server, err := NewCacheServer(app.ContextWithSpace(context.Background(), space), config)
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	ips := server.Get("www.v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.2")
+
+	ips = server.Get("example.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("10.0.0.1")
+
+	// Answers of the remote server are out of the expected range, so the local server is used.
+	remote.ExpectedIp = []*router.CIDR{
+		{Ip: []byte{127, 0, 0, 0}, Prefix: 8},
+	}
+	assert.Error(server.Reload(config)).IsNil()
+
+	ips = server.Get("example.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.0.0.2")
+}
+
+// startSlowDNSServer starts a DNS server on TCP, which answers A queries with 127.0.0.4 after the given delay.
+// It returns the number of queries received as well.
+func startSlowDNSServer(assert *assert.Assert, delay time.Duration) (*dnsmsg.Server, v2net.Destination, *int32) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Error(err).IsNil()
+
+	var queries int32
+	server := &dnsmsg.Server{
+		Listener: listener,
+		Handler: dnsmsg.HandlerFunc(func(w dnsmsg.ResponseWriter, r *dnsmsg.Msg) {
+			atomic.AddInt32(&queries, 1)
+			time.Sleep(delay)
+			msg := new(dnsmsg.Msg).SetReply(r)
+			rr, err := dnsmsg.NewRR(r.Question[0].Name + " 60 IN A 127.0.0.4")
+			assert.Error(err).IsNil()
+			msg.Answer = append(msg.Answer, rr)
+			w.WriteMsg(msg)
+		}),
+	}
+	go server.ActivateAndServe()
+	return server, v2net.DestinationFromAddr(listener.Addr()), &queries
+}
+
+func TestConcurrentQuery(t *testing.T) {
+	assert := assert.On(t)
+
+	// The dead server accepts connections but never answers.
+	deadListener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Error(err).IsNil()
+	defer deadListener.Close()
+
+	dnsServer, dest, queries := startSlowDNSServer(assert, time.Millisecond*100)
+	defer dnsServer.Shutdown()
+
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(dispatcherApp{new(directDispatcher)})).IsNil()
+
+	config := &dns.Config{
+		NameServer: []*dns.NameServer{
+			tcpNameServer(v2net.DestinationFromAddr(deadListener.Addr())),
+			tcpNameServer(dest),
+		},
+		Concurrency: 2,
+	}
+	server, err := NewCacheServer(app.ContextWithSpace(context.Background(), space), config)
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ips := server.Get("v2ray.com")
+			assert.Int(len(ips)).Equals(1)
+			assert.String(ips[0].String()).Equals("127.0.0.4")
+		}()
+	}
+	wg.Wait()
+
+	if time.Since(start) > time.Second*2 {
+		t.Error("lookups wait for the dead server")
+	}
+	// Concurrent lookups share one query.
+	assert.Int(int(atomic.LoadInt32(queries))).Equals(1)
+}
+
+func TestInvalidNameServer(t *testing.T) {
+	assert := assert.On(t)
+
+	newSpace := func() (context.Context, app.Space) {
+		space := app.NewSpace()
+		assert.Error(space.AddApplication(dispatcherApp{new(directDispatcher)})).IsNil()
+		return app.ContextWithSpace(context.Background(), space), space
+	}
+
+	for _, ns := range []*dns.NameServer{
+		{Protocol: dns.NameServer_TLS},
+		{Protocol: dns.NameServer_HTTPS, Url: "http://dns.v2ray.com/dns-query"},
+		{Address: &v2net.Endpoint{Network: v2net.Network_TCP}},
+		{
+			Address: &v2net.Endpoint{
+				Network: v2net.Network_RawTCP,
+				Address: v2net.NewIPOrDomain(v2net.LocalHostIP),
+			},
+		},
+		{
+			Address: &v2net.Endpoint{
+				Network: v2net.Network_TCP,
+				Address: v2net.NewIPOrDomain(v2net.LocalHostIP),
+			},
+			ClientSubnet: &router.CIDR{Ip: []byte{127, 0, 0}},
+		},
+	} {
+		ctx, space := newSpace()
+		_, err := NewCacheServer(ctx, &dns.Config{NameServer: []*dns.NameServer{ns}})
+		assert.Error(err).IsNil()
+		assert.Error(space.Initialize()).IsNotNil()
+	}
+
+	ctx, space := newSpace()
+	server, err := NewCacheServer(ctx, &dns.Config{})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+	assert.Error(server.Reload(&dns.Config{
+		NameServer: []*dns.NameServer{{Protocol: dns.NameServer_TLS}},
+	})).IsNotNil()
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"net"
+
+	"v2ray.com/core/proxy"
+)
+
+const (
+	// SourceSubnetPrefixIPv4 is the prefix length of the subnet sent for clients with IPv4 addresses.
+	SourceSubnetPrefixIPv4 = 24
+	// SourceSubnetPrefixIPv6 is the prefix length of the subnet sent for clients with IPv6 addresses.
+	SourceSubnetPrefixIPv6 = 56
+)
+
+// nonPublicNetworks are the IP ranges that are not routable on the Internet, so they are useless as client subnets.
+var nonPublicNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// isPublicIP returns true if the given IP is routable on the Internet.
+func isPublicIP(ip net.IP) bool {
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// sourceSubnet returns the subnet of the client in the given context. It returns nil if the client is unknown,
+// or doesn't have a public IP.
+func sourceSubnet(ctx context.Context) *net.IPNet {
+	source, ok := proxy.SourceFromContext(ctx)
+	if !ok || !source.IsValid() || source.Address.Family().IsDomain() {
+		return nil
+	}
+	ip := source.Address.IP()
+	if !isPublicIP(ip) {
+		return nil
+	}
+
+	var mask net.IPMask
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, mask = ip4, net.CIDRMask(SourceSubnetPrefixIPv4, 8*net.IPv4len)
+	} else {
+		mask = net.CIDRMask(SourceSubnetPrefixIPv6, 8*net.IPv6len)
+	}
+	return &net.IPNet{
+		IP:   ip.Mask(mask),
+		Mask: mask,
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	dnsmsg "github.com/miekg/dns"
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/router"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+)
+
+// subnetNameServer answers with an IP derived from the client subnet, and records the subnets it receives.
+type subnetNameServer struct {
+	sync.Mutex
+	subnets []*net.IPNet
+}
+
+func (s *subnetNameServer) QueryIP(domain string, qtype uint16, subnet *net.IPNet) <-chan *ARecord {
+	s.Lock()
+	s.subnets = append(s.subnets, subnet)
+	s.Unlock()
+
+	ip := net.IP{127, 0, 0, 1}
+	if subnet != nil {
+		ip = net.IP{127, subnet.IP[0], subnet.IP[1], subnet.IP[2]}
+	}
+	response := make(chan *ARecord, 1)
+	response <- &ARecord{
+		IPs:    []net.IP{ip},
+		Expire: time.Now().Add(time.Minute),
+	}
+	close(response)
+	return response
+}
+
+func contextWithSource(ip string) context.Context {
+	return proxy.ContextWithSource(context.Background(), v2net.UDPDestination(v2net.ParseAddress(ip), 5353))
+}
+
+func TestSourceSubnet(t *testing.T) {
+	assert := assert.On(t)
+
+	assert.String(sourceSubnet(contextWithSource("8.8.4.4")).String()).Equals("8.8.4.0/24")
+	assert.String(sourceSubnet(contextWithSource("2001:db8:1:2:3::1")).String()).Equals("2001:db8:1::/56")
+	assert.Pointer(sourceSubnet(contextWithSource("192.168.1.2"))).IsNil()
+	assert.Pointer(sourceSubnet(contextWithSource("::1"))).IsNil()
+	assert.Pointer(sourceSubnet(context.Background())).IsNil()
+}
+
+func TestBuildQueryWithClientSubnet(t *testing.T) {
+	assert := assert.On(t)
+
+	_, subnet, err := net.ParseCIDR("1.2.3.0/24")
+	assert.Error(err).IsNil()
+
+	msg := buildQuery("v2ray.com", 1, dnsmsg.TypeA, subnet)
+	opt := msg.IsEdns0()
+	assert.Pointer(opt).IsNotNil()
+	assert.Uint16(opt.UDPSize()).Equals(EDNSBufferSize)
+	assert.Int(len(opt.Option)).Equals(1)
+
+	option := opt.Option[0].(*dnsmsg.EDNS0_SUBNET)
+	assert.Uint16(option.Family).Equals(1)
+	assert.Int(int(option.SourceNetmask)).Equals(24)
+	assert.String(option.Address.String()).Equals("1.2.3.0")
+
+	// The option survives packing.
+	b, err := msg.Pack()
+	assert.Error(err).IsNil()
+	assert.Error(msg.Unpack(b)).IsNil()
+	assert.Pointer(msg.IsEdns0()).IsNotNil()
+
+	assert.Bool(buildQuery("v2ray.com", 1, dnsmsg.TypeA, nil).IsEdns0() == nil).IsTrue()
+}
+
+func TestClientSubnetFromSource(t *testing.T) {
+	assert := assert.On(t)
+
+	ns := &subnetNameServer{}
+	s := newTestCacheServer(&dns.Config{})
+	s.servers = []*serverEntry{{NameServer: ns, subnetFromSource: true}}
+
+	ips := s.GetWithContext(contextWithSource("8.8.4.4"), "v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.8.8.4")
+
+	// Clients in different subnets don't share cached answers.
+	ips = s.GetWithContext(contextWithSource("9.9.9.9"), "v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.9.9.9")
+
+	ips = s.GetWithContext(contextWithSource("8.8.4.8"), "v2ray.com")
+	assert.String(ips[0].String()).Equals("127.8.8.4")
+
+	ips = s.Get("v2ray.com")
+	assert.String(ips[0].String()).Equals("127.0.0.1")
+
+	ns.Lock()
+	assert.Int(len(ns.subnets)).Equals(3)
+	ns.Unlock()
+}
+
+func TestConfiguredClientSubnet(t *testing.T) {
+	assert := assert.On(t)
+
+	entry, err := buildServerEntry(&dns.NameServer{
+		ClientSubnet: &router.CIDR{
+			Ip:     []byte{10, 20, 30, 40},
+			Prefix: 16,
+		},
+	})
+	assert.Error(err).IsNil()
+	assert.String(entry.clientSubnet.String()).Equals("10.20.0.0/16")
+
+	ns := &subnetNameServer{}
+	entry.NameServer = ns
+	s := newTestCacheServer(&dns.Config{})
+	s.servers = []*serverEntry{entry}
+
+	// The configured subnet is used even if the client is known.
+	ips := s.GetWithContext(contextWithSource("8.8.4.4"), "v2ray.com")
+	assert.Int(len(ips)).Equals(1)
+	assert.String(ips[0].String()).Equals("127.10.20.0")
+
+	_, err = buildServerEntry(&dns.NameServer{
+		ClientSubnet: &router.CIDR{
+			Ip: []byte{10, 20, 30},
+		},
+	})
+	assert.Error(err).IsNotNil()
+}
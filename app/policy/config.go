@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"time"
+)
+
+// Duration converts Second to time.Duration.
+func (s *Second) Duration() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Second * time.Duration(s.Value)
+}
+
+// DurationOr converts Second to time.Duration, or returns the given default if it is not set.
+func (s *Second) DurationOr(d time.Duration) time.Duration {
+	if s == nil {
+		return d
+	}
+	return s.Duration()
+}
+
+// DefaultPolicy returns the Policy for user levels that are not configured. Its timeouts are not set, so that each
+// proxy keeps its own default timeouts.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Timeout: &Policy_Timeout{},
+		Buffer: &Policy_Buffer{
+			Connection: 0,
+		},
+	}
+}
+
+// OverrideWith overrides the settings in this Timeout with the ones specified in another.
+func (t *Policy_Timeout) OverrideWith(another *Policy_Timeout) {
+	if another.Handshake != nil {
+		t.Handshake = &Second{Value: another.Handshake.Value}
+	}
+	if another.ConnectionIdle != nil {
+		t.ConnectionIdle = &Second{Value: another.ConnectionIdle.Value}
+	}
+	if another.UplinkOnly != nil {
+		t.UplinkOnly = &Second{Value: another.UplinkOnly.Value}
+	}
+	if another.DownlinkOnly != nil {
+		t.DownlinkOnly = &Second{Value: another.DownlinkOnly.Value}
+	}
+}
+
+// OverrideWith overrides the settings in this Policy with the ones specified in another.
+func (p *Policy) OverrideWith(another *Policy) {
+	if another.Timeout != nil {
+		if p.Timeout == nil {
+			p.Timeout = new(Policy_Timeout)
+		}
+		p.Timeout.OverrideWith(another.Timeout)
+	}
+	if another.Buffer != nil && another.Buffer.Connection != 0 {
+		p.Buffer = &Policy_Buffer{
+			Connection: another.Buffer.Connection,
+		}
+	}
+}
+
+// WithConnectionIdle returns a copy of this Policy with connection idle timeout set to the given seconds.
+// It is for proxies that still carry the deprecated timeout setting.
+func (p *Policy) WithConnectionIdle(seconds uint32) *Policy {
+	np := &Policy{
+		Timeout: &Policy_Timeout{},
+		Buffer:  p.Buffer,
+	}
+	if p.Timeout != nil {
+		np.Timeout.OverrideWith(p.Timeout)
+	}
+	np.Timeout.ConnectionIdle = &Second{Value: seconds}
+	return np
+}
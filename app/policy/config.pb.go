@@ -0,0 +1,174 @@
+package policy
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Second struct {
+	Value uint32 `protobuf:"varint,1,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Second) Reset()                    { *m = Second{} }
+func (m *Second) String() string            { return proto.CompactTextString(m) }
+func (*Second) ProtoMessage()               {}
+func (*Second) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *Second) GetValue() uint32 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type Policy struct {
+	Timeout *Policy_Timeout `protobuf:"bytes,1,opt,name=timeout" json:"timeout,omitempty"`
+	Buffer  *Policy_Buffer  `protobuf:"bytes,2,opt,name=buffer" json:"buffer,omitempty"`
+}
+
+func (m *Policy) Reset()                    { *m = Policy{} }
+func (m *Policy) String() string            { return proto.CompactTextString(m) }
+func (*Policy) ProtoMessage()               {}
+func (*Policy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Policy) GetTimeout() *Policy_Timeout {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+func (m *Policy) GetBuffer() *Policy_Buffer {
+	if m != nil {
+		return m.Buffer
+	}
+	return nil
+}
+
+// Timeout is a message for timeout settings in various stages.
+type Policy_Timeout struct {
+	// Timeout for handshake, before the request of a connection is fully received.
+	Handshake *Second `protobuf:"bytes,1,opt,name=handshake" json:"handshake,omitempty"`
+	// Timeout for a connection with no data transferred in either direction.
+	ConnectionIdle *Second `protobuf:"bytes,2,opt,name=connection_idle,json=connectionIdle" json:"connection_idle,omitempty"`
+	// Timeout after the downlink of a connection is closed, while the uplink is still open.
+	UplinkOnly *Second `protobuf:"bytes,3,opt,name=uplink_only,json=uplinkOnly" json:"uplink_only,omitempty"`
+	// Timeout after the uplink of a connection is closed, while the downlink is still open.
+	DownlinkOnly *Second `protobuf:"bytes,4,opt,name=downlink_only,json=downlinkOnly" json:"downlink_only,omitempty"`
+}
+
+func (m *Policy_Timeout) Reset()                    { *m = Policy_Timeout{} }
+func (m *Policy_Timeout) String() string            { return proto.CompactTextString(m) }
+func (*Policy_Timeout) ProtoMessage()               {}
+func (*Policy_Timeout) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
+
+func (m *Policy_Timeout) GetHandshake() *Second {
+	if m != nil {
+		return m.Handshake
+	}
+	return nil
+}
+
+func (m *Policy_Timeout) GetConnectionIdle() *Second {
+	if m != nil {
+		return m.ConnectionIdle
+	}
+	return nil
+}
+
+func (m *Policy_Timeout) GetUplinkOnly() *Second {
+	if m != nil {
+		return m.UplinkOnly
+	}
+	return nil
+}
+
+func (m *Policy_Timeout) GetDownlinkOnly() *Second {
+	if m != nil {
+		return m.DownlinkOnly
+	}
+	return nil
+}
+
+type Policy_Buffer struct {
+	// Size of the buffer in each direction of a connection, in bytes.
+	// 0 for the default size, and negative value for unlimited.
+	Connection int32 `protobuf:"varint,1,opt,name=connection" json:"connection,omitempty"`
+}
+
+func (m *Policy_Buffer) Reset()                    { *m = Policy_Buffer{} }
+func (m *Policy_Buffer) String() string            { return proto.CompactTextString(m) }
+func (*Policy_Buffer) ProtoMessage()               {}
+func (*Policy_Buffer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 1} }
+
+func (m *Policy_Buffer) GetConnection() int32 {
+	if m != nil {
+		return m.Connection
+	}
+	return 0
+}
+
+type Config struct {
+	// Policies of user levels. Settings not specified in a level fall back to the default policy.
+	Level map[uint32]*Policy `protobuf:"bytes,1,rep,name=level" json:"level,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *Config) GetLevel() map[uint32]*Policy {
+	if m != nil {
+		return m.Level
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Second)(nil), "v2ray.core.app.policy.Second")
+	proto.RegisterType((*Policy)(nil), "v2ray.core.app.policy.Policy")
+	proto.RegisterType((*Policy_Timeout)(nil), "v2ray.core.app.policy.Policy.Timeout")
+	proto.RegisterType((*Policy_Buffer)(nil), "v2ray.core.app.policy.Policy.Buffer")
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.policy.Config")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/policy/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 384 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xcf, 0x4a, 0xeb, 0x40,
+	0x14, 0xc6, 0x49, 0x72, 0x9b, 0x72, 0x4f, 0x6f, 0xef, 0xbd, 0x0c, 0x16, 0x62, 0xc1, 0x52, 0x8a,
+	0x4a, 0x56, 0x13, 0x48, 0x37, 0xe2, 0x9f, 0x8a, 0x15, 0x05, 0x41, 0xb0, 0x44, 0x51, 0x70, 0x53,
+	0xd2, 0xc9, 0xd4, 0x86, 0x4e, 0x67, 0x86, 0x98, 0x54, 0xf2, 0x1a, 0xbe, 0x81, 0x5b, 0x1f, 0xc4,
+	0xe7, 0x92, 0x64, 0x12, 0xe2, 0xa2, 0x2d, 0xdd, 0x4d, 0x86, 0xdf, 0xf7, 0xcb, 0xc9, 0xc9, 0x07,
+	0x87, 0x4b, 0x37, 0xf2, 0x53, 0x4c, 0xc4, 0xc2, 0x21, 0x22, 0xa2, 0x8e, 0x2f, 0xa5, 0x23, 0x05,
+	0x0b, 0x49, 0xea, 0x10, 0xc1, 0xa7, 0xe1, 0x0b, 0x96, 0x91, 0x88, 0x05, 0x6a, 0x95, 0x5c, 0x44,
+	0xb1, 0x2f, 0x25, 0x56, 0x4c, 0xaf, 0x03, 0xe6, 0x3d, 0x25, 0x82, 0x07, 0x68, 0x07, 0x6a, 0x4b,
+	0x9f, 0x25, 0xd4, 0xd2, 0xba, 0x9a, 0xdd, 0xf4, 0xd4, 0x43, 0xef, 0xcb, 0x00, 0x73, 0x94, 0xa3,
+	0xe8, 0x1c, 0xea, 0x71, 0xb8, 0xa0, 0x22, 0x89, 0x73, 0xa4, 0xe1, 0x1e, 0xe0, 0x95, 0x4e, 0xac,
+	0x78, 0xfc, 0xa0, 0x60, 0xaf, 0x4c, 0xa1, 0x53, 0x30, 0x27, 0xc9, 0x74, 0x4a, 0x23, 0x4b, 0xcf,
+	0xf3, 0xfb, 0x9b, 0xf3, 0xc3, 0x9c, 0xf5, 0x8a, 0x4c, 0xfb, 0x5d, 0x87, 0x7a, 0xa1, 0x44, 0x27,
+	0xf0, 0x7b, 0xe6, 0xf3, 0xe0, 0x75, 0xe6, 0xcf, 0x69, 0x31, 0xcc, 0xde, 0x1a, 0x99, 0xfa, 0x3a,
+	0xaf, 0xe2, 0xd1, 0x35, 0xfc, 0x23, 0x82, 0x73, 0x4a, 0xe2, 0x50, 0xf0, 0x71, 0x18, 0x30, 0x6a,
+	0xe9, 0xdb, 0x28, 0xfe, 0x56, 0xa9, 0x9b, 0x80, 0x51, 0x34, 0x80, 0x46, 0x22, 0x59, 0xc8, 0xe7,
+	0x63, 0xc1, 0x59, 0x6a, 0x19, 0xdb, 0x38, 0x40, 0x25, 0xee, 0x38, 0x4b, 0xd1, 0x10, 0x9a, 0x81,
+	0x78, 0xe3, 0x95, 0xe1, 0xd7, 0x36, 0x86, 0x3f, 0x65, 0x26, 0x73, 0xb4, 0x6d, 0x30, 0xd5, 0x9a,
+	0x50, 0x07, 0xa0, 0x9a, 0x2f, 0xdf, 0x49, 0xcd, 0xfb, 0x71, 0xd3, 0xfb, 0xd0, 0xc0, 0xbc, 0xcc,
+	0x0b, 0x81, 0x06, 0x50, 0x63, 0x74, 0x49, 0x99, 0xa5, 0x75, 0x0d, 0xbb, 0xe1, 0xda, 0x6b, 0x5e,
+	0xa8, 0x68, 0x7c, 0x9b, 0xa1, 0x57, 0x3c, 0x8e, 0x52, 0x4f, 0xc5, 0xda, 0x4f, 0x00, 0xd5, 0x25,
+	0xfa, 0x0f, 0xc6, 0x9c, 0xa6, 0x45, 0x6b, 0xb2, 0x23, 0xea, 0x97, 0x4d, 0xda, 0xbc, 0x56, 0xf5,
+	0x9b, 0x8b, 0xa2, 0x1d, 0xeb, 0x47, 0xda, 0xf0, 0x0c, 0x76, 0x89, 0x58, 0xac, 0xc6, 0x47, 0xda,
+	0xb3, 0xa9, 0x4e, 0x9f, 0x7a, 0xeb, 0xd1, 0xf5, 0xfc, 0x6c, 0xc0, 0x88, 0xe2, 0x0b, 0x29, 0x0b,
+	0xd3, 0xc4, 0xcc, 0x9b, 0xde, 0xff, 0x1e, 0x00, 0xe7, 0x03, 0xe8, 0x00, 0x13, 0x03, 0x00, 0x00,
+}
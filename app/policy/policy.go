@@ -0,0 +1,96 @@
+package policy
+
+//go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg policy -path App,Policy
+
+import (
+	"context"
+	"sync"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/common"
+)
+
+// Manager is an app that provides Policy for user levels.
+type Manager interface {
+	// GetPolicy returns the Policy for the given user level. The returned Policy must not be modified.
+	GetPolicy(level uint32) *Policy
+}
+
+// DefaultManager is the default implementation of Manager.
+type DefaultManager struct {
+	sync.RWMutex
+	levels map[uint32]*Policy
+}
+
+// NewDefaultManager creates a new DefaultManager with the given config.
+func NewDefaultManager(ctx context.Context, config *Config) (*DefaultManager, error) {
+	return &DefaultManager{
+		levels: buildLevels(config),
+	}, nil
+}
+
+func buildLevels(config *Config) map[uint32]*Policy {
+	levels := make(map[uint32]*Policy, len(config.Level))
+	for level, p := range config.Level {
+		dp := DefaultPolicy()
+		dp.OverrideWith(p)
+		levels[level] = dp
+	}
+	return levels
+}
+
+var defaultPolicy = DefaultPolicy()
+
+// GetPolicy implements Manager.
+func (m *DefaultManager) GetPolicy(level uint32) *Policy {
+	m.RLock()
+	defer m.RUnlock()
+
+	if p, found := m.levels[level]; found {
+		return p
+	}
+	return defaultPolicy
+}
+
+// Reload implements app.Reloadable.
+func (m *DefaultManager) Reload(config interface{}) error {
+	c, ok := config.(*Config)
+	if !ok {
+		return newError("not a policy config")
+	}
+	levels := buildLevels(c)
+
+	m.Lock()
+	m.levels = levels
+	m.Unlock()
+
+	return nil
+}
+
+// Interface implements app.Application.
+func (*DefaultManager) Interface() interface{} {
+	return (*Manager)(nil)
+}
+
+// Start implements app.Application.
+func (*DefaultManager) Start() error {
+	return nil
+}
+
+// Close implements app.Application.
+func (*DefaultManager) Close() {}
+
+// FromSpace returns the policy Manager in the given space, or nil if not present.
+func FromSpace(space app.Space) Manager {
+	a := space.GetApplication((*Manager)(nil))
+	if a == nil {
+		return nil
+	}
+	return a.(Manager)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewDefaultManager(ctx, config.(*Config))
+	}))
+}
@@ -0,0 +1,74 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2ray.com/core/app"
+	. "v2ray.com/core/app/policy"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestPolicyOverride(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, &Config{
+		Level: map[uint32]*Policy{
+			0: {
+				Timeout: &Policy_Timeout{
+					Handshake: &Second{Value: 2},
+				},
+			},
+			1: {
+				Timeout: &Policy_Timeout{
+					Handshake:      &Second{Value: 8},
+					ConnectionIdle: &Second{Value: 600},
+					UplinkOnly:     &Second{Value: 0},
+					DownlinkOnly:   &Second{Value: 5},
+				},
+				Buffer: &Policy_Buffer{
+					Connection: 4096,
+				},
+			},
+		},
+	})).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	pm := FromSpace(space)
+
+	p0 := pm.GetPolicy(0)
+	assert.Int64(int64(p0.Timeout.Handshake.DurationOr(time.Second * 4))).Equals(int64(time.Second * 2))
+	// Timeouts not configured keep the defaults of proxies.
+	assert.Int64(int64(p0.Timeout.ConnectionIdle.DurationOr(time.Second * 300))).Equals(int64(time.Second * 300))
+
+	p1 := pm.GetPolicy(1)
+	assert.Int64(int64(p1.Timeout.Handshake.DurationOr(time.Second * 4))).Equals(int64(time.Second * 8))
+	assert.Int64(int64(p1.Timeout.ConnectionIdle.DurationOr(time.Second * 300))).Equals(int64(time.Second * 600))
+	assert.Int64(int64(p1.Timeout.UplinkOnly.DurationOr(time.Second * 2))).Equals(0)
+	assert.Int64(int64(p1.Timeout.DownlinkOnly.DurationOr(time.Second * 2))).Equals(int64(time.Second * 5))
+	assert.Int64(int64(p1.Buffer.Connection)).Equals(4096)
+
+	// Levels not configured use the default policy.
+	p2 := pm.GetPolicy(2)
+	assert.Int64(int64(p2.Timeout.Handshake.DurationOr(time.Second * 4))).Equals(int64(time.Second * 4))
+
+	p3 := p1.WithConnectionIdle(10)
+	assert.Int64(int64(p3.Timeout.ConnectionIdle.DurationOr(time.Second * 300))).Equals(int64(time.Second * 10))
+	assert.Int64(int64(p3.Timeout.DownlinkOnly.DurationOr(time.Second * 2))).Equals(int64(time.Second * 5))
+	assert.Int64(int64(p1.Timeout.ConnectionIdle.DurationOr(time.Second * 300))).Equals(int64(time.Second * 600))
+}
+
+func TestDefaultPolicyKeepsProxyDefaults(t *testing.T) {
+	assert := assert.On(t)
+
+	p := DefaultPolicy()
+	assert.Int64(int64(p.Timeout.ConnectionIdle.DurationOr(time.Minute * 2))).Equals(int64(time.Minute * 2))
+	assert.Int64(int64(p.Timeout.Handshake.DurationOr(time.Second * 16))).Equals(int64(time.Second * 16))
+
+	p = p.WithConnectionIdle(30)
+	assert.Int64(int64(p.Timeout.ConnectionIdle.DurationOr(time.Minute * 2))).Equals(int64(time.Second * 30))
+	assert.Int64(int64(p.Timeout.UplinkOnly.DurationOr(time.Second * 30))).Equals(int64(time.Second * 30))
+}
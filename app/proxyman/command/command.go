@@ -0,0 +1,114 @@
+package command
+
+//go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg command -path App,Proxyman,Command
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common"
+)
+
+type handlerServer struct {
+	ctx context.Context
+	ihm proxyman.InboundHandlerManager
+	ohm proxyman.OutboundHandlerManager
+}
+
+func (s *handlerServer) AddInbound(ctx context.Context, request *AddInboundRequest) (*AddInboundResponse, error) {
+	if request.Inbound == nil {
+		return nil, newError("empty inbound config")
+	}
+	if err := s.ihm.AddHandler(s.ctx, request.Inbound); err != nil {
+		return nil, err
+	}
+	return &AddInboundResponse{}, nil
+}
+
+func (s *handlerServer) RemoveInbound(ctx context.Context, request *RemoveInboundRequest) (*RemoveInboundResponse, error) {
+	if err := s.ihm.RemoveHandler(s.ctx, request.Tag); err != nil {
+		return nil, err
+	}
+	return &RemoveInboundResponse{}, nil
+}
+
+func (s *handlerServer) ListInbounds(ctx context.Context, request *ListInboundsRequest) (*ListInboundsResponse, error) {
+	response := &ListInboundsResponse{}
+	for _, handler := range s.ihm.ListHandlers(s.ctx) {
+		response.Tag = append(response.Tag, handler.Tag())
+	}
+	return response, nil
+}
+
+func (s *handlerServer) AddOutbound(ctx context.Context, request *AddOutboundRequest) (*AddOutboundResponse, error) {
+	if request.Outbound == nil {
+		return nil, newError("empty outbound config")
+	}
+	if err := s.ohm.AddHandler(s.ctx, request.Outbound); err != nil {
+		return nil, err
+	}
+	return &AddOutboundResponse{}, nil
+}
+
+func (s *handlerServer) RemoveOutbound(ctx context.Context, request *RemoveOutboundRequest) (*RemoveOutboundResponse, error) {
+	if err := s.ohm.RemoveHandler(s.ctx, request.Tag); err != nil {
+		return nil, err
+	}
+	return &RemoveOutboundResponse{}, nil
+}
+
+func (s *handlerServer) ListOutbounds(ctx context.Context, request *ListOutboundsRequest) (*ListOutboundsResponse, error) {
+	response := &ListOutboundsResponse{}
+	for _, handler := range s.ohm.ListHandlers() {
+		response.Tag = append(response.Tag, handler.Tag())
+	}
+	return response, nil
+}
+
+// Service exposes HandlerService through the API server.
+type Service struct {
+	server *handlerServer
+}
+
+// New creates a new Service. Handlers added through the service are created
+// within the same space as the given context.
+func New(ctx context.Context, config *Config) (*Service, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, newError("no space in context")
+	}
+
+	s := &Service{
+		server: &handlerServer{
+			ctx: ctx,
+		},
+	}
+
+	space.OnInitialize(func() error {
+		s.server.ihm = proxyman.InboundHandlerManagerFromSpace(space)
+		if s.server.ihm == nil {
+			return newError("InboundHandlerManager is not found in the space")
+		}
+		s.server.ohm = proxyman.OutboundHandlerManagerFromSpace(space)
+		if s.server.ohm == nil {
+			return newError("OutboundHandlerManager is not found in the space")
+		}
+		return nil
+	})
+
+	return s, nil
+}
+
+// Register implements api.Service.
+func (s *Service) Register(server *grpc.Server) {
+	RegisterHandlerServiceServer(server, s.server)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
@@ -0,0 +1,461 @@
+package command
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v2ray_core_app_proxyman "v2ray.com/core/app/proxyman"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type AddInboundRequest struct {
+	Inbound *v2ray_core_app_proxyman.InboundHandlerConfig `protobuf:"bytes,1,opt,name=inbound" json:"inbound,omitempty"`
+}
+
+func (m *AddInboundRequest) Reset()                    { *m = AddInboundRequest{} }
+func (m *AddInboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddInboundRequest) ProtoMessage()               {}
+func (*AddInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *AddInboundRequest) GetInbound() *v2ray_core_app_proxyman.InboundHandlerConfig {
+	if m != nil {
+		return m.Inbound
+	}
+	return nil
+}
+
+type AddInboundResponse struct {
+}
+
+func (m *AddInboundResponse) Reset()                    { *m = AddInboundResponse{} }
+func (m *AddInboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddInboundResponse) ProtoMessage()               {}
+func (*AddInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+type RemoveInboundRequest struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+}
+
+func (m *RemoveInboundRequest) Reset()                    { *m = RemoveInboundRequest{} }
+func (m *RemoveInboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveInboundRequest) ProtoMessage()               {}
+func (*RemoveInboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *RemoveInboundRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+type RemoveInboundResponse struct {
+}
+
+func (m *RemoveInboundResponse) Reset()                    { *m = RemoveInboundResponse{} }
+func (m *RemoveInboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*RemoveInboundResponse) ProtoMessage()               {}
+func (*RemoveInboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+type ListInboundsRequest struct {
+}
+
+func (m *ListInboundsRequest) Reset()                    { *m = ListInboundsRequest{} }
+func (m *ListInboundsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListInboundsRequest) ProtoMessage()               {}
+func (*ListInboundsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type ListInboundsResponse struct {
+	Tag []string `protobuf:"bytes,1,rep,name=tag" json:"tag,omitempty"`
+}
+
+func (m *ListInboundsResponse) Reset()                    { *m = ListInboundsResponse{} }
+func (m *ListInboundsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListInboundsResponse) ProtoMessage()               {}
+func (*ListInboundsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *ListInboundsResponse) GetTag() []string {
+	if m != nil {
+		return m.Tag
+	}
+	return nil
+}
+
+type AddOutboundRequest struct {
+	Outbound *v2ray_core_app_proxyman.OutboundHandlerConfig `protobuf:"bytes,1,opt,name=outbound" json:"outbound,omitempty"`
+}
+
+func (m *AddOutboundRequest) Reset()                    { *m = AddOutboundRequest{} }
+func (m *AddOutboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddOutboundRequest) ProtoMessage()               {}
+func (*AddOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *AddOutboundRequest) GetOutbound() *v2ray_core_app_proxyman.OutboundHandlerConfig {
+	if m != nil {
+		return m.Outbound
+	}
+	return nil
+}
+
+type AddOutboundResponse struct {
+}
+
+func (m *AddOutboundResponse) Reset()                    { *m = AddOutboundResponse{} }
+func (m *AddOutboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddOutboundResponse) ProtoMessage()               {}
+func (*AddOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+type RemoveOutboundRequest struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+}
+
+func (m *RemoveOutboundRequest) Reset()                    { *m = RemoveOutboundRequest{} }
+func (m *RemoveOutboundRequest) String() string            { return proto.CompactTextString(m) }
+func (*RemoveOutboundRequest) ProtoMessage()               {}
+func (*RemoveOutboundRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *RemoveOutboundRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+type RemoveOutboundResponse struct {
+}
+
+func (m *RemoveOutboundResponse) Reset()                    { *m = RemoveOutboundResponse{} }
+func (m *RemoveOutboundResponse) String() string            { return proto.CompactTextString(m) }
+func (*RemoveOutboundResponse) ProtoMessage()               {}
+func (*RemoveOutboundResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+type ListOutboundsRequest struct {
+}
+
+func (m *ListOutboundsRequest) Reset()                    { *m = ListOutboundsRequest{} }
+func (m *ListOutboundsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListOutboundsRequest) ProtoMessage()               {}
+func (*ListOutboundsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+type ListOutboundsResponse struct {
+	Tag []string `protobuf:"bytes,1,rep,name=tag" json:"tag,omitempty"`
+}
+
+func (m *ListOutboundsResponse) Reset()                    { *m = ListOutboundsResponse{} }
+func (m *ListOutboundsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListOutboundsResponse) ProtoMessage()               {}
+func (*ListOutboundsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *ListOutboundsResponse) GetTag() []string {
+	if m != nil {
+		return m.Tag
+	}
+	return nil
+}
+
+type Config struct {
+}
+
+func (m *Config) Reset()                    { *m = Config{} }
+func (m *Config) String() string            { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()               {}
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func init() {
+	proto.RegisterType((*AddInboundRequest)(nil), "v2ray.core.app.proxyman.command.AddInboundRequest")
+	proto.RegisterType((*AddInboundResponse)(nil), "v2ray.core.app.proxyman.command.AddInboundResponse")
+	proto.RegisterType((*RemoveInboundRequest)(nil), "v2ray.core.app.proxyman.command.RemoveInboundRequest")
+	proto.RegisterType((*RemoveInboundResponse)(nil), "v2ray.core.app.proxyman.command.RemoveInboundResponse")
+	proto.RegisterType((*ListInboundsRequest)(nil), "v2ray.core.app.proxyman.command.ListInboundsRequest")
+	proto.RegisterType((*ListInboundsResponse)(nil), "v2ray.core.app.proxyman.command.ListInboundsResponse")
+	proto.RegisterType((*AddOutboundRequest)(nil), "v2ray.core.app.proxyman.command.AddOutboundRequest")
+	proto.RegisterType((*AddOutboundResponse)(nil), "v2ray.core.app.proxyman.command.AddOutboundResponse")
+	proto.RegisterType((*RemoveOutboundRequest)(nil), "v2ray.core.app.proxyman.command.RemoveOutboundRequest")
+	proto.RegisterType((*RemoveOutboundResponse)(nil), "v2ray.core.app.proxyman.command.RemoveOutboundResponse")
+	proto.RegisterType((*ListOutboundsRequest)(nil), "v2ray.core.app.proxyman.command.ListOutboundsRequest")
+	proto.RegisterType((*ListOutboundsResponse)(nil), "v2ray.core.app.proxyman.command.ListOutboundsResponse")
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.proxyman.command.Config")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for HandlerService service
+
+type HandlerServiceClient interface {
+	AddInbound(ctx context.Context, in *AddInboundRequest, opts ...grpc.CallOption) (*AddInboundResponse, error)
+	RemoveInbound(ctx context.Context, in *RemoveInboundRequest, opts ...grpc.CallOption) (*RemoveInboundResponse, error)
+	ListInbounds(ctx context.Context, in *ListInboundsRequest, opts ...grpc.CallOption) (*ListInboundsResponse, error)
+	AddOutbound(ctx context.Context, in *AddOutboundRequest, opts ...grpc.CallOption) (*AddOutboundResponse, error)
+	RemoveOutbound(ctx context.Context, in *RemoveOutboundRequest, opts ...grpc.CallOption) (*RemoveOutboundResponse, error)
+	ListOutbounds(ctx context.Context, in *ListOutboundsRequest, opts ...grpc.CallOption) (*ListOutboundsResponse, error)
+}
+
+type handlerServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewHandlerServiceClient(cc *grpc.ClientConn) HandlerServiceClient {
+	return &handlerServiceClient{cc}
+}
+
+func (c *handlerServiceClient) AddInbound(ctx context.Context, in *AddInboundRequest, opts ...grpc.CallOption) (*AddInboundResponse, error) {
+	out := new(AddInboundResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/AddInbound", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) RemoveInbound(ctx context.Context, in *RemoveInboundRequest, opts ...grpc.CallOption) (*RemoveInboundResponse, error) {
+	out := new(RemoveInboundResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/RemoveInbound", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) ListInbounds(ctx context.Context, in *ListInboundsRequest, opts ...grpc.CallOption) (*ListInboundsResponse, error) {
+	out := new(ListInboundsResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/ListInbounds", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) AddOutbound(ctx context.Context, in *AddOutboundRequest, opts ...grpc.CallOption) (*AddOutboundResponse, error) {
+	out := new(AddOutboundResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/AddOutbound", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) RemoveOutbound(ctx context.Context, in *RemoveOutboundRequest, opts ...grpc.CallOption) (*RemoveOutboundResponse, error) {
+	out := new(RemoveOutboundResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/RemoveOutbound", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) ListOutbounds(ctx context.Context, in *ListOutboundsRequest, opts ...grpc.CallOption) (*ListOutboundsResponse, error) {
+	out := new(ListOutboundsResponse)
+	err := grpc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/ListOutbounds", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for HandlerService service
+
+type HandlerServiceServer interface {
+	AddInbound(context.Context, *AddInboundRequest) (*AddInboundResponse, error)
+	RemoveInbound(context.Context, *RemoveInboundRequest) (*RemoveInboundResponse, error)
+	ListInbounds(context.Context, *ListInboundsRequest) (*ListInboundsResponse, error)
+	AddOutbound(context.Context, *AddOutboundRequest) (*AddOutboundResponse, error)
+	RemoveOutbound(context.Context, *RemoveOutboundRequest) (*RemoveOutboundResponse, error)
+	ListOutbounds(context.Context, *ListOutboundsRequest) (*ListOutboundsResponse, error)
+}
+
+func RegisterHandlerServiceServer(s *grpc.Server, srv HandlerServiceServer) {
+	s.RegisterService(&_HandlerService_serviceDesc, srv)
+}
+
+func _HandlerService_AddInbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddInboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).AddInbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/AddInbound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).AddInbound(ctx, req.(*AddInboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_RemoveInbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveInboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).RemoveInbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/RemoveInbound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).RemoveInbound(ctx, req.(*RemoveInboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_ListInbounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInboundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).ListInbounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/ListInbounds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).ListInbounds(ctx, req.(*ListInboundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_AddOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOutboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).AddOutbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/AddOutbound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).AddOutbound(ctx, req.(*AddOutboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_RemoveOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOutboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).RemoveOutbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/RemoveOutbound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).RemoveOutbound(ctx, req.(*RemoveOutboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_ListOutbounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOutboundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).ListOutbounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/ListOutbounds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).ListOutbounds(ctx, req.(*ListOutboundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HandlerService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v2ray.core.app.proxyman.command.HandlerService",
+	HandlerType: (*HandlerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddInbound",
+			Handler:    _HandlerService_AddInbound_Handler,
+		},
+		{
+			MethodName: "RemoveInbound",
+			Handler:    _HandlerService_RemoveInbound_Handler,
+		},
+		{
+			MethodName: "ListInbounds",
+			Handler:    _HandlerService_ListInbounds_Handler,
+		},
+		{
+			MethodName: "AddOutbound",
+			Handler:    _HandlerService_AddOutbound_Handler,
+		},
+		{
+			MethodName: "RemoveOutbound",
+			Handler:    _HandlerService_RemoveOutbound_Handler,
+		},
+		{
+			MethodName: "ListOutbounds",
+			Handler:    _HandlerService_ListOutbounds_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v2ray.com/core/app/proxyman/command/command.proto",
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/app/proxyman/command/command.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 443 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0xce, 0xd2, 0x40,
+	0x14, 0xb5, 0x7e, 0x09, 0x1f, 0x5e, 0x94, 0xe8, 0x08, 0x48, 0xba, 0xc1, 0xd4, 0x0d, 0x2c, 0x9c,
+	0xc6, 0xf2, 0xe3, 0x1a, 0x59, 0xf8, 0x13, 0x13, 0x49, 0x4d, 0x5c, 0x18, 0x17, 0x0e, 0xed, 0x48,
+	0x9a, 0xd8, 0xce, 0xd8, 0x16, 0x14, 0x13, 0x13, 0x13, 0x5f, 0xc0, 0xe7, 0xf0, 0x29, 0x4d, 0xdb,
+	0x99, 0xc2, 0x0c, 0xf0, 0x95, 0xae, 0x68, 0x6e, 0xce, 0x39, 0xf7, 0x9c, 0xdb, 0x43, 0xe1, 0xd9,
+	0xd6, 0x89, 0xc9, 0x0e, 0x7b, 0x2c, 0xb4, 0x3d, 0x16, 0x53, 0x9b, 0x70, 0x6e, 0xf3, 0x98, 0xfd,
+	0xd8, 0x85, 0x24, 0xb2, 0x3d, 0x16, 0x86, 0x24, 0xf2, 0xe5, 0x2f, 0xe6, 0x31, 0x4b, 0x19, 0x1a,
+	0x48, 0x4a, 0x4c, 0x31, 0xe1, 0x1c, 0x4b, 0x38, 0x16, 0x30, 0x73, 0x78, 0xb3, 0x66, 0xf4, 0x25,
+	0x58, 0x17, 0x52, 0xd6, 0x27, 0x78, 0x30, 0xf7, 0xfd, 0xd7, 0xd1, 0x8a, 0x6d, 0x22, 0xdf, 0xa5,
+	0xdf, 0x36, 0x34, 0x49, 0xd1, 0x4b, 0xb8, 0x0e, 0x8a, 0x49, 0xdf, 0x78, 0x6c, 0x0c, 0x5b, 0xce,
+	0x53, 0x7c, 0x6e, 0xa3, 0x60, 0xbe, 0x22, 0x91, 0xff, 0x95, 0xc6, 0x8b, 0x5c, 0xda, 0x95, 0x6c,
+	0xab, 0x03, 0xe8, 0x50, 0x3d, 0xe1, 0x2c, 0x4a, 0xa8, 0x35, 0x84, 0x8e, 0x4b, 0x43, 0xb6, 0xa5,
+	0xda, 0xda, 0xfb, 0x70, 0x95, 0x92, 0x75, 0xbe, 0xf2, 0x8e, 0x9b, 0x3d, 0x5a, 0x8f, 0xa0, 0xab,
+	0x21, 0x85, 0x44, 0x17, 0x1e, 0xbe, 0x0d, 0x92, 0x54, 0x8c, 0x13, 0xa1, 0x90, 0x29, 0xab, 0xe3,
+	0x02, 0xbe, 0x57, 0xbe, 0x92, 0xca, 0x9f, 0x73, 0x67, 0xef, 0x36, 0xa9, 0xe2, 0xe0, 0x0d, 0x34,
+	0x99, 0x18, 0x89, 0xe4, 0xf8, 0x6c, 0x72, 0xc9, 0x55, 0xa3, 0x97, 0xfc, 0xcc, 0xa2, 0xb2, 0x41,
+	0x38, 0x1f, 0xc9, 0x48, 0xfa, 0xee, 0xe3, 0xf4, 0x7d, 0xe8, 0xe9, 0x50, 0x21, 0xd2, 0x2b, 0x72,
+	0xca, 0x79, 0x99, 0x7f, 0x04, 0x5d, 0x6d, 0x7e, 0xf6, 0x00, 0x4d, 0x68, 0x14, 0x96, 0x9d, 0xbf,
+	0x0d, 0x68, 0x8b, 0x10, 0xef, 0x69, 0xbc, 0x0d, 0x3c, 0x8a, 0xbe, 0x03, 0xec, 0xdf, 0x1b, 0x72,
+	0x70, 0x45, 0xdf, 0xf0, 0x51, 0x85, 0xcc, 0x71, 0x2d, 0x8e, 0x88, 0x75, 0x0b, 0xfd, 0x36, 0xe0,
+	0x9e, 0xf2, 0xc6, 0xd1, 0xb4, 0x52, 0xe8, 0x54, 0x97, 0xcc, 0x59, 0x5d, 0x5a, 0x69, 0xe1, 0x17,
+	0xdc, 0x3d, 0xec, 0x10, 0x9a, 0x54, 0x2a, 0x9d, 0x68, 0xa2, 0x39, 0xad, 0xc9, 0x2a, 0xd7, 0xff,
+	0x84, 0xd6, 0x41, 0x6d, 0xd0, 0x45, 0x77, 0xd4, 0xaa, 0x64, 0x4e, 0xea, 0x91, 0xca, 0xdd, 0x7f,
+	0x0c, 0x68, 0xab, 0x8d, 0x43, 0x97, 0xde, 0x51, 0xb7, 0xf0, 0xbc, 0x36, 0x4f, 0xe9, 0x80, 0xd2,
+	0x62, 0x74, 0xd9, 0x31, 0xf5, 0x7f, 0x83, 0x39, 0xab, 0x4b, 0x93, 0x16, 0x5e, 0xb8, 0xf0, 0xc4,
+	0x63, 0x61, 0x15, 0x7d, 0x69, 0x7c, 0xbc, 0x16, 0x8f, 0xff, 0x6e, 0x0f, 0x3e, 0x38, 0x2e, 0xd9,
+	0xe1, 0x45, 0x06, 0x9e, 0x73, 0x8e, 0x97, 0x12, 0xbc, 0x28, 0x10, 0xab, 0x46, 0xfe, 0xc1, 0x1d,
+	0xff, 0x1f, 0x00, 0xcc, 0x2b, 0x61, 0xfa, 0xf0, 0x05, 0x00, 0x00,
+}
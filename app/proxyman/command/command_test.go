@@ -0,0 +1,135 @@
+package command_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	_ "v2ray.com/core/app/dispatcher/impl"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/app/proxyman"
+	. "v2ray.com/core/app/proxyman/command"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/dice"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/dokodemo"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+)
+
+func newHandlerServiceClient(assert *assert.Assert) (HandlerServiceClient, func()) {
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(policy.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.InboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	service, err := New(ctx, &Config{})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+	assert.Error(space.Start()).IsNil()
+
+	server := grpc.NewServer()
+	service.Register(server)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Error(err).IsNil()
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	assert.Error(err).IsNil()
+	return NewHandlerServiceClient(conn), func() {
+		conn.Close()
+		server.Stop()
+		space.Close()
+	}
+}
+
+func isListening(port v2net.Port) bool {
+	conn, err := net.Dial("tcp", v2net.TCPDestination(v2net.LocalHostIP, port).NetAddr())
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func TestHandlerServiceOutbound(t *testing.T) {
+	assert := assert.On(t)
+
+	client, closeAll := newHandlerServiceClient(assert)
+	defer closeAll()
+
+	ctx := context.Background()
+	direct := &proxyman.OutboundHandlerConfig{
+		Tag:           "direct",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	}
+	_, err := client.AddOutbound(ctx, &AddOutboundRequest{Outbound: direct})
+	assert.Error(err).IsNil()
+	_, err = client.AddOutbound(ctx, &AddOutboundRequest{Outbound: direct})
+	assert.Error(err).IsNotNil()
+	_, err = client.AddOutbound(ctx, &AddOutboundRequest{})
+	assert.Error(err).IsNotNil()
+
+	list, err := client.ListOutbounds(ctx, &ListOutboundsRequest{})
+	assert.Error(err).IsNil()
+	assert.Int(len(list.Tag)).Equals(1)
+	assert.String(list.Tag[0]).Equals("direct")
+
+	_, err = client.RemoveOutbound(ctx, &RemoveOutboundRequest{Tag: "direct"})
+	assert.Error(err).IsNil()
+	_, err = client.RemoveOutbound(ctx, &RemoveOutboundRequest{Tag: "direct"})
+	assert.Error(err).IsNotNil()
+
+	list, err = client.ListOutbounds(ctx, &ListOutboundsRequest{})
+	assert.Error(err).IsNil()
+	assert.Int(len(list.Tag)).Equals(0)
+}
+
+func TestHandlerServiceInbound(t *testing.T) {
+	assert := assert.On(t)
+
+	client, closeAll := newHandlerServiceClient(assert)
+	defer closeAll()
+
+	ctx := context.Background()
+	port := v2net.Port(dice.Roll(20000) + 10000)
+	inbound := &proxyman.InboundHandlerConfig{
+		Tag: "in",
+		ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+			PortRange: v2net.SinglePortRange(port),
+			Listen:    v2net.NewIPOrDomain(v2net.LocalHostIP),
+		}),
+		ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+			Address: v2net.NewIPOrDomain(v2net.LocalHostIP),
+			Port:    80,
+			NetworkList: &v2net.NetworkList{
+				Network: []v2net.Network{v2net.Network_TCP},
+			},
+		}),
+	}
+	_, err := client.AddInbound(ctx, &AddInboundRequest{Inbound: inbound})
+	assert.Error(err).IsNil()
+	_, err = client.AddInbound(ctx, &AddInboundRequest{Inbound: inbound})
+	assert.Error(err).IsNotNil()
+
+	assert.Bool(isListening(port)).IsTrue()
+
+	list, err := client.ListInbounds(ctx, &ListInboundsRequest{})
+	assert.Error(err).IsNil()
+	assert.Int(len(list.Tag)).Equals(1)
+	assert.String(list.Tag[0]).Equals("in")
+
+	_, err = client.RemoveInbound(ctx, &RemoveInboundRequest{Tag: "in"})
+	assert.Error(err).IsNil()
+	_, err = client.RemoveInbound(ctx, &RemoveInboundRequest{Tag: "in"})
+	assert.Error(err).IsNotNil()
+
+	assert.Bool(isListening(port)).IsFalse()
+}
@@ -15,6 +15,7 @@ type AlwaysOnInboundHandler struct {
 	proxy   proxy.Inbound
 	workers []worker
 	mux     *mux.Server
+	tag     string
 }
 
 func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*AlwaysOnInboundHandler, error) {
@@ -26,6 +27,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 	h := &AlwaysOnInboundHandler{
 		proxy: p,
 		mux:   mux.NewServer(ctx),
+		tag:   tag,
 	}
 
 	nl := p.Network()
@@ -81,6 +83,24 @@ func (h *AlwaysOnInboundHandler) Close() {
 	}
 }
 
+func (h *AlwaysOnInboundHandler) StopAccepting() {
+	for _, worker := range h.workers {
+		worker.StopAccepting()
+	}
+}
+
+func (h *AlwaysOnInboundHandler) ActiveConnections() int {
+	total := 0
+	for _, worker := range h.workers {
+		total += worker.ActiveConnections()
+	}
+	return total
+}
+
+func (h *AlwaysOnInboundHandler) Tag() string {
+	return h.tag
+}
+
 func (h *AlwaysOnInboundHandler) GetRandomInboundProxy() (proxy.Inbound, net.Port, int) {
 	if len(h.workers) == 0 {
 		return nil, 0, 0
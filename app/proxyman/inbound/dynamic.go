@@ -23,6 +23,8 @@ type DynamicInboundHandler struct {
 	portsInUse     map[v2net.Port]bool
 	workerMutex    sync.RWMutex
 	worker         []worker
+	liveWorkers    map[worker]bool
+	stopped        bool
 	lastRefresh    time.Time
 	mux            *mux.Server
 }
@@ -36,6 +38,7 @@ func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *p
 		proxyConfig:    proxyConfig,
 		receiverConfig: receiverConfig,
 		portsInUse:     make(map[v2net.Port]bool),
+		liveWorkers:    make(map[worker]bool),
 		mux:            mux.NewServer(ctx),
 	}
 
@@ -69,6 +72,12 @@ func (h *DynamicInboundHandler) waitAnyCloseWorkers(ctx context.Context, cancel
 		worker.Close()
 	}
 
+	h.workerMutex.Lock()
+	for _, worker := range workers {
+		delete(h.liveWorkers, worker)
+	}
+	h.workerMutex.Unlock()
+
 	h.portMutex.Lock()
 	for _, port := range ports2Del {
 		delete(h.portsInUse, port)
@@ -77,6 +86,13 @@ func (h *DynamicInboundHandler) waitAnyCloseWorkers(ctx context.Context, cancel
 }
 
 func (h *DynamicInboundHandler) refresh() error {
+	h.workerMutex.RLock()
+	stopped := h.stopped
+	h.workerMutex.RUnlock()
+	if stopped {
+		return nil
+	}
+
 	h.lastRefresh = time.Now()
 
 	timeout := time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue()) * 2
@@ -133,6 +149,12 @@ func (h *DynamicInboundHandler) refresh() error {
 
 	h.workerMutex.Lock()
 	h.worker = workers
+	for _, worker := range workers {
+		h.liveWorkers[worker] = true
+		if h.stopped {
+			worker.StopAccepting()
+		}
+	}
 	h.workerMutex.Unlock()
 
 	go h.waitAnyCloseWorkers(ctx, cancel, workers, timeout)
@@ -164,6 +186,31 @@ func (h *DynamicInboundHandler) Close() {
 	h.cancel()
 }
 
+func (h *DynamicInboundHandler) StopAccepting() {
+	h.workerMutex.Lock()
+	defer h.workerMutex.Unlock()
+
+	h.stopped = true
+	for worker := range h.liveWorkers {
+		worker.StopAccepting()
+	}
+}
+
+func (h *DynamicInboundHandler) ActiveConnections() int {
+	h.workerMutex.RLock()
+	defer h.workerMutex.RUnlock()
+
+	total := 0
+	for worker := range h.liveWorkers {
+		total += worker.ActiveConnections()
+	}
+	return total
+}
+
+func (h *DynamicInboundHandler) Tag() string {
+	return h.tag
+}
+
 func (h *DynamicInboundHandler) GetRandomInboundProxy() (proxy.Inbound, v2net.Port, int) {
 	h.workerMutex.RLock()
 	defer h.workerMutex.RUnlock()
@@ -4,15 +4,21 @@ package inbound
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
 	"v2ray.com/core/common"
 )
 
 // Manager is to manage all inbound handlers.
 type Manager struct {
+	sync.RWMutex
 	handlers       []proxyman.InboundHandler
 	taggedHandlers map[string]proxyman.InboundHandler
+	running        bool
 }
 
 func New(ctx context.Context, config *proxyman.InboundConfig) (*Manager, error) {
@@ -34,8 +40,18 @@ func (m *Manager) AddHandler(ctx context.Context, config *proxyman.InboundHandle
 	if err != nil {
 		return err
 	}
-	var handler proxyman.InboundHandler
 	tag := config.Tag
+
+	m.Lock()
+	defer m.Unlock()
+
+	if len(tag) > 0 {
+		if _, found := m.taggedHandlers[tag]; found {
+			return newError("existing tag found: ", tag)
+		}
+	}
+
+	var handler proxyman.InboundHandler
 	allocStrategy := receiverSettings.AllocationStrategy
 	if allocStrategy == nil || allocStrategy.Type == proxyman.AllocationStrategy_Always {
 		h, err := NewAlwaysOnInboundHandler(ctx, tag, receiverSettings, proxySettings)
@@ -55,6 +71,13 @@ func (m *Manager) AddHandler(ctx context.Context, config *proxyman.InboundHandle
 		return newError("unknown allocation strategy: ", receiverSettings.AllocationStrategy.Type)
 	}
 
+	if m.running {
+		if err := handler.Start(); err != nil {
+			handler.Close()
+			return newError("failed to start handler: ", tag).Base(err)
+		}
+	}
+
 	m.handlers = append(m.handlers, handler)
 	if len(tag) > 0 {
 		m.taggedHandlers[tag] = handler
@@ -63,6 +86,9 @@ func (m *Manager) AddHandler(ctx context.Context, config *proxyman.InboundHandle
 }
 
 func (m *Manager) GetHandler(ctx context.Context, tag string) (proxyman.InboundHandler, error) {
+	m.RLock()
+	defer m.RUnlock()
+
 	handler, found := m.taggedHandlers[tag]
 	if !found {
 		return nil, newError("handler not found: ", tag)
@@ -70,7 +96,73 @@ func (m *Manager) GetHandler(ctx context.Context, tag string) (proxyman.InboundH
 	return handler, nil
 }
 
+// RemoveHandler implements proxyman.InboundHandlerManager.
+func (m *Manager) RemoveHandler(ctx context.Context, tag string) error {
+	if len(tag) == 0 {
+		return newError("empty tag")
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	handler, found := m.taggedHandlers[tag]
+	if !found {
+		return newError("handler not found: ", tag)
+	}
+	delete(m.taggedHandlers, tag)
+	for idx, h := range m.handlers {
+		if h == handler {
+			m.handlers = append(m.handlers[:idx], m.handlers[idx+1:]...)
+			break
+		}
+	}
+
+	handler.Close()
+	return nil
+}
+
+// ListHandlers implements proxyman.InboundHandlerManager.
+func (m *Manager) ListHandlers(ctx context.Context) []proxyman.InboundHandler {
+	m.RLock()
+	defer m.RUnlock()
+
+	handlers := make([]proxyman.InboundHandler, len(m.handlers))
+	copy(handlers, m.handlers)
+	return handlers
+}
+
+// Drain implements proxyman.InboundHandlerManager.
+func (m *Manager) Drain(ctx context.Context) error {
+	handlers := m.ListHandlers(ctx)
+	for _, handler := range handlers {
+		handler.StopAccepting()
+	}
+
+	ticker := time.NewTicker(time.Millisecond * 200)
+	defer ticker.Stop()
+
+	for {
+		active := 0
+		for _, handler := range handlers {
+			active += handler.ActiveConnections()
+		}
+		if active == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return newError(active, " connection(s) still active").Base(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
 func (m *Manager) Start() error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.running = true
 	for _, handler := range m.handlers {
 		if err := handler.Start(); err != nil {
 			return err
@@ -80,6 +172,10 @@ func (m *Manager) Start() error {
 }
 
 func (m *Manager) Close() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.running = false
 	for _, handler := range m.handlers {
 		handler.Close()
 	}
@@ -89,6 +185,14 @@ func (m *Manager) Interface() interface{} {
 	return (*proxyman.InboundHandlerManager)(nil)
 }
 
+// Dependencies implements app.HasDependencies.
+func (*Manager) Dependencies() []interface{} {
+	return []interface{}{
+		(*dispatcher.Interface)(nil),
+		(*router.Router)(nil),
+	}
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*proxyman.InboundConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return New(ctx, config.(*proxyman.InboundConfig))
@@ -0,0 +1,60 @@
+package inbound_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	_ "v2ray.com/core/app/dispatcher/impl"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/dokodemo"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestInboundManagerConcurrentAdd(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.InboundConfig))).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	ihm := proxyman.InboundHandlerManagerFromSpace(space)
+	config := &proxyman.InboundHandlerConfig{
+		Tag: "in",
+		ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+			PortRange: net.SinglePortRange(net.Port(10053)),
+			Listen:    net.NewIPOrDomain(net.LocalHostIP),
+		}),
+		ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+			Address:     net.NewIPOrDomain(net.LocalHostIP),
+			Port:        53,
+			NetworkList: &net.NetworkList{Network: []net.Network{net.Network_TCP}},
+		}),
+	}
+
+	var added uint32
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ihm.AddHandler(ctx, config) == nil {
+				atomic.AddUint32(&added, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Uint32(added).Equals(1)
+	assert.Int(len(ihm.ListHandlers(ctx))).Equals(1)
+}
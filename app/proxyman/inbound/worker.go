@@ -24,6 +24,10 @@ type worker interface {
 	Close()
 	Port() v2net.Port
 	Proxy() proxy.Inbound
+	// StopAccepting makes the worker reject new connections, while existing ones keep running.
+	StopAccepting()
+	// ActiveConnections returns the number of connections being processed.
+	ActiveConnections() int
 }
 
 type tcpWorker struct {
@@ -36,12 +40,17 @@ type tcpWorker struct {
 	dispatcher   dispatcher.Interface
 	sniffers     []proxyman.KnownProtocols
 
-	ctx    context.Context
-	cancel context.CancelFunc
-	hub    internet.Listener
+	ctx         context.Context
+	cancel      context.CancelFunc
+	hub         internet.Listener
+	activeConns int32
+	stopped     int32
 }
 
 func (w *tcpWorker) callback(conn internet.Connection) {
+	atomic.AddInt32(&w.activeConns, 1)
+	defer atomic.AddInt32(&w.activeConns, -1)
+
 	ctx, cancel := context.WithCancel(w.ctx)
 	if w.recvOrigDest {
 		dest, err := tcp.GetOriginalDestination(conn)
@@ -81,8 +90,8 @@ func (w *tcpWorker) Start() error {
 	if err != nil {
 		return newError("failed to listen TCP on ", w.port).Base(err)
 	}
-	go w.handleConnections(conns)
 	w.hub = hub
+	go w.handleConnections(conns)
 	return nil
 }
 
@@ -90,7 +99,6 @@ func (w *tcpWorker) handleConnections(conns <-chan internet.Connection) {
 	for {
 		select {
 		case <-w.ctx.Done():
-			w.hub.Close()
 		L:
 			for {
 				select {
@@ -102,6 +110,10 @@ func (w *tcpWorker) handleConnections(conns <-chan internet.Connection) {
 			}
 			return
 		case conn := <-conns:
+			if atomic.LoadInt32(&w.stopped) == 1 {
+				conn.Close()
+				continue
+			}
 			go w.callback(conn)
 		}
 	}
@@ -109,10 +121,30 @@ func (w *tcpWorker) handleConnections(conns <-chan internet.Connection) {
 
 func (w *tcpWorker) Close() {
 	if w.hub != nil {
+		w.hub.Close()
 		w.cancel()
 	}
 }
 
+// StopAccepting closes the listener, so that the port is released for another process, while connections already
+// accepted keep running until they finish or the worker is closed. Listeners sharing their socket with connections,
+// e.g., mKCP, are not closed, but stop accepting and release the port after their connections end.
+func (w *tcpWorker) StopAccepting() {
+	atomic.StoreInt32(&w.stopped, 1)
+	if w.hub == nil {
+		return
+	}
+	if stopper, ok := w.hub.(internet.AcceptStopper); ok {
+		stopper.StopAccepting()
+		return
+	}
+	w.hub.Close()
+}
+
+func (w *tcpWorker) ActiveConnections() int {
+	return int(atomic.LoadInt32(&w.activeConns))
+}
+
 func (w *tcpWorker) Port() v2net.Port {
 	return w.port
 }
@@ -187,6 +219,7 @@ type udpWorker struct {
 	ctx        context.Context
 	cancel     context.CancelFunc
 	activeConn map[v2net.Destination]*udpConn
+	stopped    bool
 }
 
 func (w *udpWorker) getConnection(src v2net.Destination) (*udpConn, bool) {
@@ -197,6 +230,10 @@ func (w *udpWorker) getConnection(src v2net.Destination) (*udpConn, bool) {
 		return conn, true
 	}
 
+	if w.stopped {
+		return nil, false
+	}
+
 	conn := &udpConn{
 		input: make(chan *buf.Buffer, 32),
 		output: func(b []byte) (int, error) {
@@ -219,6 +256,10 @@ func (w *udpWorker) getConnection(src v2net.Destination) (*udpConn, bool) {
 
 func (w *udpWorker) callback(b *buf.Buffer, source v2net.Destination, originalDest v2net.Destination) {
 	conn, existing := w.getConnection(source)
+	if conn == nil {
+		b.Release()
+		return
+	}
 	select {
 	case conn.input <- b:
 	default:
@@ -250,9 +291,18 @@ func (w *udpWorker) callback(b *buf.Buffer, source v2net.Destination, originalDe
 func (w *udpWorker) removeConn(src v2net.Destination) {
 	w.Lock()
 	delete(w.activeConn, src)
+	w.closeIfDrained()
 	w.Unlock()
 }
 
+// closeIfDrained releases the port if the worker stopped accepting and all connections ended. It must be called with
+// the lock held.
+func (w *udpWorker) closeIfDrained() {
+	if w.stopped && len(w.activeConn) == 0 && w.hub != nil {
+		w.hub.Close()
+	}
+}
+
 func (w *udpWorker) Start() error {
 	w.activeConn = make(map[v2net.Destination]*udpConn)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -277,6 +327,21 @@ func (w *udpWorker) Close() {
 	}
 }
 
+// StopAccepting makes the worker drop packets from new sources. As existing connections receive packets through the
+// same socket, the port is released after all of them end.
+func (w *udpWorker) StopAccepting() {
+	w.Lock()
+	w.stopped = true
+	w.closeIfDrained()
+	w.Unlock()
+}
+
+func (w *udpWorker) ActiveConnections() int {
+	w.RLock()
+	defer w.RUnlock()
+	return len(w.activeConn)
+}
+
 func (w *udpWorker) monitor() {
 	timer := time.NewTicker(time.Second * 16)
 	defer timer.Stop()
@@ -294,6 +359,7 @@ func (w *udpWorker) monitor() {
 					conn.cancel()
 				}
 			}
+			w.closeIfDrained()
 			w.Unlock()
 		}
 	}
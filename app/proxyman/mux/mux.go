@@ -59,6 +59,21 @@ func (m *ClientManager) Dispatch(ctx context.Context, outboundRay ray.OutboundRa
 	return nil
 }
 
+// Stats returns the number of active clients, and the number of sessions running on them.
+func (m *ClientManager) Stats() (clients int, sessions int) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	for _, client := range m.clients {
+		if client.Closed() {
+			continue
+		}
+		clients++
+		sessions += client.sessionManager.Size()
+	}
+	return
+}
+
 func (m *ClientManager) onClientFinish() {
 	m.access.Lock()
 	defer m.access.Unlock()
@@ -73,6 +73,16 @@ func NewHandler(ctx context.Context, config *proxyman.OutboundHandlerConfig) (*H
 	return h, nil
 }
 
+// MuxClientManager returns the manager of mux clients of this handler, or nil if mux is not enabled.
+func (h *Handler) MuxClientManager() *mux.ClientManager {
+	return h.mux
+}
+
+// Tag implements proxyman.OutboundHandler.
+func (h *Handler) Tag() string {
+	return h.config.Tag
+}
+
 // Dispatch implements proxy.Outbound.Dispatch.
 func (h *Handler) Dispatch(ctx context.Context, outboundRay ray.OutboundRay) {
 	if h.mux != nil {
@@ -128,7 +138,7 @@ var (
 )
 
 type Connection struct {
-	stream     ray.Ray
+	stream     ray.InboundRay
 	closed     bool
 	localAddr  net.Addr
 	remoteAddr net.Addr
@@ -138,7 +148,7 @@ type Connection struct {
 	writer      buf.Writer
 }
 
-func NewConnection(stream ray.Ray) *Connection {
+func NewConnection(stream ray.InboundRay) *Connection {
 	return &Connection{
 		stream: stream,
 		localAddr: &net.TCPAddr{
@@ -6,6 +6,7 @@ import (
 	"context"
 	"sync"
 
+	"v2ray.com/core/app/log"
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common"
 )
@@ -15,6 +16,7 @@ type Manager struct {
 	sync.RWMutex
 	defaultHandler *Handler
 	taggedHandler  map[string]*Handler
+	handlers       []*Handler
 }
 
 // New creates a new Manager.
@@ -57,6 +59,12 @@ func (m *Manager) AddHandler(ctx context.Context, config *proxyman.OutboundHandl
 	m.Lock()
 	defer m.Unlock()
 
+	if len(config.Tag) > 0 {
+		if _, found := m.taggedHandler[config.Tag]; found {
+			return newError("existing tag found: ", config.Tag)
+		}
+	}
+
 	handler, err := NewHandler(ctx, config)
 	if err != nil {
 		return err
@@ -68,10 +76,53 @@ func (m *Manager) AddHandler(ctx context.Context, config *proxyman.OutboundHandl
 	if len(config.Tag) > 0 {
 		m.taggedHandler[config.Tag] = handler
 	}
+	m.handlers = append(m.handlers, handler)
+
+	return nil
+}
+
+// RemoveHandler implements proxyman.OutboundHandlerManager. If the default handler is removed, existing handlers are
+// not made the default one, and connections not routed to any handler are rejected until a new handler is added.
+func (m *Manager) RemoveHandler(ctx context.Context, tag string) error {
+	if len(tag) == 0 {
+		return newError("empty tag")
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	handler, found := m.taggedHandler[tag]
+	if !found {
+		return newError("handler not found: ", tag)
+	}
+	delete(m.taggedHandler, tag)
+	for idx, h := range m.handlers {
+		if h == handler {
+			m.handlers = append(m.handlers[:idx], m.handlers[idx+1:]...)
+			break
+		}
+	}
+
+	if m.defaultHandler == handler {
+		m.defaultHandler = nil
+		log.Trace(newError("default outbound handler ", tag, " is removed. Connections not routed to other handlers are rejected.").AtWarning())
+	}
 
 	return nil
 }
 
+// ListHandlers implements proxyman.OutboundHandlerManager.
+func (m *Manager) ListHandlers() []proxyman.OutboundHandler {
+	m.RLock()
+	defer m.RUnlock()
+
+	handlers := make([]proxyman.OutboundHandler, 0, len(m.handlers))
+	for _, h := range m.handlers {
+		handlers = append(handlers, h)
+	}
+	return handlers
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*proxyman.OutboundConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return New(ctx, config.(*proxyman.OutboundConfig))
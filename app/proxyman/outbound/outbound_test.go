@@ -0,0 +1,53 @@
+package outbound_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestOutboundManagerAddRemove(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	ohm := proxyman.OutboundHandlerManagerFromSpace(space)
+	for _, tag := range []string{"direct", "backup"} {
+		assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+			Tag:           tag,
+			ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+		})).IsNil()
+	}
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "direct",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	})).IsNotNil()
+
+	assert.Int(len(ohm.ListHandlers())).Equals(2)
+	assert.String(ohm.GetDefaultHandler().Tag()).Equals("direct")
+
+	assert.Error(ohm.RemoveHandler(ctx, "direct")).IsNil()
+	assert.Error(ohm.RemoveHandler(ctx, "direct")).IsNotNil()
+	assert.Bool(ohm.GetHandler("direct") == nil).IsTrue()
+	assert.Int(len(ohm.ListHandlers())).Equals(1)
+	// The remaining handler doesn't take over unrouted connections.
+	assert.Bool(ohm.GetDefaultHandler() == nil).IsTrue()
+
+	assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+		Tag:           "direct",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	})).IsNil()
+	assert.String(ohm.GetDefaultHandler().Tag()).Equals("direct")
+
+	assert.Error(ohm.RemoveHandler(ctx, "backup")).IsNil()
+	assert.String(ohm.GetDefaultHandler().Tag()).Equals("direct")
+}
@@ -15,11 +15,23 @@ import (
 type InboundHandlerManager interface {
 	GetHandler(ctx context.Context, tag string) (InboundHandler, error)
 	AddHandler(ctx context.Context, config *InboundHandlerConfig) error
+	// RemoveHandler closes and removes the handler with the given tag.
+	RemoveHandler(ctx context.Context, tag string) error
+	// ListHandlers returns all handlers currently managed.
+	ListHandlers(ctx context.Context) []InboundHandler
+	// Drain makes all handlers stop accepting new connections, and then waits until all existing
+	// connections finish, or the given context is done.
+	Drain(ctx context.Context) error
 }
 
 type InboundHandler interface {
 	Start() error
 	Close()
+	Tag() string
+	// StopAccepting makes the handler reject new connections, while existing ones keep running.
+	StopAccepting()
+	// ActiveConnections returns the number of connections being processed by the handler.
+	ActiveConnections() int
 
 	// For migration
 	GetRandomInboundProxy() (proxy.Inbound, net.Port, int)
@@ -29,9 +41,16 @@ type OutboundHandlerManager interface {
 	GetHandler(tag string) OutboundHandler
 	GetDefaultHandler() OutboundHandler
 	AddHandler(ctx context.Context, config *OutboundHandlerConfig) error
+	// RemoveHandler removes the handler with the given tag. Connections already
+	// dispatched to the handler are not affected. If the default handler is
+	// removed, the next handler added becomes the default one.
+	RemoveHandler(ctx context.Context, tag string) error
+	// ListHandlers returns all handlers currently managed.
+	ListHandlers() []OutboundHandler
 }
 
 type OutboundHandler interface {
+	Tag() string
 	Dispatch(ctx context.Context, outboundRay ray.OutboundRay)
 }
 
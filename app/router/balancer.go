@@ -0,0 +1,256 @@
+package router
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/ray"
+)
+
+// GetIntervalValue returns the interval between two probes of an outbound.
+func (c *HealthCheck) GetIntervalValue() time.Duration {
+	if c == nil || c.Interval == 0 {
+		return time.Minute
+	}
+	return time.Second * time.Duration(c.Interval)
+}
+
+// GetTimeoutValue returns the time to wait for a probe.
+func (c *HealthCheck) GetTimeoutValue() time.Duration {
+	if c == nil || c.Timeout == 0 {
+		return time.Second * 5
+	}
+	return time.Second * time.Duration(c.Timeout)
+}
+
+// GetDestinationValue returns the destination to connect to through outbounds. It must be a plain HTTP server over TCP.
+func (c *HealthCheck) GetDestinationValue() net.Destination {
+	if c == nil || c.Destination == nil {
+		return net.TCPDestination(net.DomainAddress("www.gstatic.com"), 80)
+	}
+	return c.Destination.AsDestination()
+}
+
+// outboundHealth is the result of the last probe of an outbound.
+type outboundHealth struct {
+	alive   bool
+	latency time.Duration
+}
+
+// Balancer picks an outbound among a group of outbounds, by its strategy. If health check is enabled, outbounds are
+// probed periodically, and the ones that failed the last probe are not picked, unless all of them failed.
+type Balancer struct {
+	sync.RWMutex
+	tag       string
+	outbounds []string
+	strategy  BalancingRule_Strategy
+	check     *HealthCheck
+	ohm       proxyman.OutboundHandlerManager
+	health    map[string]outboundHealth
+	next      uint32
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+// NewBalancer creates a Balancer of the given rule. Outbounds are looked up in the given manager when probed.
+func NewBalancer(rule *BalancingRule, ohm proxyman.OutboundHandlerManager) (*Balancer, error) {
+	if len(rule.Tag) == 0 {
+		return nil, newError("balancer has no tag").AtError()
+	}
+	if len(rule.OutboundTag) == 0 {
+		return nil, newError("balancer ", rule.Tag, " has no outbound").AtError()
+	}
+	if rule.HealthCheck != nil {
+		if ohm == nil {
+			return nil, newError("outbound manager is not found for health check of balancer ", rule.Tag).AtError()
+		}
+		if dest := rule.HealthCheck.GetDestinationValue(); dest.Network != net.Network_TCP {
+			return nil, newError("health check destination of balancer ", rule.Tag, " is not a TCP destination: ", dest).AtError()
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Balancer{
+		tag:       rule.Tag,
+		outbounds: rule.OutboundTag,
+		strategy:  rule.Strategy,
+		check:     rule.HealthCheck,
+		ohm:       ohm,
+		health:    make(map[string]outboundHealth),
+		ctx:       ctx,
+		cancel:    cancel,
+	}, nil
+}
+
+// Tag returns the tag of the balancer.
+func (b *Balancer) Tag() string {
+	return b.tag
+}
+
+// candidates returns the outbounds that didn't fail the last probe, or all outbounds if every one failed.
+func (b *Balancer) candidates() []string {
+	b.RLock()
+	defer b.RUnlock()
+
+	alive := make([]string, 0, len(b.outbounds))
+	for _, tag := range b.outbounds {
+		if h, found := b.health[tag]; !found || h.alive {
+			alive = append(alive, tag)
+		}
+	}
+	if len(alive) == 0 {
+		return b.outbounds
+	}
+	return alive
+}
+
+// PickOutbound returns the tag of the outbound for a new connection.
+func (b *Balancer) PickOutbound() string {
+	candidates := b.candidates()
+	switch b.strategy {
+	case BalancingRule_RoundRobin:
+		n := atomic.AddUint32(&b.next, 1)
+		return candidates[int((n-1)%uint32(len(candidates)))]
+	case BalancingRule_LeastLatency:
+		return b.leastLatency(candidates)
+	case BalancingRule_Failover:
+		return candidates[0]
+	default:
+		return candidates[dice.Roll(len(candidates))]
+	}
+}
+
+// leastLatency returns the candidate with the lowest latency. Outbounds not probed yet are picked only if no outbound
+// has a latency.
+func (b *Balancer) leastLatency(candidates []string) string {
+	b.RLock()
+	defer b.RUnlock()
+
+	picked := candidates[0]
+	var latency time.Duration
+	for _, tag := range candidates {
+		h, found := b.health[tag]
+		if !found || !h.alive {
+			continue
+		}
+		if latency == 0 || h.latency < latency {
+			picked = tag
+			latency = h.latency
+		}
+	}
+	return picked
+}
+
+// CheckHealth probes all outbounds once, and updates their health.
+func (b *Balancer) CheckHealth() {
+	var wg sync.WaitGroup
+	for _, tag := range b.outbounds {
+		wg.Add(1)
+		go func(tag string) {
+			defer wg.Done()
+
+			latency, err := b.probe(tag)
+			h := outboundHealth{alive: err == nil, latency: latency}
+			if err != nil {
+				log.Trace(newError("outbound ", tag, " of balancer ", b.tag, " failed health check").Base(err).AtWarning())
+			} else {
+				log.Trace(newError("outbound ", tag, " of balancer ", b.tag, " is alive with latency ", latency).AtDebug())
+			}
+
+			b.Lock()
+			b.health[tag] = h
+			b.Unlock()
+		}(tag)
+	}
+	wg.Wait()
+}
+
+// probe sends a HTTP request to the health check destination through the outbound, in the same way as proxy chaining,
+// and returns the time from dispatching the request until the first byte of response, which includes connecting to the
+// destination through the outbound. The probe fails if the response is not HTTP, so the destination must be a plain
+// HTTP server, e.g., port 80 rather than 443.
+func (b *Balancer) probe(tag string) (time.Duration, error) {
+	handler := b.ohm.GetHandler(tag)
+	if handler == nil {
+		return 0, newError("outbound ", tag, " is not found")
+	}
+
+	timeout := b.check.GetTimeoutValue()
+	dest := b.check.GetDestinationValue()
+	ctx, cancel := context.WithTimeout(b.ctx, timeout)
+	defer cancel()
+	ctx = proxy.ContextWithTarget(ctx, dest)
+
+	stream := ray.NewRay(ctx)
+	defer stream.InboundOutput().CloseError()
+
+	start := time.Now()
+	go handler.Dispatch(ctx, stream)
+
+	request := buf.New()
+	request.AppendBytes([]byte("HEAD / HTTP/1.1\r\nHost: " + dest.Address.String() + "\r\nConnection: close\r\n\r\n")...)
+	if err := stream.InboundInput().Write(buf.NewMultiBufferValue(request)); err != nil {
+		return 0, newError("failed to send probe").Base(err)
+	}
+
+	response, err := stream.InboundOutput().ReadTimeout(timeout)
+	if err != nil {
+		return 0, newError("no response to probe").Base(err)
+	}
+	latency := time.Since(start)
+
+	// The response may come in chunks shorter than the header, so read until the header is complete or the response ends.
+	header := make([]byte, 0, 5)
+	for {
+		n, _ := response.Read(header[len(header):cap(header)])
+		header = header[:len(header)+n]
+		response.Release()
+		if len(header) == cap(header) {
+			break
+		}
+		response, err = stream.InboundOutput().ReadTimeout(timeout)
+		if err != nil {
+			break
+		}
+	}
+	stream.InboundInput().Close()
+
+	if !strings.HasPrefix(string(header), "HTTP/") {
+		return 0, newError("response to probe is not HTTP, the destination must be a plain HTTP server")
+	}
+	return latency, nil
+}
+
+func (b *Balancer) monitor() {
+	ticker := time.NewTicker(b.check.GetIntervalValue())
+	defer ticker.Stop()
+
+	for {
+		b.CheckHealth()
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start starts probing outbounds, if health check is enabled.
+func (b *Balancer) Start() {
+	if b.check != nil {
+		go b.monitor()
+	}
+}
+
+// Close stops probing outbounds.
+func (b *Balancer) Close() {
+	b.cancel()
+}
@@ -0,0 +1,236 @@
+package router_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/proxyman"
+	. "v2ray.com/core/app/router"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/proxy/blackhole"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/ray"
+)
+
+// probeHandler answers the probe after the delay, or fails it if dead. The response is written in the given chunks one
+// by one, or as a single HTTP response if not set.
+type probeHandler struct {
+	tag      string
+	delay    time.Duration
+	dead     bool
+	response []string
+}
+
+func (h *probeHandler) Tag() string {
+	return h.tag
+}
+
+func (h *probeHandler) Dispatch(ctx context.Context, outboundRay ray.OutboundRay) {
+	request, err := outboundRay.OutboundInput().Read()
+	if err != nil {
+		outboundRay.OutboundOutput().CloseError()
+		return
+	}
+	request.Release()
+
+	if h.dead {
+		outboundRay.OutboundOutput().CloseError()
+		return
+	}
+	time.Sleep(h.delay)
+	chunks := h.response
+	if len(chunks) == 0 {
+		chunks = []string{"HTTP/1.1 204 No Content\r\n\r\n"}
+	}
+	for i, chunk := range chunks {
+		if i > 0 {
+			// Let the prober read the previous chunk alone.
+			time.Sleep(time.Millisecond * 50)
+		}
+		response := buf.New()
+		response.AppendBytes([]byte(chunk)...)
+		outboundRay.OutboundOutput().Write(buf.NewMultiBufferValue(response))
+	}
+	outboundRay.OutboundOutput().Close()
+}
+
+type probeHandlerManager struct {
+	proxyman.OutboundHandlerManager
+	handlers map[string]*probeHandler
+}
+
+func (m *probeHandlerManager) GetHandler(tag string) proxyman.OutboundHandler {
+	if h, found := m.handlers[tag]; found {
+		return h
+	}
+	return nil
+}
+
+func newProbeHandlerManager(handlers ...*probeHandler) *probeHandlerManager {
+	m := &probeHandlerManager{
+		handlers: make(map[string]*probeHandler),
+	}
+	for _, h := range handlers {
+		m.handlers[h.tag] = h
+	}
+	return m
+}
+
+func TestBalancerStrategies(t *testing.T) {
+	assert := assert.On(t)
+
+	rule := &BalancingRule{
+		Tag:         "proxy",
+		OutboundTag: []string{"a", "b", "c"},
+		Strategy:    BalancingRule_RoundRobin,
+	}
+	balancer, err := NewBalancer(rule, nil)
+	assert.Error(err).IsNil()
+	for _, tag := range []string{"a", "b", "c", "a"} {
+		assert.String(balancer.PickOutbound()).Equals(tag)
+	}
+
+	rule.Strategy = BalancingRule_Failover
+	balancer, err = NewBalancer(rule, nil)
+	assert.Error(err).IsNil()
+	assert.String(balancer.PickOutbound()).Equals("a")
+
+	rule.Strategy = BalancingRule_Random
+	balancer, err = NewBalancer(rule, nil)
+	assert.Error(err).IsNil()
+	picked := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		picked[balancer.PickOutbound()] = true
+	}
+	assert.Int(len(picked)).Equals(3)
+
+	_, err = NewBalancer(&BalancingRule{Tag: "proxy"}, nil)
+	assert.Error(err).IsNotNil()
+	_, err = NewBalancer(&BalancingRule{Tag: "proxy", OutboundTag: []string{"a"}, HealthCheck: &HealthCheck{}}, nil)
+	assert.Error(err).IsNotNil()
+	_, err = NewBalancer(&BalancingRule{
+		Tag:         "proxy",
+		OutboundTag: []string{"a"},
+		HealthCheck: &HealthCheck{
+			Destination: &net.Endpoint{
+				Network: net.Network_UDP,
+				Address: net.NewIPOrDomain(net.LocalHostIP),
+				Port:    53,
+			},
+		},
+	}, newProbeHandlerManager())
+	assert.Error(err).IsNotNil()
+}
+
+func TestBalancerHealthCheck(t *testing.T) {
+	assert := assert.On(t)
+
+	ohm := newProbeHandlerManager(
+		&probeHandler{tag: "slow", delay: time.Millisecond * 200},
+		&probeHandler{tag: "fast"},
+		&probeHandler{tag: "dead", dead: true},
+		&probeHandler{tag: "tls", response: []string{"\x15\x03\x01\x00\x02"}},
+		&probeHandler{tag: "short", response: []string{"H"}},
+		&probeHandler{tag: "split", delay: time.Millisecond * 100, response: []string{"HT", "TP/1.1 204 No Content\r\n\r\n"}},
+	)
+	newBalancer := func(strategy BalancingRule_Strategy, outbounds ...string) *Balancer {
+		balancer, err := NewBalancer(&BalancingRule{
+			Tag:         "proxy",
+			OutboundTag: outbounds,
+			Strategy:    strategy,
+			HealthCheck: &HealthCheck{Timeout: 1},
+		}, ohm)
+		assert.Error(err).IsNil()
+		balancer.CheckHealth()
+		return balancer
+	}
+
+	balancer := newBalancer(BalancingRule_LeastLatency, "dead", "tls", "slow", "fast")
+	assert.String(balancer.PickOutbound()).Equals("fast")
+
+	balancer = newBalancer(BalancingRule_Failover, "dead", "missing", "tls", "short", "slow", "fast")
+	assert.String(balancer.PickOutbound()).Equals("slow")
+
+	// A response split in the middle of the header is still HTTP.
+	balancer = newBalancer(BalancingRule_Failover, "short", "split", "fast")
+	assert.String(balancer.PickOutbound()).Equals("split")
+
+	balancer = newBalancer(BalancingRule_RoundRobin, "dead", "slow", "fast")
+	for _, tag := range []string{"slow", "fast", "slow"} {
+		assert.String(balancer.PickOutbound()).Equals(tag)
+	}
+
+	// All outbounds are picked if all of them fail.
+	balancer = newBalancer(BalancingRule_Failover, "dead", "missing")
+	assert.String(balancer.PickOutbound()).Equals("dead")
+}
+
+func TestBalancingRule(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &Config{
+		BalancingRule: []*BalancingRule{
+			{
+				Tag:         "exits",
+				OutboundTag: []string{"exit1", "exit2"},
+				Strategy:    BalancingRule_RoundRobin,
+			},
+		},
+		Rule: []*RoutingRule{
+			{
+				Tag: "direct",
+				Domain: []*Domain{
+					{Type: Domain_Domain, Value: "v2ray.com"},
+				},
+			},
+			{
+				BalancingTag: "exits",
+				NetworkList:  &net.NetworkList{Network: []net.Network{net.Network_TCP}},
+			},
+		},
+	}
+	newSpace := func() (context.Context, app.Space) {
+		ctx, space := newRouterSpace(assert, config)
+		ohm := proxyman.OutboundHandlerManagerFromSpace(space)
+		for _, tag := range []string{"exit1", "exit2"} {
+			assert.Error(ohm.AddHandler(ctx, &proxyman.OutboundHandlerConfig{
+				Tag:           tag,
+				ProxySettings: serial.ToTypedMessage(new(blackhole.Config)),
+			})).IsNil()
+		}
+		return ctx, space
+	}
+	ctx, space := newSpace()
+	assert.Error(space.Initialize()).IsNil()
+	r := FromSpace(space)
+	assert.Pointer(r.Balancer("exits")).IsNotNil()
+
+	for _, c := range []struct {
+		domain string
+		tag    string
+	}{
+		{"www.v2ray.com", "direct"},
+		{"www.google.com", "exit1"},
+		{"www.google.com", "exit2"},
+		{"www.google.com", "exit1"},
+	} {
+		tag, err := r.TakeDetour(proxy.ContextWithTarget(ctx, net.TCPDestination(net.DomainAddress(c.domain), 80)))
+		assert.Error(err).IsNil()
+		assert.String(tag).Equals(c.tag)
+	}
+
+	config.Rule[1].BalancingTag = "missing"
+	_, space = newSpace()
+	assert.Error(space.Initialize()).IsNotNil()
+
+	// Outbounds of balancers must exist.
+	config.Rule[1].BalancingTag = "exits"
+	config.BalancingRule[0].OutboundTag = []string{"exit1", "exit3"}
+	_, space = newSpace()
+	assert.Error(space.Initialize()).IsNotNil()
+}
@@ -132,6 +132,23 @@ func (m SubDomainMatcher) Apply(ctx context.Context) bool {
 	return len(domain) == len(pattern) || domain[len(domain)-len(pattern)-1] == '.'
 }
 
+type FullDomainMatcher string
+
+func NewFullDomainMatcher(p string) Condition {
+	return FullDomainMatcher(p)
+}
+
+func (m FullDomainMatcher) Apply(ctx context.Context) bool {
+	dest, ok := proxy.TargetFromContext(ctx)
+	if !ok {
+		return false
+	}
+	if !dest.Address.Family().IsDomain() {
+		return false
+	}
+	return dest.Address.Domain() == string(m)
+}
+
 type CIDRMatcher struct {
 	cidr     *net.IPNet
 	onSource bool
@@ -78,6 +78,10 @@ func TestRoutingRule(t *testing.T) {
 						Value: "^facebook\\.com$",
 						Type:  Domain_Regex,
 					},
+					{
+						Value: "www.v2ray.net",
+						Type:  Domain_Full,
+					},
 				},
 			},
 			test: []ruleTest{
@@ -105,6 +109,14 @@ func TestRoutingRule(t *testing.T) {
 					input:  proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.DomainAddress("www.facebook.com"), 80)),
 					output: false,
 				},
+				ruleTest{
+					input:  proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.DomainAddress("www.v2ray.net"), 80)),
+					output: true,
+				},
+				ruleTest{
+					input:  proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.DomainAddress("v2.www.v2ray.net"), 80)),
+					output: false,
+				},
 				ruleTest{
 					input:  context.Background(),
 					output: false,
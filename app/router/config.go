@@ -2,79 +2,68 @@ package router
 
 import (
 	"context"
-	"net"
-
-	v2net "v2ray.com/core/common/net"
+	"sync/atomic"
 )
 
 type Rule struct {
+	// hits is accessed atomically. It must be the first field, and Rules must be allocated individually, so that it
+	// is 64-bit aligned on 32-bit platforms.
+	hits      uint64
 	Tag       string
+	Balancer  *Balancer
 	Condition Condition
 }
 
-func (r *Rule) Apply(ctx context.Context) bool {
-	return r.Condition.Apply(ctx)
-}
-
-func cidrToCondition(cidr []*CIDR, source bool) (Condition, error) {
-	ipv4Net := v2net.NewIPNet()
-	ipv6Cond := NewAnyCondition()
-	hasIpv6 := false
-
-	for _, ip := range cidr {
-		switch len(ip.Ip) {
-		case net.IPv4len:
-			ipv4Net.AddIP(ip.Ip, byte(ip.Prefix))
-		case net.IPv6len:
-			hasIpv6 = true
-			matcher, err := NewCIDRMatcher(ip.Ip, ip.Prefix, source)
-			if err != nil {
-				return nil, err
-			}
-			ipv6Cond.Add(matcher)
-		default:
-			return nil, newError("invalid IP length").AtError()
-		}
+// GetTag returns the tag of the outbound for the connection matching this rule.
+func (r *Rule) GetTag() string {
+	if r.Balancer != nil {
+		return r.Balancer.PickOutbound()
 	}
+	return r.Tag
+}
 
-	if !ipv4Net.IsEmpty() && hasIpv6 {
-		cond := NewAnyCondition()
-		cond.Add(NewIPv4Matcher(ipv4Net, source))
-		cond.Add(ipv6Cond)
-		return cond, nil
-	} else if !ipv4Net.IsEmpty() {
-		return NewIPv4Matcher(ipv4Net, source), nil
-	} else {
-		return ipv6Cond, nil
+func (r *Rule) Apply(ctx context.Context) bool {
+	if r.Condition.Apply(ctx) {
+		atomic.AddUint64(&r.hits, 1)
+		return true
 	}
+	return false
 }
 
+// BuildCondition builds the condition of the rule. Country codes and domain lists are loaded from the default data files.
 func (rr *RoutingRule) BuildCondition() (Condition, error) {
+	return rr.buildCondition(newGeoLoader(&Config{}))
+}
+
+func (rr *RoutingRule) buildCondition(geo *geoLoader) (Condition, error) {
 	conds := NewConditionChan()
 
-	if len(rr.Domain) > 0 {
-		anyCond := NewAnyCondition()
-		for _, domain := range rr.Domain {
-			switch domain.Type {
-			case Domain_Plain:
-				anyCond.Add(NewPlainDomainMatcher(domain.Value))
-			case Domain_Regex:
-				matcher, err := NewRegexpDomainMatcher(domain.Value)
-				if err != nil {
-					return nil, err
-				}
-				anyCond.Add(matcher)
-			case Domain_Domain:
-				anyCond.Add(NewSubDomainMatcher(domain.Value))
-			default:
-				panic("Unknown domain type.")
+	if len(rr.Domain) > 0 || len(rr.Geosite) > 0 {
+		domains := rr.Domain
+		for _, name := range rr.Geosite {
+			list, err := geo.loadGeoSite(name)
+			if err != nil {
+				return nil, err
 			}
+			domains = append(domains[:len(domains):len(domains)], list...)
 		}
-		conds.Add(anyCond)
+		matcher, err := NewDomainMatcher(domains)
+		if err != nil {
+			return nil, err
+		}
+		conds.Add(matcher)
 	}
 
-	if len(rr.Cidr) > 0 {
-		cond, err := cidrToCondition(rr.Cidr, false)
+	if len(rr.Cidr) > 0 || len(rr.Geoip) > 0 {
+		cidr := rr.Cidr
+		for _, code := range rr.Geoip {
+			ips, err := geo.loadGeoIP(code)
+			if err != nil {
+				return nil, err
+			}
+			cidr = append(cidr[:len(cidr):len(cidr)], ips...)
+		}
+		cond, err := NewIPMatcher(cidr, false)
 		if err != nil {
 			return nil, err
 		}
@@ -90,7 +79,7 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 	}
 
 	if len(rr.SourceCidr) > 0 {
-		cond, err := cidrToCondition(rr.SourceCidr, true)
+		cond, err := NewIPMatcher(rr.SourceCidr, true)
 		if err != nil {
 			return nil, err
 		}
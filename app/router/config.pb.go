@@ -5,6 +5,7 @@ import fmt "fmt"
 import math "math"
 import v2ray_core_common_net "v2ray.com/core/common/net"
 import v2ray_core_common_net1 "v2ray.com/core/common/net"
+import v2ray_core_common_net2 "v2ray.com/core/common/net"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -27,17 +28,21 @@ const (
 	Domain_Regex Domain_Type = 1
 	// The value is a domain.
 	Domain_Domain Domain_Type = 2
+	// The value is a full domain, which must be matched exactly.
+	Domain_Full Domain_Type = 3
 )
 
 var Domain_Type_name = map[int32]string{
 	0: "Plain",
 	1: "Regex",
 	2: "Domain",
+	3: "Full",
 }
 var Domain_Type_value = map[string]int32{
 	"Plain":  0,
 	"Regex":  1,
 	"Domain": 2,
+	"Full":   3,
 }
 
 func (x Domain_Type) String() string {
@@ -45,6 +50,37 @@ func (x Domain_Type) String() string {
 }
 func (Domain_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 0} }
 
+type BalancingRule_Strategy int32
+
+const (
+	// Pick an outbound at random.
+	BalancingRule_Random BalancingRule_Strategy = 0
+	// Pick outbounds in turn.
+	BalancingRule_RoundRobin BalancingRule_Strategy = 1
+	// Pick the outbound with the lowest latency of the last probe.
+	BalancingRule_LeastLatency BalancingRule_Strategy = 2
+	// Pick the first outbound in the list, unless it fails the probe.
+	BalancingRule_Failover BalancingRule_Strategy = 3
+)
+
+var BalancingRule_Strategy_name = map[int32]string{
+	0: "Random",
+	1: "RoundRobin",
+	2: "LeastLatency",
+	3: "Failover",
+}
+var BalancingRule_Strategy_value = map[string]int32{
+	"Random":       0,
+	"RoundRobin":   1,
+	"LeastLatency": 2,
+	"Failover":     3,
+}
+
+func (x BalancingRule_Strategy) String() string {
+	return proto.EnumName(BalancingRule_Strategy_name, int32(x))
+}
+func (BalancingRule_Strategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{8, 0} }
+
 type Config_DomainStrategy int32
 
 const (
@@ -70,7 +106,7 @@ var Config_DomainStrategy_value = map[string]int32{
 func (x Config_DomainStrategy) String() string {
 	return proto.EnumName(Config_DomainStrategy_name, int32(x))
 }
-func (Config_DomainStrategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3, 0} }
+func (Config_DomainStrategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{9, 0} }
 
 // Domain for routing decision.
 type Domain struct {
@@ -78,6 +114,8 @@ type Domain struct {
 	Type Domain_Type `protobuf:"varint,1,opt,name=type,enum=v2ray.core.app.router.Domain_Type" json:"type,omitempty"`
 	// Domain value.
 	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+	// Attributes of the domain in a domain list, such as "ads". They select a subset of the list in routing rules.
+	Attribute []string `protobuf:"bytes,3,rep,name=attribute" json:"attribute,omitempty"`
 }
 
 func (m *Domain) Reset()                    { *m = Domain{} }
@@ -99,6 +137,13 @@ func (m *Domain) GetValue() string {
 	return ""
 }
 
+func (m *Domain) GetAttribute() []string {
+	if m != nil {
+		return m.Attribute
+	}
+	return nil
+}
+
 // IP for routing decision, in CIDR form.
 type CIDR struct {
 	// IP address, should be either 4 or 16 bytes.
@@ -126,6 +171,94 @@ func (m *CIDR) GetPrefix() uint32 {
 	return 0
 }
 
+// IP ranges of a country, or of a named group of IPs, such as "private".
+type CountryIPRange struct {
+	Ips []*CIDR `protobuf:"bytes,1,rep,name=ips" json:"ips,omitempty"`
+}
+
+func (m *CountryIPRange) Reset()                    { *m = CountryIPRange{} }
+func (m *CountryIPRange) String() string            { return proto.CompactTextString(m) }
+func (*CountryIPRange) ProtoMessage()               {}
+func (*CountryIPRange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *CountryIPRange) GetIps() []*CIDR {
+	if m != nil {
+		return m.Ips
+	}
+	return nil
+}
+
+// Content of a GeoIP data file.
+type GeoIPList struct {
+	// Country codes in lower case, such as "cn", to their IP ranges.
+	Country map[string]*CountryIPRange `protobuf:"bytes,1,rep,name=country" json:"country,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *GeoIPList) Reset()                    { *m = GeoIPList{} }
+func (m *GeoIPList) String() string            { return proto.CompactTextString(m) }
+func (*GeoIPList) ProtoMessage()               {}
+func (*GeoIPList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *GeoIPList) GetCountry() map[string]*CountryIPRange {
+	if m != nil {
+		return m.Country
+	}
+	return nil
+}
+
+// A named list of domains.
+type GeoSite struct {
+	// Name of the list in lower case, such as "cn".
+	Name   string    `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Domain []*Domain `protobuf:"bytes,2,rep,name=domain" json:"domain,omitempty"`
+	// Other lists in the same file whose domains are in this list too. Each one is a name optionally followed by
+	// "@attribute", such as "google@ads", to include the domains with the attribute only.
+	Include []string `protobuf:"bytes,3,rep,name=include" json:"include,omitempty"`
+}
+
+func (m *GeoSite) Reset()                    { *m = GeoSite{} }
+func (m *GeoSite) String() string            { return proto.CompactTextString(m) }
+func (*GeoSite) ProtoMessage()               {}
+func (*GeoSite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *GeoSite) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GeoSite) GetDomain() []*Domain {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+func (m *GeoSite) GetInclude() []string {
+	if m != nil {
+		return m.Include
+	}
+	return nil
+}
+
+// Content of a domain list data file.
+type GeoSiteList struct {
+	Entry []*GeoSite `protobuf:"bytes,1,rep,name=entry" json:"entry,omitempty"`
+}
+
+func (m *GeoSiteList) Reset()                    { *m = GeoSiteList{} }
+func (m *GeoSiteList) String() string            { return proto.CompactTextString(m) }
+func (*GeoSiteList) ProtoMessage()               {}
+func (*GeoSiteList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *GeoSiteList) GetEntry() []*GeoSite {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
 type RoutingRule struct {
 	Tag         string                              `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
 	Domain      []*Domain                           `protobuf:"bytes,2,rep,name=domain" json:"domain,omitempty"`
@@ -135,12 +268,21 @@ type RoutingRule struct {
 	SourceCidr  []*CIDR                             `protobuf:"bytes,6,rep,name=source_cidr,json=sourceCidr" json:"source_cidr,omitempty"`
 	UserEmail   []string                            `protobuf:"bytes,7,rep,name=user_email,json=userEmail" json:"user_email,omitempty"`
 	InboundTag  []string                            `protobuf:"bytes,8,rep,name=inbound_tag,json=inboundTag" json:"inbound_tag,omitempty"`
+	// Country codes, such as "cn" or "geoip:private". The target IP matches if it is in the IP ranges of any of the
+	// countries, or in cidr. IP ranges are loaded from the GeoIP data file.
+	Geoip []string `protobuf:"bytes,9,rep,name=geoip" json:"geoip,omitempty"`
+	// Names of domain lists, such as "cn" or "geosite:ads". A name may be followed by one or more "@attribute" to
+	// select domains with all the attributes, such as "geosite:google@ads". The target domain matches if it matches
+	// any domain in the lists, or in domain. Lists are loaded from the domain list data file.
+	Geosite []string `protobuf:"bytes,10,rep,name=geosite" json:"geosite,omitempty"`
+	// Tag of a balancer in Config.balancing_rule. If set, the outbound is picked by the balancer, and tag is ignored.
+	BalancingTag string `protobuf:"bytes,11,opt,name=balancing_tag,json=balancingTag" json:"balancing_tag,omitempty"`
 }
 
 func (m *RoutingRule) Reset()                    { *m = RoutingRule{} }
 func (m *RoutingRule) String() string            { return proto.CompactTextString(m) }
 func (*RoutingRule) ProtoMessage()               {}
-func (*RoutingRule) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*RoutingRule) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *RoutingRule) GetTag() string {
 	if m != nil {
@@ -198,15 +340,120 @@ func (m *RoutingRule) GetInboundTag() []string {
 	return nil
 }
 
+func (m *RoutingRule) GetGeoip() []string {
+	if m != nil {
+		return m.Geoip
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetGeosite() []string {
+	if m != nil {
+		return m.Geosite
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetBalancingTag() string {
+	if m != nil {
+		return m.BalancingTag
+	}
+	return ""
+}
+
+// HealthCheck probes outbounds of a balancer periodically.
+type HealthCheck struct {
+	// Seconds between two probes of an outbound. Default to 60.
+	Interval uint32 `protobuf:"varint,1,opt,name=interval" json:"interval,omitempty"`
+	// Destination to connect to through the outbound. It must be a plain HTTP server over TCP, as a HEAD request is sent
+	// and the response must be HTTP. Default to TCP www.gstatic.com:80.
+	Destination *v2ray_core_common_net2.Endpoint `protobuf:"bytes,2,opt,name=destination" json:"destination,omitempty"`
+	// Seconds to wait for a probe. Default to 5.
+	Timeout uint32 `protobuf:"varint,3,opt,name=timeout" json:"timeout,omitempty"`
+}
+
+func (m *HealthCheck) Reset()                    { *m = HealthCheck{} }
+func (m *HealthCheck) String() string            { return proto.CompactTextString(m) }
+func (*HealthCheck) ProtoMessage()               {}
+func (*HealthCheck) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *HealthCheck) GetInterval() uint32 {
+	if m != nil {
+		return m.Interval
+	}
+	return 0
+}
+
+func (m *HealthCheck) GetDestination() *v2ray_core_common_net2.Endpoint {
+	if m != nil {
+		return m.Destination
+	}
+	return nil
+}
+
+func (m *HealthCheck) GetTimeout() uint32 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+// BalancingRule is a group of outbounds, among which one is picked for each connection.
+type BalancingRule struct {
+	Tag         string                 `protobuf:"bytes,1,opt,name=tag" json:"tag,omitempty"`
+	OutboundTag []string               `protobuf:"bytes,2,rep,name=outbound_tag,json=outboundTag" json:"outbound_tag,omitempty"`
+	Strategy    BalancingRule_Strategy `protobuf:"varint,3,opt,name=strategy,enum=v2ray.core.app.router.BalancingRule_Strategy" json:"strategy,omitempty"`
+	// Outbounds that fail the probe are not picked, unless all outbounds fail. If not set, outbounds are not probed.
+	HealthCheck *HealthCheck `protobuf:"bytes,4,opt,name=health_check,json=healthCheck" json:"health_check,omitempty"`
+}
+
+func (m *BalancingRule) Reset()                    { *m = BalancingRule{} }
+func (m *BalancingRule) String() string            { return proto.CompactTextString(m) }
+func (*BalancingRule) ProtoMessage()               {}
+func (*BalancingRule) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *BalancingRule) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *BalancingRule) GetOutboundTag() []string {
+	if m != nil {
+		return m.OutboundTag
+	}
+	return nil
+}
+
+func (m *BalancingRule) GetStrategy() BalancingRule_Strategy {
+	if m != nil {
+		return m.Strategy
+	}
+	return BalancingRule_Random
+}
+
+func (m *BalancingRule) GetHealthCheck() *HealthCheck {
+	if m != nil {
+		return m.HealthCheck
+	}
+	return nil
+}
+
 type Config struct {
 	DomainStrategy Config_DomainStrategy `protobuf:"varint,1,opt,name=domain_strategy,json=domainStrategy,enum=v2ray.core.app.router.Config_DomainStrategy" json:"domain_strategy,omitempty"`
 	Rule           []*RoutingRule        `protobuf:"bytes,2,rep,name=rule" json:"rule,omitempty"`
+	// Path of the GeoIP data file, which contains a GeoIPList. Default to "geoip.dat" in the asset location.
+	GeoipFile string `protobuf:"bytes,3,opt,name=geoip_file,json=geoipFile" json:"geoip_file,omitempty"`
+	// Path of the domain list data file, which contains a GeoSiteList. Default to "geosite.dat" in the asset location.
+	GeositeFile   string           `protobuf:"bytes,4,opt,name=geosite_file,json=geositeFile" json:"geosite_file,omitempty"`
+	BalancingRule []*BalancingRule `protobuf:"bytes,5,rep,name=balancing_rule,json=balancingRule" json:"balancing_rule,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *Config) GetDomainStrategy() Config_DomainStrategy {
 	if m != nil {
@@ -222,51 +469,106 @@ func (m *Config) GetRule() []*RoutingRule {
 	return nil
 }
 
+func (m *Config) GetGeoipFile() string {
+	if m != nil {
+		return m.GeoipFile
+	}
+	return ""
+}
+
+func (m *Config) GetGeositeFile() string {
+	if m != nil {
+		return m.GeositeFile
+	}
+	return ""
+}
+
+func (m *Config) GetBalancingRule() []*BalancingRule {
+	if m != nil {
+		return m.BalancingRule
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Domain)(nil), "v2ray.core.app.router.Domain")
 	proto.RegisterType((*CIDR)(nil), "v2ray.core.app.router.CIDR")
+	proto.RegisterType((*CountryIPRange)(nil), "v2ray.core.app.router.CountryIPRange")
+	proto.RegisterType((*GeoIPList)(nil), "v2ray.core.app.router.GeoIPList")
+	proto.RegisterType((*GeoSite)(nil), "v2ray.core.app.router.GeoSite")
+	proto.RegisterType((*GeoSiteList)(nil), "v2ray.core.app.router.GeoSiteList")
 	proto.RegisterType((*RoutingRule)(nil), "v2ray.core.app.router.RoutingRule")
+	proto.RegisterType((*HealthCheck)(nil), "v2ray.core.app.router.HealthCheck")
+	proto.RegisterType((*BalancingRule)(nil), "v2ray.core.app.router.BalancingRule")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.router.Config")
 	proto.RegisterEnum("v2ray.core.app.router.Domain_Type", Domain_Type_name, Domain_Type_value)
+	proto.RegisterEnum("v2ray.core.app.router.BalancingRule_Strategy", BalancingRule_Strategy_name, BalancingRule_Strategy_value)
 	proto.RegisterEnum("v2ray.core.app.router.Config_DomainStrategy", Config_DomainStrategy_name, Config_DomainStrategy_value)
 }
 
 func init() { proto.RegisterFile("v2ray.com/core/app/router/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 538 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x93, 0xc1, 0x6e, 0xd4, 0x30,
-	0x10, 0x86, 0x49, 0x76, 0x1b, 0xba, 0x93, 0xb2, 0x44, 0x16, 0x45, 0xa1, 0xa8, 0x22, 0x8a, 0x10,
-	0xe4, 0x80, 0x12, 0x69, 0x11, 0x70, 0x01, 0xa1, 0xb2, 0xed, 0x61, 0x25, 0xa8, 0x2a, 0xd3, 0x72,
-	0xe0, 0x12, 0xb9, 0x59, 0x37, 0x58, 0x24, 0xb6, 0xe5, 0x38, 0xa5, 0x7b, 0xe3, 0x05, 0x78, 0x11,
-	0x9e, 0x86, 0x47, 0x42, 0xb6, 0x53, 0xd1, 0xa2, 0x2e, 0xdc, 0x66, 0x9c, 0xef, 0x9f, 0x19, 0x8f,
-	0xff, 0xc0, 0x93, 0xf3, 0x99, 0x22, 0xab, 0xbc, 0x12, 0x6d, 0x51, 0x09, 0x45, 0x0b, 0x22, 0x65,
-	0xa1, 0x44, 0xaf, 0xa9, 0x2a, 0x2a, 0xc1, 0xcf, 0x58, 0x9d, 0x4b, 0x25, 0xb4, 0x40, 0xdb, 0x97,
-	0x9c, 0xa2, 0x39, 0x91, 0x32, 0x77, 0xcc, 0xce, 0xe3, 0xbf, 0xe4, 0x95, 0x68, 0x5b, 0xc1, 0x0b,
-	0x4e, 0x75, 0x21, 0x85, 0xd2, 0x4e, 0xbc, 0xf3, 0x74, 0x3d, 0xc5, 0xa9, 0xfe, 0x26, 0xd4, 0x57,
-	0x07, 0xa6, 0xdf, 0x3d, 0x08, 0xf6, 0x45, 0x4b, 0x18, 0x47, 0x2f, 0x61, 0xac, 0x57, 0x92, 0xc6,
-	0x5e, 0xe2, 0x65, 0xd3, 0x59, 0x9a, 0xdf, 0xd8, 0x3f, 0x77, 0x70, 0x7e, 0xbc, 0x92, 0x14, 0x5b,
-	0x1e, 0xdd, 0x83, 0x8d, 0x73, 0xd2, 0xf4, 0x34, 0xf6, 0x13, 0x2f, 0x9b, 0x60, 0x97, 0xa4, 0x19,
-	0x8c, 0x0d, 0x83, 0x26, 0xb0, 0x71, 0xd4, 0x10, 0xc6, 0xa3, 0x5b, 0x26, 0xc4, 0xb4, 0xa6, 0x17,
-	0x91, 0x87, 0xe0, 0xb2, 0x6b, 0xe4, 0xa7, 0x39, 0x8c, 0xe7, 0x8b, 0x7d, 0x8c, 0xa6, 0xe0, 0x33,
-	0x69, 0xbb, 0x6f, 0x61, 0x9f, 0x49, 0x74, 0x1f, 0x02, 0xa9, 0xe8, 0x19, 0xbb, 0xb0, 0x85, 0xef,
-	0xe0, 0x21, 0x4b, 0x7f, 0x8c, 0x20, 0xc4, 0xa2, 0xd7, 0x8c, 0xd7, 0xb8, 0x6f, 0x28, 0x8a, 0x60,
-	0xa4, 0x49, 0x6d, 0x85, 0x13, 0x6c, 0x42, 0xf4, 0x02, 0x82, 0xa5, 0xad, 0x1e, 0xfb, 0xc9, 0x28,
-	0x0b, 0x67, 0xbb, 0xff, 0xbc, 0x0b, 0x1e, 0x60, 0x54, 0xc0, 0xb8, 0x62, 0x4b, 0x15, 0x8f, 0xac,
-	0xe8, 0xe1, 0x1a, 0x91, 0x99, 0x15, 0x5b, 0x10, 0xbd, 0x05, 0x30, 0x3b, 0x2f, 0x15, 0xe1, 0x35,
-	0x8d, 0xc7, 0x89, 0x97, 0x85, 0xb3, 0xe4, 0xaa, 0xcc, 0xad, 0x3d, 0xe7, 0x54, 0xe7, 0x47, 0x42,
-	0x69, 0x6c, 0x38, 0x3c, 0x91, 0x97, 0x21, 0x3a, 0x80, 0xad, 0xe1, 0x39, 0xca, 0x86, 0x75, 0x3a,
-	0xde, 0xb0, 0x25, 0xd2, 0x35, 0x25, 0x0e, 0x1d, 0xfa, 0x9e, 0x75, 0x1a, 0x87, 0xfc, 0x4f, 0x82,
-	0x5e, 0x43, 0xd8, 0x89, 0x5e, 0x55, 0xb4, 0xb4, 0xf3, 0x07, 0xff, 0x9f, 0x1f, 0x1c, 0x3f, 0x37,
-	0xb7, 0xd8, 0x05, 0xe8, 0x3b, 0xaa, 0x4a, 0xda, 0x12, 0xd6, 0xc4, 0xb7, 0x93, 0x51, 0x36, 0xc1,
-	0x13, 0x73, 0x72, 0x60, 0x0e, 0xd0, 0x23, 0x08, 0x19, 0x3f, 0x15, 0x3d, 0x5f, 0x96, 0x66, 0xcd,
-	0x9b, 0xf6, 0x3b, 0x0c, 0x47, 0xc7, 0xa4, 0x4e, 0x7f, 0x79, 0x10, 0xcc, 0xad, 0x73, 0xd1, 0x09,
-	0xdc, 0x75, 0xbb, 0x2c, 0x3b, 0xad, 0x88, 0xa6, 0xf5, 0x6a, 0x70, 0xd3, 0xb3, 0x75, 0xc3, 0x38,
-	0xc7, 0xbb, 0x87, 0xf8, 0x38, 0x68, 0xf0, 0x74, 0x79, 0x2d, 0x37, 0xce, 0x54, 0x7d, 0x43, 0x87,
-	0xd7, 0x5c, 0xe7, 0xcc, 0x2b, 0x9e, 0xc0, 0x96, 0x4f, 0x5f, 0xc1, 0xf4, 0x7a, 0x65, 0xb4, 0x09,
-	0xe3, 0xbd, 0x6e, 0xd1, 0x39, 0x33, 0x9e, 0x74, 0x74, 0x21, 0x23, 0x0f, 0x45, 0xb0, 0xb5, 0x90,
-	0x8b, 0xb3, 0x43, 0xc1, 0x3f, 0x10, 0x5d, 0x7d, 0x89, 0xfc, 0x77, 0x6f, 0xe0, 0x41, 0x25, 0xda,
-	0x9b, 0xfb, 0x1c, 0x79, 0x9f, 0x03, 0x17, 0xfd, 0xf4, 0xb7, 0x3f, 0xcd, 0x30, 0x59, 0xe5, 0x73,
-	0x43, 0xec, 0x49, 0x69, 0x47, 0xa0, 0xea, 0x34, 0xb0, 0xff, 0xd6, 0xf3, 0xdf, 0x01, 0x00, 0x00,
-	0xff, 0xff, 0xa7, 0x6a, 0x97, 0x93, 0xeb, 0x03, 0x00, 0x00,
+	// 975 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x6d, 0x6f, 0xe3, 0xc4,
+	0x13, 0x3f, 0x3b, 0x69, 0x9a, 0x8c, 0xd3, 0xfc, 0xad, 0xd5, 0xff, 0x90, 0x29, 0x1c, 0x57, 0xcc,
+	0x01, 0x95, 0xe0, 0x1c, 0x29, 0x3c, 0x8a, 0x07, 0x9d, 0x7a, 0xb9, 0xb6, 0x44, 0x94, 0x53, 0xb5,
+	0xd7, 0xe3, 0x05, 0x6f, 0xa2, 0x8d, 0x33, 0x75, 0x57, 0x75, 0x76, 0xad, 0xf5, 0xba, 0x5c, 0xbe,
+	0x00, 0x1f, 0x83, 0xd7, 0x88, 0x2f, 0xc0, 0xc7, 0x41, 0xe2, 0x93, 0xa0, 0x5d, 0xdb, 0x89, 0x0b,
+	0x67, 0x0e, 0xf1, 0xc6, 0xda, 0x19, 0xff, 0x66, 0x76, 0xe6, 0x37, 0x33, 0x3b, 0xf0, 0xde, 0xcd,
+	0x44, 0xb1, 0x75, 0x14, 0xcb, 0xd5, 0x38, 0x96, 0x0a, 0xc7, 0x2c, 0xcb, 0xc6, 0x4a, 0x16, 0x1a,
+	0xd5, 0x38, 0x96, 0xe2, 0x92, 0x27, 0x51, 0xa6, 0xa4, 0x96, 0xe4, 0x6e, 0x8d, 0x53, 0x18, 0xb1,
+	0x2c, 0x8b, 0x4a, 0xcc, 0xfe, 0x83, 0xbf, 0x98, 0xc7, 0x72, 0xb5, 0x92, 0x62, 0x2c, 0x50, 0x8f,
+	0x33, 0xa9, 0x74, 0x69, 0xbc, 0xff, 0x7e, 0x3b, 0x4a, 0xa0, 0xfe, 0x51, 0xaa, 0xeb, 0x0a, 0xf8,
+	0x41, 0x3b, 0x70, 0x89, 0xb9, 0xe6, 0x82, 0x69, 0x2e, 0x45, 0x09, 0x0e, 0x7f, 0x71, 0xa0, 0xf7,
+	0x44, 0xae, 0x18, 0x17, 0xe4, 0x53, 0xe8, 0xea, 0x75, 0x86, 0x81, 0x73, 0xe0, 0x1c, 0x8e, 0x26,
+	0x61, 0xf4, 0xd2, 0x60, 0xa3, 0x12, 0x1c, 0x5d, 0xac, 0x33, 0xa4, 0x16, 0x4f, 0xfe, 0x0f, 0x3b,
+	0x37, 0x2c, 0x2d, 0x30, 0x70, 0x0f, 0x9c, 0xc3, 0x01, 0x2d, 0x05, 0xf2, 0x26, 0x0c, 0x98, 0xd6,
+	0x8a, 0x2f, 0x0a, 0x8d, 0x41, 0xe7, 0xa0, 0x73, 0x38, 0xa0, 0x5b, 0x45, 0x38, 0x81, 0xae, 0xf1,
+	0x40, 0x06, 0xb0, 0x73, 0x9e, 0x32, 0x2e, 0xfc, 0x3b, 0xe6, 0x48, 0x31, 0xc1, 0x17, 0xbe, 0x43,
+	0xa0, 0x8e, 0xc9, 0x77, 0x49, 0x1f, 0xba, 0x27, 0x45, 0x9a, 0xfa, 0x9d, 0x30, 0x82, 0xee, 0x74,
+	0xf6, 0x84, 0x92, 0x11, 0xb8, 0x3c, 0xb3, 0x51, 0x0e, 0xa9, 0xcb, 0x33, 0xf2, 0x1a, 0xf4, 0x32,
+	0x85, 0x97, 0xfc, 0x85, 0x0d, 0x60, 0x8f, 0x56, 0x52, 0xf8, 0x08, 0x46, 0x53, 0x59, 0x08, 0xad,
+	0xd6, 0xb3, 0x73, 0xca, 0x44, 0x82, 0xe4, 0x21, 0x74, 0x78, 0x96, 0x07, 0xce, 0x41, 0xe7, 0xd0,
+	0x9b, 0xbc, 0xd1, 0x92, 0xa0, 0xb9, 0x83, 0x1a, 0x5c, 0xf8, 0x9b, 0x03, 0x83, 0x53, 0x94, 0xb3,
+	0xf3, 0x33, 0x9e, 0x6b, 0x72, 0x0a, 0xbb, 0x71, 0xe9, 0xae, 0x72, 0xf0, 0xb0, 0xc5, 0xc1, 0xc6,
+	0x24, 0xaa, 0xae, 0x3f, 0x36, 0x1f, 0x5a, 0x5b, 0xef, 0x33, 0x18, 0x36, 0x7f, 0x10, 0x1f, 0x3a,
+	0xd7, 0xb8, 0xb6, 0x09, 0x0d, 0xa8, 0x39, 0x92, 0x2f, 0x9b, 0x8c, 0x7a, 0x93, 0x77, 0xdb, 0x22,
+	0xbd, 0x95, 0x5d, 0x45, 0xfc, 0x17, 0xee, 0xe7, 0x4e, 0x28, 0x60, 0xf7, 0x14, 0xe5, 0x33, 0xae,
+	0x91, 0x10, 0xe8, 0x0a, 0xb6, 0xc2, 0xca, 0xbd, 0x3d, 0x93, 0x4f, 0xa0, 0xb7, 0xb4, 0xfc, 0x06,
+	0xae, 0xcd, 0xe4, 0xde, 0x3f, 0xd6, 0x9a, 0x56, 0x60, 0x12, 0xc0, 0x2e, 0x17, 0x71, 0x5a, 0x2c,
+	0xeb, 0x82, 0xd6, 0x62, 0x38, 0x05, 0xaf, 0xba, 0xcf, 0x52, 0xf5, 0x31, 0xec, 0x60, 0x83, 0xa8,
+	0xb7, 0xda, 0x89, 0x32, 0x26, 0xb4, 0x04, 0x87, 0x7f, 0x74, 0xc0, 0xa3, 0xb2, 0xd0, 0x5c, 0x24,
+	0xb4, 0x48, 0xd1, 0xf0, 0xa2, 0x59, 0x52, 0xf3, 0xa2, 0x59, 0xf2, 0x5f, 0xe3, 0x1e, 0x43, 0x37,
+	0xe6, 0x4b, 0x15, 0x74, 0x5e, 0x5d, 0x77, 0x0b, 0x24, 0x8f, 0x00, 0xcc, 0xe0, 0xcd, 0x95, 0xe1,
+	0x35, 0xe8, 0xda, 0x22, 0x1c, 0x34, 0xcd, 0xca, 0x91, 0x8a, 0x04, 0xea, 0xe8, 0x5c, 0x2a, 0x5d,
+	0xf2, 0x3f, 0xc8, 0xea, 0x23, 0x39, 0x86, 0x61, 0x35, 0x93, 0xf3, 0x94, 0xe7, 0x3a, 0xd8, 0xb1,
+	0x2e, 0xc2, 0x16, 0x17, 0x4f, 0x4b, 0xa8, 0xa1, 0x8e, 0x7a, 0x62, 0x2b, 0x90, 0xaf, 0xc0, 0xcb,
+	0x65, 0xa1, 0x62, 0x9c, 0xdb, 0xf8, 0x7b, 0xaf, 0x8e, 0x1f, 0x4a, 0xfc, 0xd4, 0x64, 0x71, 0x0f,
+	0xa0, 0xc8, 0x51, 0xcd, 0x71, 0xc5, 0x78, 0x1a, 0xec, 0x96, 0x23, 0x68, 0x34, 0xc7, 0x46, 0x41,
+	0xee, 0x83, 0xc7, 0xc5, 0x42, 0x16, 0x62, 0x39, 0x37, 0x34, 0xf7, 0xed, 0x7f, 0xa8, 0x54, 0x17,
+	0x2c, 0x31, 0x73, 0x9d, 0xa0, 0xe4, 0x59, 0x30, 0xb0, 0xbf, 0x4a, 0xc1, 0x34, 0x41, 0x82, 0x32,
+	0xe7, 0x1a, 0x03, 0x28, 0x9b, 0xa0, 0x12, 0xc9, 0x3b, 0xb0, 0xb7, 0x60, 0x29, 0x13, 0x31, 0x17,
+	0x89, 0x75, 0xe9, 0xd9, 0xca, 0x0d, 0x37, 0xca, 0x0b, 0x96, 0x84, 0x3f, 0x39, 0xe0, 0x7d, 0x83,
+	0x2c, 0xd5, 0x57, 0xd3, 0x2b, 0x8c, 0xaf, 0xc9, 0x3e, 0xf4, 0xb9, 0xd0, 0xa8, 0x6e, 0x58, 0x6a,
+	0x2b, 0xbd, 0x47, 0x37, 0x32, 0x39, 0x02, 0xaf, 0xf1, 0x60, 0x55, 0xc3, 0x70, 0xbf, 0x85, 0xc4,
+	0x63, 0xb1, 0xcc, 0x24, 0x17, 0x9a, 0x36, 0x6d, 0x4c, 0xb4, 0x9a, 0xaf, 0x50, 0x16, 0x3a, 0xe8,
+	0x58, 0xef, 0xb5, 0x18, 0xfe, 0xec, 0xc2, 0xde, 0xe3, 0x3a, 0xb2, 0x96, 0x7e, 0x7b, 0x1b, 0x86,
+	0xb2, 0xd0, 0x5b, 0x8e, 0x5c, 0x9b, 0xb0, 0x57, 0xeb, 0x0c, 0x49, 0x33, 0xe8, 0xe7, 0x5a, 0x31,
+	0x8d, 0xc9, 0xda, 0xde, 0x30, 0x6a, 0x7d, 0x16, 0x6e, 0x5d, 0x16, 0x3d, 0xab, 0x8c, 0xe8, 0xc6,
+	0xdc, 0x34, 0xcd, 0x95, 0x65, 0x66, 0x1e, 0x1b, 0x6a, 0x82, 0xee, 0xdf, 0x9b, 0xa6, 0xe1, 0xae,
+	0x41, 0x22, 0xf5, 0xae, 0xb6, 0x42, 0x78, 0x02, 0xfd, 0xda, 0xb9, 0x79, 0x48, 0x29, 0x13, 0x4b,
+	0xb9, 0xf2, 0xef, 0x90, 0x11, 0x00, 0x35, 0x51, 0x53, 0xb9, 0xe0, 0xc2, 0x77, 0x88, 0x0f, 0xc3,
+	0x33, 0x64, 0xb9, 0x3e, 0x63, 0x1a, 0x45, 0xbc, 0xf6, 0x5d, 0x32, 0x84, 0xfe, 0x09, 0xe3, 0xa9,
+	0xbc, 0x41, 0xe5, 0x77, 0xc2, 0xdf, 0x5d, 0xe8, 0x4d, 0xed, 0xf6, 0x22, 0xcf, 0xe1, 0x7f, 0xe5,
+	0x28, 0xcd, 0x37, 0xb9, 0x96, 0x4b, 0xe2, 0xc3, 0xd6, 0x97, 0xc9, 0xd8, 0x55, 0x73, 0xb8, 0x49,
+	0x75, 0xb4, 0xbc, 0x25, 0x9b, 0x85, 0xa3, 0x8a, 0x14, 0xab, 0x61, 0x6e, 0x4b, 0xb4, 0xf1, 0x24,
+	0x50, 0x8b, 0x37, 0x8d, 0x6d, 0x7b, 0x71, 0x7e, 0xc9, 0x53, 0xb4, 0xac, 0x0f, 0xe8, 0xc0, 0x6a,
+	0x4e, 0x78, 0x8a, 0xa6, 0x6a, 0x55, 0x4b, 0x96, 0x80, 0xae, 0x05, 0x78, 0x95, 0xce, 0x42, 0xbe,
+	0x85, 0xd1, 0xb6, 0x55, 0x6d, 0x0c, 0x3b, 0x36, 0x86, 0x07, 0xff, 0xa6, 0x76, 0x74, 0x6f, 0xd1,
+	0x14, 0xc3, 0xcf, 0x60, 0x74, 0x3b, 0x51, 0xb3, 0xb3, 0x8e, 0xf2, 0x59, 0x5e, 0x2e, 0xb5, 0xe7,
+	0x39, 0xce, 0xb2, 0x92, 0xef, 0x59, 0x36, 0xbb, 0x7c, 0x2a, 0xc5, 0x77, 0x4c, 0xc7, 0x57, 0xbe,
+	0xfb, 0xf8, 0x6b, 0x78, 0x3d, 0x96, 0xab, 0x97, 0x5f, 0x79, 0xee, 0xfc, 0xd0, 0x2b, 0x4f, 0xbf,
+	0xba, 0x77, 0xbf, 0x9f, 0x50, 0xb6, 0x8e, 0xa6, 0x06, 0x71, 0x94, 0x65, 0x96, 0x11, 0x54, 0x8b,
+	0x9e, 0xdd, 0xe0, 0x1f, 0xfd, 0x39, 0x00, 0x81, 0xc2, 0xac, 0x10, 0x7e, 0x08, 0x00, 0x00,
 }
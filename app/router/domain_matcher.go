@@ -0,0 +1,231 @@
+package router
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"v2ray.com/core/proxy"
+)
+
+// DomainMatcher matches domains against a list of Domains at once. Full domains are in a hash set, domains are in a
+// trie of reversed labels, and keywords are in an Aho-Corasick automaton, so that the cost of matching doesn't grow
+// with the size of the list. Regular expressions are matched one by one.
+type DomainMatcher struct {
+	full     map[string]bool
+	domains  *domainTrie
+	keywords *keywordAutomaton
+	regexps  []*regexp.Regexp
+}
+
+// NewDomainMatcher compiles the given domains into a DomainMatcher.
+func NewDomainMatcher(domains []*Domain) (*DomainMatcher, error) {
+	m := &DomainMatcher{
+		full:     make(map[string]bool),
+		domains:  newDomainTrie(),
+		keywords: newKeywordAutomaton(),
+	}
+	for _, domain := range domains {
+		switch domain.Type {
+		case Domain_Plain:
+			m.keywords.Add(domain.Value)
+		case Domain_Regex:
+			r, err := regexp.Compile(domain.Value)
+			if err != nil {
+				return nil, err
+			}
+			m.regexps = append(m.regexps, r)
+		case Domain_Domain:
+			m.domains.Add(domain.Value)
+		case Domain_Full:
+			m.full[domain.Value] = true
+		default:
+			panic("Unknown domain type.")
+		}
+	}
+	m.keywords.Build()
+	return m, nil
+}
+
+// Match returns true if the given domain matches any domain in the list.
+func (m *DomainMatcher) Match(domain string) bool {
+	if m.full[domain] || m.domains.Match(domain) || m.keywords.Match(domain) {
+		return true
+	}
+	if len(m.regexps) > 0 {
+		lower := strings.ToLower(domain)
+		for _, r := range m.regexps {
+			if r.MatchString(lower) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply implements Condition.
+func (m *DomainMatcher) Apply(ctx context.Context) bool {
+	dest, ok := proxy.TargetFromContext(ctx)
+	if !ok {
+		return false
+	}
+	if !dest.Address.Family().IsDomain() {
+		return false
+	}
+	return m.Match(dest.Address.Domain())
+}
+
+// domainTrie is a trie of domain labels from the top level, e.g., "www.v2ray.com" is stored as "com" -> "v2ray" -> "www".
+type domainTrie struct {
+	children map[string]*domainTrie
+	// end is true if a domain ends at this node, so that all its subdomains match.
+	end bool
+}
+
+func newDomainTrie() *domainTrie {
+	return new(domainTrie)
+}
+
+// Add adds the given domain, which matches itself and all its subdomains.
+func (t *domainTrie) Add(domain string) {
+	node := t
+	for {
+		idx := strings.LastIndexByte(domain, '.')
+		label := domain[idx+1:]
+		if node.children == nil {
+			node.children = make(map[string]*domainTrie)
+		}
+		child, found := node.children[label]
+		if !found {
+			child = new(domainTrie)
+			node.children[label] = child
+		}
+		node = child
+		if idx < 0 {
+			break
+		}
+		domain = domain[:idx]
+	}
+	node.end = true
+}
+
+// Match returns true if the given domain is, or is a subdomain of, any added domain.
+func (t *domainTrie) Match(domain string) bool {
+	node := t
+	for {
+		idx := strings.LastIndexByte(domain, '.')
+		node = node.children[domain[idx+1:]]
+		if node == nil {
+			return false
+		}
+		if node.end {
+			return true
+		}
+		if idx < 0 {
+			return false
+		}
+		domain = domain[:idx]
+	}
+}
+
+// keywordNode is a state of keywordAutomaton.
+type keywordNode struct {
+	next map[byte]int32
+	fail int32
+	// match is true if a keyword ends at this state, or at any state on its failure chain.
+	match bool
+}
+
+// keywordAutomaton is an Aho-Corasick automaton, which finds whether any of a set of keywords is a substring of
+// a string, in time linear to the length of the string.
+type keywordAutomaton struct {
+	nodes []keywordNode
+	// empty is true if an empty keyword is added, which matches everything.
+	empty bool
+}
+
+func newKeywordAutomaton() *keywordAutomaton {
+	return &keywordAutomaton{
+		nodes: []keywordNode{{}},
+	}
+}
+
+// Add adds a keyword. Build must be called after all keywords are added.
+func (a *keywordAutomaton) Add(keyword string) {
+	if len(keyword) == 0 {
+		a.empty = true
+		return
+	}
+	state := int32(0)
+	for i := 0; i < len(keyword); i++ {
+		c := keyword[i]
+		next, found := a.nodes[state].next[c]
+		if !found {
+			if a.nodes[state].next == nil {
+				a.nodes[state].next = make(map[byte]int32)
+			}
+			next = int32(len(a.nodes))
+			a.nodes = append(a.nodes, keywordNode{})
+			a.nodes[state].next[c] = next
+		}
+		state = next
+	}
+	a.nodes[state].match = true
+}
+
+// Build computes failure links of all states, in breadth-first order.
+func (a *keywordAutomaton) Build() {
+	queue := make([]int32, 0, len(a.nodes))
+	for _, child := range a.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for c, child := range a.nodes[state].next {
+			fail := a.nodes[state].fail
+			for {
+				if next, found := a.nodes[fail].next[c]; found {
+					a.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					break
+				}
+				fail = a.nodes[fail].fail
+			}
+			if a.nodes[a.nodes[child].fail].match {
+				a.nodes[child].match = true
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Match returns true if any keyword is a substring of s.
+func (a *keywordAutomaton) Match(s string) bool {
+	if a.empty {
+		return true
+	}
+	if len(a.nodes) == 1 {
+		return false
+	}
+	state := int32(0)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		for {
+			if next, found := a.nodes[state].next[c]; found {
+				state = next
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = a.nodes[state].fail
+		}
+		if a.nodes[state].match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package router_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	. "v2ray.com/core/app/router"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestDomainMatcher(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher, err := NewDomainMatcher([]*Domain{
+		{Type: Domain_Full, Value: "www.v2ray.com"},
+		{Type: Domain_Domain, Value: "google.com"},
+		{Type: Domain_Domain, Value: "mail.qq.com"},
+		{Type: Domain_Plain, Value: "he"},
+		{Type: Domain_Plain, Value: "she"},
+		{Type: Domain_Plain, Value: "hers"},
+		{Type: Domain_Plain, Value: "abcd"},
+		{Type: Domain_Plain, Value: "bc"},
+		{Type: Domain_Regex, Value: "^facebook\\.com$"},
+	})
+	assert.Error(err).IsNil()
+
+	cases := []struct {
+		domain string
+		match  bool
+	}{
+		{"www.v2ray.com", true},
+		{"v2ray.com", false},
+		{"a.www.v2ray.com", false},
+		{"google.com", true},
+		{"www.google.com", true},
+		{"www.agoogle.com", false},
+		{"com", false},
+		{"mail.qq.com", true},
+		{"x.mail.qq.com", true},
+		{"qq.com", false},
+		{"ushe.net", true},
+		{"sher.net", true},
+		{"xabcx.net", true},
+		{"abxd.net", false},
+		{"facebook.com", true},
+		{"FACEBOOK.com", true},
+		{"www.facebook.com", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		assert.Bool(matcher.Match(c.domain)).Equals(c.match)
+	}
+
+	ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.DomainAddress("www.google.com"), 80))
+	assert.Bool(matcher.Apply(ctx)).IsTrue()
+	ctx = proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.ParseAddress("8.8.8.8"), 80))
+	assert.Bool(matcher.Apply(ctx)).IsFalse()
+
+	_, err = NewDomainMatcher([]*Domain{{Type: Domain_Regex, Value: "("}})
+	assert.Error(err).IsNotNil()
+}
+
+// TestDomainMatcherKeywordSuffix covers keywords found only through failure links.
+func TestDomainMatcherKeywordSuffix(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher, err := NewDomainMatcher([]*Domain{
+		{Type: Domain_Plain, Value: "abcde"},
+		{Type: Domain_Plain, Value: "cd"},
+	})
+	assert.Error(err).IsNil()
+	assert.Bool(matcher.Match("abcx")).IsFalse()
+	assert.Bool(matcher.Match("abcdx")).IsTrue()
+	assert.Bool(matcher.Match("xxabcx")).IsFalse()
+}
+
+const benchmarkDomains = 100000
+
+func benchmarkDomainList(domainType Domain_Type) []*Domain {
+	domains := make([]*Domain, 0, benchmarkDomains)
+	for i := 0; i < benchmarkDomains; i++ {
+		domains = append(domains, &Domain{
+			Type:  domainType,
+			Value: "site" + strconv.Itoa(i) + ".example" + strconv.Itoa(i%100) + ".com",
+		})
+	}
+	return domains
+}
+
+func benchmarkDomainMatcher(b *testing.B, domainType Domain_Type, domain string) {
+	matcher, err := NewDomainMatcher(benchmarkDomainList(domainType))
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.DomainAddress(domain), 80))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matcher.Apply(ctx)
+	}
+}
+
+func BenchmarkDomainMatcherFull(b *testing.B) {
+	benchmarkDomainMatcher(b, Domain_Full, "site99999.example99.com")
+}
+
+func BenchmarkDomainMatcherDomain(b *testing.B) {
+	benchmarkDomainMatcher(b, Domain_Domain, "www.site99999.example99.com")
+}
+
+func BenchmarkDomainMatcherKeyword(b *testing.B) {
+	benchmarkDomainMatcher(b, Domain_Plain, "www.site99999.example99.com")
+}
+
+func BenchmarkDomainMatcherMiss(b *testing.B) {
+	matcher, err := NewDomainMatcher(append(benchmarkDomainList(Domain_Domain), benchmarkDomainList(Domain_Plain)...))
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.DomainAddress("www.v2ray.com"), 80))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matcher.Apply(ctx)
+	}
+}
+
+// BenchmarkAnyConditionDomain is the linear matching for comparison.
+func BenchmarkAnyConditionDomain(b *testing.B) {
+	cond := NewAnyCondition()
+	for _, domain := range benchmarkDomainList(Domain_Domain) {
+		cond.Add(NewSubDomainMatcher(domain.Value))
+	}
+	ctx := proxy.ContextWithTarget(context.Background(), net.TCPDestination(net.DomainAddress("www.v2ray.com"), 80))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cond.Apply(ctx)
+	}
+}
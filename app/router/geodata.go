@@ -0,0 +1,155 @@
+package router
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/common/platform"
+)
+
+// GetGeoIPFileValue returns the path of the GeoIP data file, or the default path if not set.
+func (c *Config) GetGeoIPFileValue() string {
+	if len(c.GeoipFile) == 0 {
+		return platform.GetAssetLocation("geoip.dat")
+	}
+	return c.GeoipFile
+}
+
+// GetGeoSiteFileValue returns the path of the domain list data file, or the default path if not set.
+func (c *Config) GetGeoSiteFileValue() string {
+	if len(c.GeositeFile) == 0 {
+		return platform.GetAssetLocation("geosite.dat")
+	}
+	return c.GeositeFile
+}
+
+// LoadGeoIPList reads a GeoIP data file.
+func LoadGeoIPList(path string) (*GeoIPList, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, newError("failed to read GeoIP file ", path).Base(err)
+	}
+	list := new(GeoIPList)
+	if err := proto.Unmarshal(content, list); err != nil {
+		return nil, newError("invalid GeoIP file ", path).Base(err)
+	}
+	return list, nil
+}
+
+// LoadGeoSiteList reads a domain list data file.
+func LoadGeoSiteList(path string) (*GeoSiteList, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, newError("failed to read domain list file ", path).Base(err)
+	}
+	list := new(GeoSiteList)
+	if err := proto.Unmarshal(content, list); err != nil {
+		return nil, newError("invalid domain list file ", path).Base(err)
+	}
+	return list, nil
+}
+
+// geoLoader loads data files for routing rules on first use, so that files are not read unless rules refer to them,
+// and each file is read at most once for all rules.
+type geoLoader struct {
+	geoIPFile   string
+	geoIP       *GeoIPList
+	geoSiteFile string
+	geoSite     map[string]*GeoSite
+}
+
+func newGeoLoader(config *Config) *geoLoader {
+	return &geoLoader{
+		geoIPFile:   config.GetGeoIPFileValue(),
+		geoSiteFile: config.GetGeoSiteFileValue(),
+	}
+}
+
+// loadGeoIP returns the IP ranges of the given country code, such as "cn" or "geoip:cn".
+func (l *geoLoader) loadGeoIP(code string) ([]*CIDR, error) {
+	if l.geoIP == nil {
+		list, err := LoadGeoIPList(l.geoIPFile)
+		if err != nil {
+			return nil, err
+		}
+		l.geoIP = list
+	}
+
+	code = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(code)), "geoip:")
+	ipRange, found := l.geoIP.Country[code]
+	if !found {
+		return nil, newError("country code ", code, " is not found in GeoIP file ", l.geoIPFile)
+	}
+	return ipRange.Ips, nil
+}
+
+// loadGeoSite returns the domains of the given domain list, such as "cn", "geosite:cn" or "geosite:google@ads".
+func (l *geoLoader) loadGeoSite(name string) ([]*Domain, error) {
+	if l.geoSite == nil {
+		list, err := LoadGeoSiteList(l.geoSiteFile)
+		if err != nil {
+			return nil, err
+		}
+		l.geoSite = make(map[string]*GeoSite, len(list.Entry))
+		for _, site := range list.Entry {
+			l.geoSite[strings.ToLower(site.Name)] = site
+		}
+	}
+
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "geosite:")
+	return l.collectGeoSite(name, make(map[string]bool))
+}
+
+// collectGeoSite returns the domains of the given list and its included lists, with the attributes following "@"
+// in name. visiting holds the lists being collected, to detect loops of includes.
+func (l *geoLoader) collectGeoSite(name string, visiting map[string]bool) ([]*Domain, error) {
+	parts := strings.Split(name, "@")
+	name, attributes := parts[0], parts[1:]
+	if visiting[name] {
+		return nil, newError("domain list ", name, " includes itself in ", l.geoSiteFile)
+	}
+	site, found := l.geoSite[name]
+	if !found {
+		return nil, newError("domain list ", name, " is not found in ", l.geoSiteFile)
+	}
+
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	domains := make([]*Domain, 0, len(site.Domain))
+	for _, domain := range site.Domain {
+		if hasAttributes(domain, attributes) {
+			domains = append(domains, domain)
+		}
+	}
+	for _, include := range site.Include {
+		include = strings.ToLower(strings.TrimSpace(include))
+		if len(attributes) > 0 {
+			include += "@" + strings.Join(attributes, "@")
+		}
+		included, err := l.collectGeoSite(include, visiting)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, included...)
+	}
+	return domains, nil
+}
+
+// hasAttributes returns true if the domain has all the given attributes.
+func hasAttributes(domain *Domain, attributes []string) bool {
+	for _, attribute := range attributes {
+		found := false
+		for _, a := range domain.Attribute {
+			if strings.EqualFold(a, attribute) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
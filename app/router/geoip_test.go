@@ -0,0 +1,128 @@
+package router_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/proxyman"
+	. "v2ray.com/core/app/router"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+)
+
+func writeGeoIPFile(assert *assert.Assert, dir string) string {
+	list := &GeoIPList{
+		Country: map[string]*CountryIPRange{
+			"cn": {
+				Ips: []*CIDR{
+					{Ip: []byte{1, 0, 1, 0}, Prefix: 24},
+					{Ip: []byte{0x24, 0x0e, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, Prefix: 20},
+				},
+			},
+			"private": {
+				Ips: []*CIDR{
+					{Ip: []byte{192, 168, 0, 0}, Prefix: 16},
+				},
+			},
+		},
+	}
+	content, err := proto.Marshal(list)
+	assert.Error(err).IsNil()
+
+	path := filepath.Join(dir, "geoip.dat")
+	assert.Error(ioutil.WriteFile(path, content, 0600)).IsNil()
+	return path
+}
+
+func newRouterSpace(assert *assert.Assert, config *Config) (context.Context, app.Space) {
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, config)).IsNil()
+	return ctx, space
+}
+
+func TestGeoIPRule(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-geoip")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	config := &Config{
+		GeoipFile: writeGeoIPFile(assert, dir),
+		Rule: []*RoutingRule{
+			{
+				Tag:   "direct",
+				Geoip: []string{"geoip:CN", "private"},
+				Cidr: []*CIDR{
+					{Ip: []byte{8, 8, 8, 8}, Prefix: 32},
+				},
+			},
+		},
+	}
+	ctx, space := newRouterSpace(assert, config)
+	assert.Error(space.Initialize()).IsNil()
+	r := FromSpace(space)
+
+	cases := []struct {
+		ip  string
+		tag string
+	}{
+		{"1.0.1.2", "direct"},
+		{"192.168.1.1", "direct"},
+		{"8.8.8.8", "direct"},
+		{"240e::1", "direct"},
+		{"1.0.2.1", ""},
+		{"2001::1", ""},
+	}
+	for _, c := range cases {
+		tag, err := r.TakeDetour(proxy.ContextWithTarget(ctx, net.TCPDestination(net.ParseAddress(c.ip), 80)))
+		if len(c.tag) > 0 {
+			assert.Error(err).IsNil()
+			assert.String(tag).Equals(c.tag)
+		} else {
+			assert.Error(err).Equals(ErrNoRuleApplicable)
+		}
+	}
+}
+
+func TestGeoIPRuleErrors(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-geoip")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	_, space := newRouterSpace(assert, &Config{
+		GeoipFile: writeGeoIPFile(assert, dir),
+		Rule: []*RoutingRule{
+			{
+				Tag:   "direct",
+				Geoip: []string{"us"},
+			},
+		},
+	})
+	assert.Error(space.Initialize()).IsNotNil()
+
+	_, space = newRouterSpace(assert, &Config{
+		GeoipFile: filepath.Join(dir, "missing.dat"),
+		Rule: []*RoutingRule{
+			{
+				Tag:   "direct",
+				Geoip: []string{"cn"},
+			},
+		},
+	})
+	assert.Error(space.Initialize()).IsNotNil()
+}
@@ -0,0 +1,133 @@
+package router_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	. "v2ray.com/core/app/router"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+)
+
+func writeGeoSiteFile(assert *assert.Assert, dir string) string {
+	list := &GeoSiteList{
+		Entry: []*GeoSite{
+			{
+				Name: "google",
+				Domain: []*Domain{
+					{Type: Domain_Domain, Value: "google.com"},
+					{Type: Domain_Domain, Value: "doubleclick.net", Attribute: []string{"ads"}},
+				},
+			},
+			{
+				Name: "ads",
+				Domain: []*Domain{
+					{Type: Domain_Plain, Value: "adserver"},
+				},
+				Include: []string{"google@ads"},
+			},
+			{
+				Name: "CN",
+				Domain: []*Domain{
+					{Type: Domain_Full, Value: "www.baidu.com"},
+					{Type: Domain_Regex, Value: "\\.cn$"},
+				},
+			},
+			{
+				Name:    "loop",
+				Include: []string{"loop2"},
+			},
+			{
+				Name:    "loop2",
+				Include: []string{"loop"},
+			},
+		},
+	}
+	content, err := proto.Marshal(list)
+	assert.Error(err).IsNil()
+
+	path := filepath.Join(dir, "geosite.dat")
+	assert.Error(ioutil.WriteFile(path, content, 0600)).IsNil()
+	return path
+}
+
+func TestGeoSiteRule(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-geosite")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	config := &Config{
+		GeositeFile: writeGeoSiteFile(assert, dir),
+		Rule: []*RoutingRule{
+			{
+				Tag:     "block",
+				Geosite: []string{"geosite:ads"},
+			},
+			{
+				Tag:     "direct",
+				Geosite: []string{"cn"},
+				Domain: []*Domain{
+					{Type: Domain_Domain, Value: "v2ray.com"},
+				},
+			},
+			{
+				Tag:     "proxy",
+				Geosite: []string{"google"},
+			},
+		},
+	}
+	ctx, space := newRouterSpace(assert, config)
+	assert.Error(space.Initialize()).IsNil()
+	r := FromSpace(space)
+
+	cases := []struct {
+		domain string
+		tag    string
+	}{
+		{"adserver.example.com", "block"},
+		{"ad.doubleclick.net", "block"},
+		{"www.google.com", "proxy"},
+		{"www.baidu.com", "direct"},
+		{"baidu.com", ""},
+		{"www.gov.cn", "direct"},
+		{"www.v2ray.com", "direct"},
+		{"example.com", ""},
+	}
+	for _, c := range cases {
+		tag, err := r.TakeDetour(proxy.ContextWithTarget(ctx, net.TCPDestination(net.DomainAddress(c.domain), 80)))
+		if len(c.tag) > 0 {
+			assert.Error(err).IsNil()
+			assert.String(tag).Equals(c.tag)
+		} else {
+			assert.Error(err).Equals(ErrNoRuleApplicable)
+		}
+	}
+}
+
+func TestGeoSiteRuleErrors(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-geosite")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	path := writeGeoSiteFile(assert, dir)
+	for _, name := range []string{"missing", "loop"} {
+		_, space := newRouterSpace(assert, &Config{
+			GeositeFile: path,
+			Rule: []*RoutingRule{
+				{
+					Tag:     "block",
+					Geosite: []string{name},
+				},
+			},
+		})
+		assert.Error(space.Initialize()).IsNotNil()
+	}
+}
@@ -0,0 +1,198 @@
+package router
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sort"
+
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+)
+
+// uint128 is an IPv6 address as an integer.
+type uint128 struct {
+	hi, lo uint64
+}
+
+func (a uint128) less(b uint128) bool {
+	return a.hi < b.hi || (a.hi == b.hi && a.lo < b.lo)
+}
+
+// next returns a+1, and false if it overflows.
+func (a uint128) next() (uint128, bool) {
+	if a.lo != ^uint64(0) {
+		return uint128{a.hi, a.lo + 1}, true
+	}
+	if a.hi != ^uint64(0) {
+		return uint128{a.hi + 1, 0}, true
+	}
+	return a, false
+}
+
+// ipv4Range is an inclusive range of IPv4 addresses.
+type ipv4Range struct {
+	start, end uint32
+}
+
+// ipv6Range is an inclusive range of IPv6 addresses.
+type ipv6Range struct {
+	start, end uint128
+}
+
+// IPMatcher matches IPs against a list of CIDRs. The CIDRs are merged into sorted ranges without overlap, so that an
+// IP is matched by binary search, in logarithmic time of the size of the list. It matches either the target, including
+// IPs resolved from the target domain, or the source.
+type IPMatcher struct {
+	ip4      []ipv4Range
+	ip6      []ipv6Range
+	onSource bool
+}
+
+// NewIPMatcher creates an IPMatcher of the given CIDRs.
+func NewIPMatcher(cidrs []*CIDR, onSource bool) (*IPMatcher, error) {
+	m := &IPMatcher{
+		onSource: onSource,
+	}
+	for _, cidr := range cidrs {
+		switch len(cidr.Ip) {
+		case net.IPv4len:
+			if cidr.Prefix > 32 {
+				return nil, newError("invalid prefix ", cidr.Prefix, " of IPv4").AtError()
+			}
+			mask := ^uint32(0)
+			if cidr.Prefix < 32 {
+				mask = ^(^uint32(0) >> cidr.Prefix)
+			}
+			start := binary.BigEndian.Uint32(cidr.Ip) & mask
+			m.ip4 = append(m.ip4, ipv4Range{start, start | ^mask})
+		case net.IPv6len:
+			if cidr.Prefix > 128 {
+				return nil, newError("invalid prefix ", cidr.Prefix, " of IPv6").AtError()
+			}
+			hiMask, loMask := ^uint64(0), ^uint64(0)
+			if cidr.Prefix < 64 {
+				hiMask, loMask = ^(^uint64(0) >> cidr.Prefix), 0
+			} else if cidr.Prefix < 128 {
+				loMask = ^(^uint64(0) >> (cidr.Prefix - 64))
+			}
+			start := uint128{binary.BigEndian.Uint64(cidr.Ip[:8]) & hiMask, binary.BigEndian.Uint64(cidr.Ip[8:]) & loMask}
+			m.ip6 = append(m.ip6, ipv6Range{start, uint128{start.hi | ^hiMask, start.lo | ^loMask}})
+		default:
+			return nil, newError("invalid IP length").AtError()
+		}
+	}
+	m.ip4 = mergeIPv4Ranges(m.ip4)
+	m.ip6 = mergeIPv6Ranges(m.ip6)
+	return m, nil
+}
+
+func mergeIPv4Ranges(ranges []ipv4Range) []ipv4Range {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if last.end == ^uint32(0) || r.start <= last.end+1 {
+			if r.end > last.end {
+				last.end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func mergeIPv6Ranges(ranges []ipv6Range) []ipv6Range {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start.less(ranges[j].start)
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if next, ok := last.end.next(); !ok || !next.less(r.start) {
+			if last.end.less(r.end) {
+				last.end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func (m *IPMatcher) match4(ip uint32) bool {
+	// Find the first range that ends at or after ip.
+	lo, hi := 0, len(m.ip4)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if m.ip4[mid].end < ip {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo < len(m.ip4) && m.ip4[lo].start <= ip
+}
+
+func (m *IPMatcher) match6(ip uint128) bool {
+	lo, hi := 0, len(m.ip6)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if m.ip6[mid].end.less(ip) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo < len(m.ip6) && !ip.less(m.ip6[lo].start)
+}
+
+// MatchAddress returns true if the given address is an IP in any of the CIDRs.
+func (m *IPMatcher) MatchAddress(address v2net.Address) bool {
+	hi, lo, ok := v2net.AddressToUint128(address)
+	if !ok {
+		return false
+	}
+	if address.Family().IsIPv4() {
+		return m.match4(uint32(lo))
+	}
+	return m.match6(uint128{hi, lo})
+}
+
+// Match returns true if the given IP is in any of the CIDRs.
+func (m *IPMatcher) Match(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		return m.match4(binary.BigEndian.Uint32(ip4))
+	}
+	if len(ip) != net.IPv6len {
+		return false
+	}
+	return m.match6(uint128{binary.BigEndian.Uint64(ip[:8]), binary.BigEndian.Uint64(ip[8:])})
+}
+
+// Apply implements Condition.
+func (m *IPMatcher) Apply(ctx context.Context) bool {
+	if m.onSource {
+		source, ok := proxy.SourceFromContext(ctx)
+		return ok && m.MatchAddress(source.Address)
+	}
+
+	if ips, ok := proxy.ResolvedIPsFromContext(ctx); ok {
+		for _, ip := range ips {
+			if m.MatchAddress(ip) {
+				return true
+			}
+		}
+	}
+	dest, ok := proxy.TargetFromContext(ctx)
+	return ok && m.MatchAddress(dest.Address)
+}
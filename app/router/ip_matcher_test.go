@@ -0,0 +1,172 @@
+package router_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	. "v2ray.com/core/app/router"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/assert"
+)
+
+func parseCIDRs(assert *assert.Assert, cidrs ...string) []*CIDR {
+	result := make([]*CIDR, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		assert.Error(err).IsNil()
+		ones, _ := network.Mask.Size()
+		result = append(result, &CIDR{
+			Ip:     []byte(network.IP),
+			Prefix: uint32(ones),
+		})
+	}
+	return result
+}
+
+func TestIPMatcher(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher, err := NewIPMatcher(parseCIDRs(assert,
+		"10.0.0.0/8",
+		"10.1.0.0/16",
+		"192.168.0.0/24",
+		"192.168.1.0/24",
+		"8.8.8.8/32",
+		"255.255.255.0/24",
+		"2001:db8::/32",
+		"2001:db9::1/128",
+		"ffff::/16",
+	), false)
+	assert.Error(err).IsNil()
+
+	cases := []struct {
+		ip    string
+		match bool
+	}{
+		{"10.255.255.255", true},
+		{"10.1.2.3", true},
+		{"11.0.0.0", false},
+		{"9.255.255.255", false},
+		{"192.168.0.1", true},
+		{"192.168.1.255", true},
+		{"192.168.2.0", false},
+		{"8.8.8.8", true},
+		{"8.8.8.9", false},
+		{"255.255.255.255", true},
+		{"0.0.0.0", false},
+		{"2001:db8:ffff::1", true},
+		{"2001:db9::1", true},
+		{"2001:db9::2", false},
+		{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", true},
+		{"::", false},
+		{"::ffff:10.0.0.1", true},
+	}
+	for _, c := range cases {
+		ip := net.ParseIP(c.ip)
+		assert.Bool(matcher.Match(ip)).Equals(c.match)
+		assert.Bool(matcher.MatchAddress(v2net.IPAddress(ip))).Equals(c.match)
+	}
+	assert.Bool(matcher.MatchAddress(v2net.DomainAddress("v2ray.com"))).IsFalse()
+
+	_, err = NewIPMatcher([]*CIDR{{Ip: []byte{1, 2, 3}, Prefix: 8}}, false)
+	assert.Error(err).IsNotNil()
+	_, err = NewIPMatcher([]*CIDR{{Ip: []byte{1, 2, 3, 4}, Prefix: 33}}, false)
+	assert.Error(err).IsNotNil()
+}
+
+func TestIPMatcherFullRange(t *testing.T) {
+	assert := assert.On(t)
+
+	matcher, err := NewIPMatcher(parseCIDRs(assert, "0.0.0.0/0", "::/0"), false)
+	assert.Error(err).IsNil()
+	assert.Bool(matcher.Match(net.ParseIP("1.2.3.4"))).IsTrue()
+	assert.Bool(matcher.Match(net.ParseIP("2001::1"))).IsTrue()
+
+	// IPv6 rules don't apply to IPv4 addresses.
+	matcher, err = NewIPMatcher(parseCIDRs(assert, "::/0"), false)
+	assert.Error(err).IsNil()
+	assert.Bool(matcher.Match(net.ParseIP("1.2.3.4"))).IsFalse()
+}
+
+func TestIPMatcherApply(t *testing.T) {
+	assert := assert.On(t)
+
+	target, err := NewIPMatcher(parseCIDRs(assert, "10.0.0.0/8"), false)
+	assert.Error(err).IsNil()
+	source, err := NewIPMatcher(parseCIDRs(assert, "10.0.0.0/8"), true)
+	assert.Error(err).IsNil()
+
+	ctx := proxy.ContextWithSource(context.Background(), v2net.TCPDestination(v2net.ParseAddress("10.0.0.1"), 1234))
+	ctx = proxy.ContextWithTarget(ctx, v2net.TCPDestination(v2net.DomainAddress("v2ray.com"), 80))
+	assert.Bool(target.Apply(ctx)).IsFalse()
+	assert.Bool(source.Apply(ctx)).IsTrue()
+
+	ctx = proxy.ContextWithResolveIPs(ctx, []v2net.Address{v2net.ParseAddress("2001::1"), v2net.ParseAddress("10.2.3.4")})
+	assert.Bool(target.Apply(ctx)).IsTrue()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		target.Apply(ctx)
+		source.Apply(ctx)
+	})
+	assert.Int(int(allocs)).Equals(0)
+}
+
+func benchmarkCIDRs(count int, ipv6 bool) []*CIDR {
+	cidrs := make([]*CIDR, 0, count)
+	for i := 0; i < count; i++ {
+		if ipv6 {
+			ip := make([]byte, 16)
+			binary.BigEndian.PutUint32(ip, 0x20010000|uint32(i))
+			cidrs = append(cidrs, &CIDR{Ip: ip, Prefix: 32})
+		} else {
+			ip := make([]byte, 4)
+			binary.BigEndian.PutUint32(ip, uint32(i)<<13)
+			cidrs = append(cidrs, &CIDR{Ip: ip, Prefix: 20})
+		}
+	}
+	return cidrs
+}
+
+func benchmarkIPMatcher(b *testing.B, ipv6 bool, ip string) {
+	matcher, err := NewIPMatcher(benchmarkCIDRs(20000, ipv6), false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := proxy.ContextWithTarget(context.Background(), v2net.TCPDestination(v2net.ParseAddress(ip), 80))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matcher.Apply(ctx)
+	}
+}
+
+func BenchmarkIPMatcherIPv4(b *testing.B) {
+	benchmarkIPMatcher(b, false, "2.0.16.1")
+}
+
+func BenchmarkIPMatcherIPv6(b *testing.B) {
+	benchmarkIPMatcher(b, true, "2001:4e20::1")
+}
+
+// BenchmarkAnyConditionIPv6 is the linear matching for comparison.
+func BenchmarkAnyConditionIPv6(b *testing.B) {
+	cond := NewAnyCondition()
+	for _, cidr := range benchmarkCIDRs(20000, true) {
+		matcher, err := NewCIDRMatcher(cidr.Ip, cidr.Prefix, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		cond.Add(matcher)
+	}
+	ctx := proxy.ContextWithTarget(context.Background(), v2net.TCPDestination(v2net.ParseAddress("2001:4e20::1"), 80))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cond.Apply(ctx)
+	}
+}
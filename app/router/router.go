@@ -4,10 +4,13 @@ package router
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
 	"v2ray.com/core/app"
-	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/dns/resolver"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/proxy"
@@ -18,9 +21,12 @@ var (
 )
 
 type Router struct {
+	sync.RWMutex
 	domainStrategy Config_DomainStrategy
-	rules          []Rule
-	dnsServer      dns.Server
+	rules          []*Rule
+	balancers      map[string]*Balancer
+	dnsServer      resolver.Server
+	ohm            proxyman.OutboundHandlerManager
 }
 
 func NewRouter(ctx context.Context, config *Config) (*Router, error) {
@@ -30,20 +36,22 @@ func NewRouter(ctx context.Context, config *Config) (*Router, error) {
 	}
 	r := &Router{
 		domainStrategy: config.DomainStrategy,
-		rules:          make([]Rule, len(config.Rule)),
 	}
 
 	space.OnInitialize(func() error {
-		for idx, rule := range config.Rule {
-			r.rules[idx].Tag = rule.Tag
-			cond, err := rule.BuildCondition()
-			if err != nil {
-				return err
-			}
-			r.rules[idx].Condition = cond
+		r.ohm = proxyman.OutboundHandlerManagerFromSpace(space)
+		balancers, err := buildBalancers(config, r.ohm)
+		if err != nil {
+			return err
+		}
+		rules, err := buildRules(config, balancers)
+		if err != nil {
+			return err
 		}
+		r.rules = rules
+		r.balancers = balancers
 
-		r.dnsServer = dns.FromSpace(space)
+		r.dnsServer = resolver.FromSpace(space)
 		if r.dnsServer == nil {
 			return newError("DNS is not found in the space")
 		}
@@ -52,8 +60,86 @@ func NewRouter(ctx context.Context, config *Config) (*Router, error) {
 	return r, nil
 }
 
-func (r *Router) resolveIP(dest net.Destination) []net.Address {
-	ips := r.dnsServer.Get(dest.Address.Domain())
+// buildBalancers builds the balancers in the config. Outbounds of the balancers must be in the given manager.
+func buildBalancers(config *Config, ohm proxyman.OutboundHandlerManager) (map[string]*Balancer, error) {
+	balancers := make(map[string]*Balancer, len(config.BalancingRule))
+	for _, rule := range config.BalancingRule {
+		if _, found := balancers[rule.Tag]; found {
+			return nil, newError("duplicate balancer tag ", rule.Tag).AtError()
+		}
+		if ohm == nil {
+			return nil, newError("outbound manager is not found for balancer ", rule.Tag).AtError()
+		}
+		for _, tag := range rule.OutboundTag {
+			if ohm.GetHandler(tag) == nil {
+				return nil, newError("outbound ", tag, " of balancer ", rule.Tag, " is not found").AtError()
+			}
+		}
+		balancer, err := NewBalancer(rule, ohm)
+		if err != nil {
+			return nil, err
+		}
+		balancers[rule.Tag] = balancer
+	}
+	return balancers, nil
+}
+
+func buildRules(config *Config, balancers map[string]*Balancer) ([]*Rule, error) {
+	rules := make([]*Rule, len(config.Rule))
+	geo := newGeoLoader(config)
+	for idx, rule := range config.Rule {
+		rules[idx] = &Rule{Tag: rule.Tag}
+		if len(rule.BalancingTag) > 0 {
+			balancer, found := balancers[rule.BalancingTag]
+			if !found {
+				return nil, newError("balancer ", rule.BalancingTag, " is not found").AtError()
+			}
+			rules[idx].Balancer = balancer
+		}
+		cond, err := rule.buildCondition(geo)
+		if err != nil {
+			return nil, err
+		}
+		rules[idx].Condition = cond
+	}
+	return rules, nil
+}
+
+// Reload implements app.Reloadable. Routing decisions made before Reload are not affected. Balancers are replaced,
+// so that outbounds of new balancers are probed from scratch.
+func (r *Router) Reload(config interface{}) error {
+	c, ok := config.(*Config)
+	if !ok {
+		return newError("not a router config")
+	}
+	balancers, err := buildBalancers(c, r.ohm)
+	if err != nil {
+		return newError("failed to build balancers").Base(err)
+	}
+	rules, err := buildRules(c, balancers)
+	if err != nil {
+		return newError("failed to build routing rules").Base(err)
+	}
+
+	r.Lock()
+	r.domainStrategy = c.DomainStrategy
+	r.rules = rules
+	oldBalancers := r.balancers
+	r.balancers = balancers
+	r.Unlock()
+
+	for _, balancer := range oldBalancers {
+		balancer.Close()
+	}
+	for _, balancer := range balancers {
+		balancer.Start()
+	}
+
+	return nil
+}
+
+func (r *Router) resolveIP(ctx context.Context, dest net.Destination) []net.Address {
+	ips := resolver.LookupIP(ctx, r.dnsServer, dest.Address.Domain())
 	if len(ips) == 0 {
 		return nil
 	}
@@ -65,9 +151,14 @@ func (r *Router) resolveIP(dest net.Destination) []net.Address {
 }
 
 func (r *Router) TakeDetour(ctx context.Context) (string, error) {
-	for _, rule := range r.rules {
+	r.RLock()
+	domainStrategy := r.domainStrategy
+	rules := r.rules
+	r.RUnlock()
+
+	for _, rule := range rules {
 		if rule.Apply(ctx) {
-			return rule.Tag, nil
+			return rule.GetTag(), nil
 		}
 	}
 
@@ -76,14 +167,14 @@ func (r *Router) TakeDetour(ctx context.Context) (string, error) {
 		return "", ErrNoRuleApplicable
 	}
 
-	if r.domainStrategy == Config_IpIfNonMatch && dest.Address.Family().IsDomain() {
+	if domainStrategy == Config_IpIfNonMatch && dest.Address.Family().IsDomain() {
 		log.Trace(newError("looking up IP for ", dest))
-		ipDests := r.resolveIP(dest)
+		ipDests := r.resolveIP(ctx, dest)
 		if ipDests != nil {
 			ctx = proxy.ContextWithResolveIPs(ctx, ipDests)
-			for _, rule := range r.rules {
+			for _, rule := range rules {
 				if rule.Apply(ctx) {
-					return rule.Tag, nil
+					return rule.GetTag(), nil
 				}
 			}
 		}
@@ -92,15 +183,60 @@ func (r *Router) TakeDetour(ctx context.Context) (string, error) {
 	return "", ErrNoRuleApplicable
 }
 
+// RuleStat is the number of times a routing rule was matched.
+type RuleStat struct {
+	Tag  string
+	Hits uint64
+}
+
+// RuleStats returns the statistics of current routing rules, in the order of the rules.
+func (r *Router) RuleStats() []RuleStat {
+	r.RLock()
+	rules := r.rules
+	r.RUnlock()
+
+	stats := make([]RuleStat, len(rules))
+	for idx, rule := range rules {
+		stats[idx] = RuleStat{
+			Tag:  rule.Tag,
+			Hits: atomic.LoadUint64(&rule.hits),
+		}
+	}
+	return stats
+}
+
 func (*Router) Interface() interface{} {
 	return (*Router)(nil)
 }
 
-func (*Router) Start() error {
+// Dependencies implements app.HasDependencies.
+func (*Router) Dependencies() []interface{} {
+	return []interface{}{(*resolver.Server)(nil)}
+}
+
+// Balancer returns the balancer with the given tag, or nil if not found.
+func (r *Router) Balancer(tag string) *Balancer {
+	r.RLock()
+	defer r.RUnlock()
+	return r.balancers[tag]
+}
+
+func (r *Router) Start() error {
+	r.RLock()
+	defer r.RUnlock()
+	for _, balancer := range r.balancers {
+		balancer.Start()
+	}
 	return nil
 }
 
-func (*Router) Close() {}
+func (r *Router) Close() {
+	r.RLock()
+	defer r.RUnlock()
+	for _, balancer := range r.balancers {
+		balancer.Close()
+	}
+}
 
 func FromSpace(space app.Space) *Router {
 	app := space.GetApplication((*Router)(nil))
@@ -46,3 +46,74 @@ func TestSimpleRouter(t *testing.T) {
 	assert.Error(err).IsNil()
 	assert.String(tag).Equals("test")
 }
+
+func TestRouterReload(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(dns.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(dispatcher.Config))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(proxyman.OutboundConfig))).IsNil()
+	assert.Error(app.AddApplicationToSpace(ctx, new(Config))).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	r := FromSpace(space)
+
+	ctx = proxy.ContextWithTarget(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 80))
+	_, err := r.TakeDetour(ctx)
+	assert.Error(err).Equals(ErrNoRuleApplicable)
+
+	assert.Error(r.Reload(&Config{
+		Rule: []*RoutingRule{
+			{
+				Tag: "test",
+				NetworkList: &net.NetworkList{
+					Network: []net.Network{net.Network_TCP},
+				},
+			},
+		},
+	})).IsNil()
+
+	tag, err := r.TakeDetour(ctx)
+	assert.Error(err).IsNil()
+	assert.String(tag).Equals("test")
+}
+
+func TestRuleStats(t *testing.T) {
+	assert := assert.On(t)
+
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				Tag: "udp",
+				NetworkList: &net.NetworkList{
+					Network: []net.Network{net.Network_UDP},
+				},
+			},
+			{
+				Tag: "tcp",
+				NetworkList: &net.NetworkList{
+					Network: []net.Network{net.Network_TCP},
+				},
+			},
+		},
+	}
+	ctx, space := newRouterSpace(assert, config)
+	assert.Error(space.Initialize()).IsNil()
+	r := FromSpace(space)
+
+	// Rules after the first one are counted as well.
+	for i := 0; i < 3; i++ {
+		tag, err := r.TakeDetour(proxy.ContextWithTarget(ctx, net.TCPDestination(net.DomainAddress("v2ray.com"), 80)))
+		assert.Error(err).IsNil()
+		assert.String(tag).Equals("tcp")
+	}
+
+	stats := r.RuleStats()
+	assert.Int(len(stats)).Equals(2)
+	assert.String(stats[0].Tag).Equals("udp")
+	assert.Int64(int64(stats[0].Hits)).Equals(0)
+	assert.String(stats[1].Tag).Equals("tcp")
+	assert.Int64(int64(stats[1].Hits)).Equals(3)
+}
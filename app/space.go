@@ -13,6 +13,19 @@ type Application interface {
 	Close()
 }
 
+// Reloadable is an Application that is able to apply a new config while running.
+type Reloadable interface {
+	// Reload applies the given config, which is of the same type as the one the Application was created with.
+	Reload(config interface{}) error
+}
+
+// HasDependencies is an Application that requires other applications to be started before it.
+type HasDependencies interface {
+	// Dependencies returns the interfaces of the required applications, as returned by their Interface() method.
+	// Dependencies that are not available in the Space are ignored.
+	Dependencies() []interface{}
+}
+
 type InitializationCallback func() error
 
 func CreateAppFromConfig(ctx context.Context, config interface{}) (Application, error) {
@@ -42,6 +55,7 @@ type Space interface {
 type spaceImpl struct {
 	initialized bool
 	cache       map[reflect.Type]Application
+	appTypes    []reflect.Type
 	appInit     []InitializationCallback
 }
 
@@ -84,22 +98,96 @@ func (s *spaceImpl) AddApplication(app Application) error {
 		return newError("nil space").AtError()
 	}
 	appType := reflect.TypeOf(app.Interface())
+	if _, found := s.cache[appType]; !found {
+		s.appTypes = append(s.appTypes, appType)
+	}
 	s.cache[appType] = app
 	return nil
 }
 
+// sortApplications returns all apps in the order they should be started. An app is placed after all its dependencies.
+// Apps without dependency between each other are kept in the order they were added.
+func (s *spaceImpl) sortApplications() ([]reflect.Type, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[reflect.Type]int)
+	sorted := make([]reflect.Type, 0, len(s.appTypes))
+	var path []reflect.Type
+
+	var visit func(t reflect.Type) error
+	visit = func(t reflect.Type) error {
+		switch state[t] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := []interface{}{"dependency cycle: "}
+			for _, p := range path[indexOfType(path, t):] {
+				cycle = append(cycle, p, " -> ")
+			}
+			cycle = append(cycle, t)
+			return newError(cycle...).AtError()
+		}
+
+		state[t] = visiting
+		path = append(path, t)
+		if d, ok := s.cache[t].(HasDependencies); ok {
+			for _, dep := range d.Dependencies() {
+				depType := reflect.TypeOf(dep)
+				if _, found := s.cache[depType]; !found {
+					continue
+				}
+				if err := visit(depType); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[t] = visited
+		sorted = append(sorted, t)
+		return nil
+	}
+
+	for _, t := range s.appTypes {
+		if err := visit(t); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+func indexOfType(types []reflect.Type, t reflect.Type) int {
+	for idx, tt := range types {
+		if tt == t {
+			return idx
+		}
+	}
+	return -1
+}
+
+// Start starts all apps in the Space. Dependencies of an app are started before the app itself.
 func (s *spaceImpl) Start() error {
-	for _, app := range s.cache {
-		if err := app.Start(); err != nil {
-			return err
+	appTypes, err := s.sortApplications()
+	if err != nil {
+		return err
+	}
+	for _, t := range appTypes {
+		if err := s.cache[t].Start(); err != nil {
+			return newError("failed to start app ", t).Base(err)
 		}
 	}
 	return nil
 }
 
+// Close closes all apps in the Space, in the reverse order of starting.
 func (s *spaceImpl) Close() {
-	for _, app := range s.cache {
-		app.Close()
+	appTypes, err := s.sortApplications()
+	if err != nil {
+		appTypes = s.appTypes
+	}
+	for i := len(appTypes) - 1; i >= 0; i-- {
+		s.cache[appTypes[i]].Close()
 	}
 }
 
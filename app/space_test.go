@@ -0,0 +1,120 @@
+package app_test
+
+import (
+	"errors"
+	"testing"
+
+	. "v2ray.com/core/app"
+	"v2ray.com/core/testing/assert"
+)
+
+type (
+	interfaceA struct{}
+	interfaceB struct{}
+	interfaceC struct{}
+	interfaceD struct{}
+)
+
+type testApp struct {
+	name     string
+	iface    interface{}
+	deps     []interface{}
+	startErr error
+	events   *[]string
+}
+
+func (a *testApp) Interface() interface{} {
+	return a.iface
+}
+
+func (a *testApp) Dependencies() []interface{} {
+	return a.deps
+}
+
+func (a *testApp) Start() error {
+	if a.startErr != nil {
+		return a.startErr
+	}
+	*a.events = append(*a.events, "start "+a.name)
+	return nil
+}
+
+func (a *testApp) Close() {
+	*a.events = append(*a.events, "close "+a.name)
+}
+
+func TestSpaceDependencyOrder(t *testing.T) {
+	assert := assert.On(t)
+
+	var events []string
+	space := NewSpace()
+	assert.Error(space.AddApplication(&testApp{
+		name:   "a",
+		iface:  (*interfaceA)(nil),
+		deps:   []interface{}{(*interfaceB)(nil), (*interfaceD)(nil)},
+		events: &events,
+	})).IsNil()
+	assert.Error(space.AddApplication(&testApp{
+		name:   "c",
+		iface:  (*interfaceC)(nil),
+		events: &events,
+	})).IsNil()
+	assert.Error(space.AddApplication(&testApp{
+		name:   "b",
+		iface:  (*interfaceB)(nil),
+		deps:   []interface{}{(*interfaceC)(nil)},
+		events: &events,
+	})).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	assert.Error(space.Start()).IsNil()
+	space.Close()
+
+	expected := []string{"start c", "start b", "start a", "close a", "close b", "close c"}
+	assert.Int(len(events)).Equals(len(expected))
+	for i := range expected {
+		assert.String(events[i]).Equals(expected[i])
+	}
+}
+
+func TestSpaceDependencyCycle(t *testing.T) {
+	assert := assert.On(t)
+
+	var events []string
+	space := NewSpace()
+	assert.Error(space.AddApplication(&testApp{
+		name:   "a",
+		iface:  (*interfaceA)(nil),
+		deps:   []interface{}{(*interfaceB)(nil)},
+		events: &events,
+	})).IsNil()
+	assert.Error(space.AddApplication(&testApp{
+		name:   "b",
+		iface:  (*interfaceB)(nil),
+		deps:   []interface{}{(*interfaceA)(nil)},
+		events: &events,
+	})).IsNil()
+
+	err := space.Start()
+	assert.Error(err).IsNotNil()
+	assert.String(err.Error()).Contains("dependency cycle")
+	assert.Int(len(events)).Equals(0)
+}
+
+func TestSpaceStartFailure(t *testing.T) {
+	assert := assert.On(t)
+
+	var events []string
+	space := NewSpace()
+	assert.Error(space.AddApplication(&testApp{
+		name:     "a",
+		iface:    (*interfaceA)(nil),
+		startErr: errors.New("test error"),
+		events:   &events,
+	})).IsNil()
+
+	err := space.Start()
+	assert.Error(err).IsNotNil()
+	assert.String(err.Error()).Contains("interfaceA")
+	assert.String(err.Error()).Contains("test error")
+}
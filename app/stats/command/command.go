@@ -0,0 +1,96 @@
+package command
+
+//go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg command -path App,Stats,Command
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common"
+)
+
+type statsServer struct {
+	stats stats.Manager
+}
+
+func (s *statsServer) GetStats(ctx context.Context, request *GetStatsRequest) (*GetStatsResponse, error) {
+	c := s.stats.GetCounter(request.Name)
+	if c == nil {
+		return nil, newError(request.Name, " not found.")
+	}
+	var value int64
+	if request.Reset_ {
+		value = c.Set(0)
+	} else {
+		value = c.Value()
+	}
+	return &GetStatsResponse{
+		Stat: &Stat{
+			Name:  request.Name,
+			Value: value,
+		},
+	}, nil
+}
+
+func (s *statsServer) QueryStats(ctx context.Context, request *QueryStatsRequest) (*QueryStatsResponse, error) {
+	response := &QueryStatsResponse{}
+	s.stats.VisitCounters(func(name string, c stats.Counter) bool {
+		if !strings.Contains(name, request.Pattern) {
+			return true
+		}
+		var value int64
+		if request.Reset_ {
+			value = c.Set(0)
+		} else {
+			value = c.Value()
+		}
+		response.Stat = append(response.Stat, &Stat{
+			Name:  name,
+			Value: value,
+		})
+		return true
+	})
+	return response, nil
+}
+
+// Service exposes StatsService through the API server.
+type Service struct {
+	server *statsServer
+}
+
+// New creates a new Service.
+func New(ctx context.Context, config *Config) (*Service, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, newError("no space in context")
+	}
+
+	s := &Service{
+		server: &statsServer{},
+	}
+
+	space.OnInitialize(func() error {
+		s.server.stats = stats.FromSpace(space)
+		if s.server.stats == nil {
+			return newError("stats.Manager is not found in the space")
+		}
+		return nil
+	})
+
+	return s, nil
+}
+
+// Register implements api.Service.
+func (s *Service) Register(server *grpc.Server) {
+	RegisterStatsServiceServer(server, s.server)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
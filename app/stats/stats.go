@@ -0,0 +1,171 @@
+package stats
+
+//go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg stats -path App,Stats
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/common"
+)
+
+// Counter is a named counter of traffic in bytes.
+type Counter interface {
+	// Value returns the current value of the counter.
+	Value() int64
+	// Set sets a new value to the counter, and returns the previous value.
+	Set(int64) int64
+	// Add adds a delta to the counter, and returns the new value.
+	Add(int64) int64
+}
+
+// Manager manages all counters in a V2Ray instance.
+//
+// Traffic counters are named as follows:
+//
+//	user>>>[email]>>>traffic>>>uplink
+//	user>>>[email]>>>traffic>>>downlink
+//	inbound>>>[tag]>>>traffic>>>uplink
+//	inbound>>>[tag]>>>traffic>>>downlink
+//	outbound>>>[tag]>>>traffic>>>uplink
+//	outbound>>>[tag]>>>traffic>>>downlink
+type Manager interface {
+	// RegisterCounter creates a new counter with the given name. It returns an error if the name is taken.
+	RegisterCounter(string) (Counter, error)
+	// GetCounter returns the counter with the given name, or nil if it doesn't exist.
+	GetCounter(string) Counter
+	// VisitCounters calls the visitor on every counter, until the visitor returns false.
+	VisitCounters(func(string, Counter) bool)
+}
+
+// GetOrRegisterCounter returns the counter with the given name, or creates one if it doesn't exist.
+func GetOrRegisterCounter(m Manager, name string) (Counter, error) {
+	if c := m.GetCounter(name); c != nil {
+		return c, nil
+	}
+	c, err := m.RegisterCounter(name)
+	if err != nil {
+		if c := m.GetCounter(name); c != nil {
+			return c, nil
+		}
+	}
+	return c, err
+}
+
+// UserTrafficCounterName returns the name of the counter for the traffic of the given user.
+func UserTrafficCounterName(email string, uplink bool) string {
+	return trafficCounterName("user", email, uplink)
+}
+
+// InboundTrafficCounterName returns the name of the counter for the traffic of the given inbound handler.
+func InboundTrafficCounterName(tag string, uplink bool) string {
+	return trafficCounterName("inbound", tag, uplink)
+}
+
+// OutboundTrafficCounterName returns the name of the counter for the traffic of the given outbound handler.
+func OutboundTrafficCounterName(tag string, uplink bool) string {
+	return trafficCounterName("outbound", tag, uplink)
+}
+
+func trafficCounterName(category string, name string, uplink bool) string {
+	direction := "downlink"
+	if uplink {
+		direction = "uplink"
+	}
+	return category + ">>>" + name + ">>>traffic>>>" + direction
+}
+
+type counter struct {
+	value int64
+}
+
+func (c *counter) Value() int64 {
+	return atomic.LoadInt64(&c.value)
+}
+
+func (c *counter) Set(newValue int64) int64 {
+	return atomic.SwapInt64(&c.value, newValue)
+}
+
+func (c *counter) Add(delta int64) int64 {
+	return atomic.AddInt64(&c.value, delta)
+}
+
+// DefaultManager is the default implementation of Manager.
+type DefaultManager struct {
+	access   sync.RWMutex
+	counters map[string]*counter
+}
+
+// NewDefaultManager creates a new DefaultManager.
+func NewDefaultManager(ctx context.Context, config *Config) (*DefaultManager, error) {
+	return &DefaultManager{
+		counters: make(map[string]*counter),
+	}, nil
+}
+
+// Interface implements app.Application.
+func (*DefaultManager) Interface() interface{} {
+	return (*Manager)(nil)
+}
+
+// Start implements app.Application.
+func (*DefaultManager) Start() error {
+	return nil
+}
+
+// Close implements app.Application.
+func (*DefaultManager) Close() {}
+
+// RegisterCounter implements Manager.
+func (m *DefaultManager) RegisterCounter(name string) (Counter, error) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if _, found := m.counters[name]; found {
+		return nil, newError("counter ", name, " already registered")
+	}
+	c := new(counter)
+	m.counters[name] = c
+	return c, nil
+}
+
+// GetCounter implements Manager.
+func (m *DefaultManager) GetCounter(name string) Counter {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	if c, found := m.counters[name]; found {
+		return c
+	}
+	return nil
+}
+
+// VisitCounters implements Manager.
+func (m *DefaultManager) VisitCounters(visitor func(string, Counter) bool) {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	for name, c := range m.counters {
+		if !visitor(name, c) {
+			break
+		}
+	}
+}
+
+// FromSpace returns the stats Manager in the given space, or nil if not present.
+func FromSpace(space app.Space) Manager {
+	a := space.GetApplication((*Manager)(nil))
+	if a == nil {
+		return nil
+	}
+	return a.(Manager)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewDefaultManager(ctx, config.(*Config))
+	}))
+}
@@ -0,0 +1,40 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app"
+	. "v2ray.com/core/app/stats"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestStatsCounter(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	assert.Error(app.AddApplicationToSpace(ctx, new(Config))).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	m := FromSpace(space)
+	assert.Bool(m != nil).IsTrue()
+
+	name := UserTrafficCounterName("test@v2ray.com", true)
+	assert.String(name).Equals("user>>>test@v2ray.com>>>traffic>>>uplink")
+
+	c, err := m.RegisterCounter(name)
+	assert.Error(err).IsNil()
+	_, err = m.RegisterCounter(name)
+	assert.Error(err).IsNotNil()
+
+	c2, err := GetOrRegisterCounter(m, name)
+	assert.Error(err).IsNil()
+	assert.Bool(c == c2).IsTrue()
+
+	assert.Int64(c.Add(1)).Equals(1)
+	assert.Int64(c.Add(2)).Equals(3)
+	assert.Int64(c.Set(0)).Equals(3)
+	assert.Int64(m.GetCounter(name).Value()).Equals(0)
+	assert.Bool(m.GetCounter("nonexist") == nil).IsTrue()
+}
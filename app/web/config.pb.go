@@ -3,7 +3,9 @@ package web
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import v2ray_core_common_net "v2ray.com/core/common/net"
 import v2ray_core_common_serial "v2ray.com/core/common/serial"
+import v2ray_core_transport_internet_tls "v2ray.com/core/transport/internet/tls"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -153,6 +155,25 @@ func _FileServer_Entry_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+// Metrics serves runtime metrics of V2Ray in Prometheus text format. Traffic of inbounds, outbounds and users is
+// reported only if the stats app is configured.
+type Metrics struct {
+	// URL path of the metrics. Default to "/metrics".
+	Path string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+}
+
+func (m *Metrics) Reset()                    { *m = Metrics{} }
+func (m *Metrics) String() string            { return proto.CompactTextString(m) }
+func (*Metrics) ProtoMessage()               {}
+func (*Metrics) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Metrics) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
 type Server struct {
 	Domain   []string                               `protobuf:"bytes,1,rep,name=domain" json:"domain,omitempty"`
 	Settings *v2ray_core_common_serial.TypedMessage `protobuf:"bytes,2,opt,name=settings" json:"settings,omitempty"`
@@ -161,7 +182,7 @@ type Server struct {
 func (m *Server) Reset()                    { *m = Server{} }
 func (m *Server) String() string            { return proto.CompactTextString(m) }
 func (*Server) ProtoMessage()               {}
-func (*Server) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*Server) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 func (m *Server) GetDomain() []string {
 	if m != nil {
@@ -179,12 +200,17 @@ func (m *Server) GetSettings() *v2ray_core_common_serial.TypedMessage {
 
 type Config struct {
 	Server []*Server `protobuf:"bytes,1,rep,name=server" json:"server,omitempty"`
+	// Address to listen on. Default to 127.0.0.1.
+	Listen *v2ray_core_common_net.IPOrDomain `protobuf:"bytes,2,opt,name=listen" json:"listen,omitempty"`
+	Port   uint32                            `protobuf:"varint,3,opt,name=port" json:"port,omitempty"`
+	// TLS settings of the web server. Content is served in plain HTTP if not set.
+	Tls *v2ray_core_transport_internet_tls.Config `protobuf:"bytes,4,opt,name=tls" json:"tls,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
 func (m *Config) String() string            { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()               {}
-func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*Config) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *Config) GetServer() []*Server {
 	if m != nil {
@@ -193,9 +219,31 @@ func (m *Config) GetServer() []*Server {
 	return nil
 }
 
+func (m *Config) GetListen() *v2ray_core_common_net.IPOrDomain {
+	if m != nil {
+		return m.Listen
+	}
+	return nil
+}
+
+func (m *Config) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *Config) GetTls() *v2ray_core_transport_internet_tls.Config {
+	if m != nil {
+		return m.Tls
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*FileServer)(nil), "v2ray.core.app.web.FileServer")
 	proto.RegisterType((*FileServer_Entry)(nil), "v2ray.core.app.web.FileServer.Entry")
+	proto.RegisterType((*Metrics)(nil), "v2ray.core.app.web.Metrics")
 	proto.RegisterType((*Server)(nil), "v2ray.core.app.web.Server")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.web.Config")
 }
@@ -203,26 +251,33 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/app/web/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 324 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcf, 0x4a, 0xc3, 0x40,
-	0x10, 0x87, 0x4d, 0xff, 0x04, 0x33, 0xbd, 0x2d, 0x52, 0x42, 0x0f, 0x52, 0xaa, 0x48, 0x4f, 0x1b,
-	0x89, 0x9e, 0xc4, 0x8b, 0x6d, 0x15, 0x2f, 0xa2, 0xac, 0xa2, 0xe0, 0x41, 0xd9, 0xa4, 0x63, 0x5d,
-	0x68, 0xb2, 0xcb, 0x64, 0x69, 0xc9, 0x1b, 0x89, 0x4f, 0x29, 0xd9, 0x44, 0x2b, 0xda, 0x5b, 0x26,
-	0xf3, 0xfd, 0x76, 0x66, 0xbf, 0x85, 0x83, 0x55, 0x4c, 0xb2, 0xe4, 0xa9, 0xce, 0xa2, 0x54, 0x13,
-	0x46, 0xd2, 0x98, 0x68, 0x8d, 0x49, 0x94, 0xea, 0xfc, 0x4d, 0x2d, 0xb8, 0x21, 0x6d, 0x35, 0x63,
-	0xdf, 0x10, 0x21, 0x97, 0xc6, 0xf0, 0x35, 0x26, 0x83, 0xe3, 0x3f, 0xc1, 0x54, 0x67, 0x99, 0xce,
-	0xa3, 0x02, 0x49, 0xc9, 0x65, 0x64, 0x4b, 0x83, 0xf3, 0xd7, 0x0c, 0x8b, 0x42, 0x2e, 0xb0, 0x3e,
-	0x65, 0xf4, 0xe1, 0x01, 0x5c, 0xa9, 0x25, 0xde, 0x23, 0xad, 0x90, 0xd8, 0x19, 0x74, 0x31, 0xb7,
-	0x54, 0x86, 0xde, 0xb0, 0x3d, 0xee, 0xc5, 0x87, 0xfc, 0xff, 0x10, 0xbe, 0xc1, 0xf9, 0x65, 0xc5,
-	0x8a, 0x3a, 0x32, 0x78, 0x81, 0xae, 0xab, 0xd9, 0x1e, 0x74, 0x2a, 0x26, 0xf4, 0x86, 0xde, 0x38,
-	0xb8, 0xde, 0x11, 0xae, 0x62, 0xfb, 0x10, 0xcc, 0x14, 0x61, 0x6a, 0x35, 0x95, 0x61, 0xab, 0x69,
-	0x6d, 0x7e, 0x31, 0x06, 0x1d, 0x23, 0xed, 0x7b, 0xd8, 0xae, 0x5a, 0xc2, 0x7d, 0x4f, 0x7a, 0x10,
-	0x54, 0xd9, 0x5b, 0x9a, 0x29, 0x1a, 0xcd, 0xc1, 0x6f, 0xb6, 0xec, 0x83, 0x3f, 0xd7, 0x99, 0x54,
-	0xb9, 0x5b, 0x33, 0x10, 0x4d, 0xc5, 0x26, 0xb0, 0x5b, 0xa0, 0xb5, 0x2a, 0x5f, 0x14, 0x6e, 0x42,
-	0x2f, 0x3e, 0xfa, 0x7d, 0x81, 0xda, 0x06, 0xaf, 0x6d, 0xf0, 0x87, 0xca, 0xc6, 0x4d, 0x2d, 0x43,
-	0xfc, 0xe4, 0x46, 0xe7, 0xe0, 0x4f, 0x9d, 0x66, 0x16, 0x83, 0x5f, 0xb8, 0x79, 0x8d, 0x8c, 0xc1,
-	0x36, 0x19, 0xf5, 0x46, 0xa2, 0x21, 0x27, 0xa7, 0xd0, 0x4f, 0x75, 0xb6, 0x05, 0xbc, 0xf3, 0x9e,
-	0xdb, 0x6b, 0x4c, 0x3e, 0x5b, 0xec, 0x31, 0x16, 0xb2, 0xe4, 0xd3, 0xaa, 0x77, 0x61, 0x0c, 0x7f,
-	0xc2, 0x24, 0xf1, 0xdd, 0x5b, 0x9c, 0x7c, 0x05, 0x00, 0x00, 0xff, 0xff, 0xf9, 0x2a, 0xe1, 0x59,
-	0xf8, 0x01, 0x00, 0x00,
+	// 433 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x51, 0x6f, 0xd3, 0x3e,
+	0x14, 0xc5, 0xff, 0x59, 0xbb, 0xfc, 0xe9, 0xad, 0x78, 0xb1, 0xd0, 0x54, 0x55, 0x02, 0x8d, 0x82,
+	0x60, 0xbc, 0x38, 0x28, 0xf0, 0x02, 0x3c, 0xd1, 0x15, 0x04, 0x0f, 0xd3, 0x26, 0x83, 0x40, 0xe2,
+	0x01, 0xe4, 0x24, 0x97, 0x62, 0x29, 0xb1, 0xad, 0xeb, 0xab, 0x55, 0xf9, 0x46, 0x88, 0x4f, 0xc2,
+	0xc7, 0x42, 0x71, 0xb2, 0x76, 0x8c, 0xbe, 0xd9, 0xb9, 0xe7, 0x67, 0x9f, 0x73, 0x1c, 0x78, 0x70,
+	0x99, 0x93, 0x6e, 0x65, 0xe9, 0x9a, 0xac, 0x74, 0x84, 0x99, 0xf6, 0x3e, 0xdb, 0x60, 0x91, 0x95,
+	0xce, 0x7e, 0x37, 0x6b, 0xe9, 0xc9, 0xb1, 0x13, 0xe2, 0x4a, 0x44, 0x28, 0xb5, 0xf7, 0x72, 0x83,
+	0xc5, 0xfc, 0xf1, 0x0d, 0xb0, 0x74, 0x4d, 0xe3, 0x6c, 0x66, 0x91, 0x33, 0x5d, 0x55, 0x84, 0x21,
+	0xf4, 0xf0, 0xfc, 0xe9, 0x7e, 0x61, 0x40, 0x32, 0xba, 0xce, 0xb8, 0xf5, 0x58, 0x7d, 0x6b, 0x30,
+	0x04, 0xbd, 0xc6, 0x81, 0xc8, 0x6f, 0x10, 0x4c, 0xda, 0x06, 0xef, 0x88, 0x33, 0x63, 0x19, 0xa9,
+	0xbb, 0x82, 0xeb, 0xf0, 0x97, 0xc5, 0xc5, 0xcf, 0x04, 0xe0, 0xad, 0xa9, 0xf1, 0x03, 0xd2, 0x25,
+	0x92, 0x78, 0x09, 0x87, 0x68, 0x99, 0xda, 0x59, 0x72, 0x3c, 0x3a, 0x99, 0xe6, 0x0f, 0xe5, 0xbf,
+	0x09, 0xe4, 0x4e, 0x2e, 0xdf, 0x74, 0x5a, 0xd5, 0x23, 0xf3, 0xaf, 0x70, 0x18, 0xf7, 0xe2, 0x0e,
+	0x8c, 0x3b, 0xcd, 0x2c, 0x39, 0x4e, 0x4e, 0x26, 0xef, 0xfe, 0x53, 0x71, 0x27, 0xee, 0xc1, 0x64,
+	0x65, 0x08, 0x4b, 0x76, 0xd4, 0xce, 0x0e, 0x86, 0xd1, 0xee, 0x93, 0x10, 0x30, 0xf6, 0x9a, 0x7f,
+	0xcc, 0x46, 0xdd, 0x48, 0xc5, 0xf5, 0x72, 0x0a, 0x93, 0x8e, 0x3d, 0xa7, 0x95, 0xa1, 0xc5, 0x5d,
+	0xf8, 0xff, 0x0c, 0x99, 0x4c, 0x19, 0xb6, 0xda, 0x64, 0xa7, 0x5d, 0x54, 0x90, 0x0e, 0x21, 0x8e,
+	0x20, 0xad, 0x5c, 0xa3, 0x8d, 0x8d, 0x29, 0x26, 0x6a, 0xd8, 0x89, 0x25, 0xdc, 0x0a, 0xc8, 0x6c,
+	0xec, 0x3a, 0x44, 0x03, 0xd3, 0xfc, 0xd1, 0xf5, 0x7c, 0x7d, 0xc1, 0xb2, 0x2f, 0x58, 0x7e, 0xec,
+	0x0a, 0x3e, 0xeb, 0xfb, 0x55, 0x5b, 0x6e, 0xf1, 0x3b, 0x81, 0xf4, 0x34, 0x16, 0x28, 0x72, 0x48,
+	0x43, 0xbc, 0x70, 0x28, 0x6b, 0xbe, 0xaf, 0xac, 0xde, 0x92, 0x1a, 0x94, 0xe2, 0x05, 0xa4, 0xb5,
+	0x09, 0x8c, 0x76, 0x30, 0x70, 0x7f, 0x8f, 0x01, 0x8b, 0x2c, 0xdf, 0x5f, 0x9c, 0xd3, 0x2a, 0xba,
+	0x56, 0x03, 0x10, 0x33, 0x3b, 0xe2, 0xd8, 0xcf, 0x6d, 0x15, 0xd7, 0xe2, 0x15, 0x8c, 0xb8, 0x0e,
+	0xb3, 0x71, 0x3c, 0xeb, 0xc9, 0xf5, 0xb3, 0xb6, 0x6f, 0x2f, 0xaf, 0xde, 0x5e, 0x72, 0x1d, 0x64,
+	0x6f, 0x5d, 0x75, 0xd4, 0xf2, 0x39, 0x1c, 0x95, 0xae, 0xd9, 0x63, 0xfa, 0x22, 0xf9, 0x32, 0xda,
+	0x60, 0xf1, 0xeb, 0x40, 0x7c, 0xca, 0x95, 0x6e, 0xe5, 0x69, 0x37, 0x7b, 0xed, 0xbd, 0xfc, 0x8c,
+	0x45, 0x91, 0xc6, 0xff, 0xe6, 0xd9, 0x9f, 0x01, 0x00, 0xfe, 0x5e, 0xc6, 0xef, 0x01, 0x03, 0x00,
+	0x00,
 }
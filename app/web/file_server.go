@@ -1 +1,112 @@
 package web
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"v2ray.com/core/common"
+)
+
+// FileHandler is a Handler that serves static files.
+type FileHandler struct {
+	mux   *http.ServeMux
+	paths []string
+}
+
+// NewFileHandler creates a new FileHandler with the given config.
+func NewFileHandler(ctx context.Context, config *FileServer) (*FileHandler, error) {
+	h := &FileHandler{
+		mux: http.NewServeMux(),
+	}
+	registered := make(map[string]bool)
+	for _, entry := range config.Entry {
+		path := entry.Path
+		if len(path) == 0 {
+			path = "/"
+		}
+		if !strings.HasPrefix(path, "/") {
+			return nil, newError("invalid path: ", path)
+		}
+
+		var handler http.Handler
+		switch {
+		case len(entry.GetDirectory()) > 0:
+			if !strings.HasSuffix(path, "/") {
+				path += "/"
+			}
+			handler = http.StripPrefix(path, http.FileServer(noListingFileSystem{http.Dir(entry.GetDirectory())}))
+		case len(entry.GetFile()) > 0:
+			handler = singleFileHandler{path: path, file: entry.GetFile()}
+		default:
+			return nil, newError("neither file nor directory is specified for path ", path)
+		}
+
+		if registered[path] {
+			return nil, newError("duplicated path: ", path)
+		}
+		registered[path] = true
+		h.mux.Handle(path, handler)
+		h.paths = append(h.paths, path)
+	}
+	return h, nil
+}
+
+// Paths implements Handler.
+func (h *FileHandler) Paths() []string {
+	return h.paths
+}
+
+// ServeHTTP implements http.Handler.
+func (h *FileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// noListingFileSystem is a http.FileSystem that hides directories without index.html, so that their contents are not
+// listed.
+type noListingFileSystem struct {
+	fs http.FileSystem
+}
+
+func (fs noListingFileSystem) Open(name string) (http.File, error) {
+	f, err := fs.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		index, err := fs.fs.Open(path.Join(name, "index.html"))
+		if err != nil {
+			f.Close()
+			return nil, os.ErrNotExist
+		}
+		index.Close()
+	}
+	return f, nil
+}
+
+// singleFileHandler serves a file on exactly one URL path.
+type singleFileHandler struct {
+	path string
+	file string
+}
+
+func (h singleFileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != h.path {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, h.file)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*FileServer)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewFileHandler(ctx, config.(*FileServer))
+	}))
+}
@@ -0,0 +1,90 @@
+package web_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "v2ray.com/core/app/web"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestFileHandler(t *testing.T) {
+	assert := assert.On(t)
+
+	dir, err := ioutil.TempDir("", "v2ray-web")
+	assert.Error(err).IsNil()
+	defer os.RemoveAll(dir)
+
+	assert.Error(ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("index"), 0644)).IsNil()
+	assert.Error(ioutil.WriteFile(filepath.Join(dir, "robots.txt"), []byte("robots"), 0644)).IsNil()
+	assert.Error(os.Mkdir(filepath.Join(dir, "assets"), 0755)).IsNil()
+	assert.Error(ioutil.WriteFile(filepath.Join(dir, "assets", "app.js"), []byte("app"), 0644)).IsNil()
+
+	handler, err := NewFileHandler(context.Background(), &FileServer{
+		Entry: []*FileServer_Entry{
+			{
+				FileOrDir: &FileServer_Entry_Directory{Directory: dir},
+				Path:      "/static",
+			},
+			{
+				FileOrDir: &FileServer_Entry_File{File: filepath.Join(dir, "robots.txt")},
+				Path:      "/robots.txt",
+			},
+		},
+	})
+	assert.Error(err).IsNil()
+	assert.Int(len(handler.Paths())).Equals(2)
+	assert.String(handler.Paths()[0]).Equals("/static/")
+
+	get := func(path string) (int, string) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		return recorder.Code, recorder.Body.String()
+	}
+
+	code, body := get("/static/")
+	assert.Int(code).Equals(http.StatusOK)
+	assert.String(body).Equals("index")
+
+	code, body = get("/robots.txt")
+	assert.Int(code).Equals(http.StatusOK)
+	assert.String(body).Equals("robots")
+
+	code, _ = get("/static/nonexist.html")
+	assert.Int(code).Equals(http.StatusNotFound)
+
+	// Directories without index.html are not listed.
+	code, body = get("/static/assets/app.js")
+	assert.Int(code).Equals(http.StatusOK)
+	assert.String(body).Equals("app")
+	code, _ = get("/static/assets/")
+	assert.Int(code).Equals(http.StatusNotFound)
+	code, _ = get("/static/assets")
+	assert.Int(code).Equals(http.StatusNotFound)
+
+	code, _ = get("/other")
+	assert.Int(code).Equals(http.StatusNotFound)
+}
+
+func TestFileHandlerDuplicatedPath(t *testing.T) {
+	assert := assert.On(t)
+
+	_, err := NewFileHandler(context.Background(), &FileServer{
+		Entry: []*FileServer_Entry{
+			{
+				FileOrDir: &FileServer_Entry_File{File: "a"},
+				Path:      "/a",
+			},
+			{
+				FileOrDir: &FileServer_Entry_File{File: "b"},
+				Path:      "/a",
+			},
+		},
+	})
+	assert.Error(err).IsNotNil()
+}
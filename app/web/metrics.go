@@ -0,0 +1,247 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dns/resolver"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/proxyman/mux"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+)
+
+type muxOutboundHandler interface {
+	MuxClientManager() *mux.ClientManager
+}
+
+// MetricsHandler is a Handler that reports runtime metrics in Prometheus text format. Traffic of inbounds, outbounds
+// and users is counted by the stats app, so it is reported only if the stats app is configured. DNS cache metrics
+// are reported if the DNS server implements resolver.CachedServer.
+type MetricsHandler struct {
+	path   string
+	ctx    context.Context
+	ihm    proxyman.InboundHandlerManager
+	ohm    proxyman.OutboundHandlerManager
+	stats  stats.Manager
+	dns    resolver.Server
+	router *router.Router
+}
+
+// NewMetricsHandler creates a new MetricsHandler with the given config.
+func NewMetricsHandler(ctx context.Context, config *Metrics) (*MetricsHandler, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, newError("no space in context")
+	}
+	h := &MetricsHandler{
+		path: config.Path,
+		ctx:  ctx,
+	}
+	if len(h.path) == 0 {
+		h.path = "/metrics"
+	}
+	space.OnInitialize(func() error {
+		h.ihm = proxyman.InboundHandlerManagerFromSpace(space)
+		h.ohm = proxyman.OutboundHandlerManagerFromSpace(space)
+		h.stats = stats.FromSpace(space)
+		h.dns = resolver.FromSpace(space)
+		h.router = router.FromSpace(space)
+		return nil
+	})
+	return h, nil
+}
+
+// Paths implements Handler.
+func (h *MetricsHandler) Paths() []string {
+	return []string{h.path}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != h.path {
+		http.NotFound(w, r)
+		return
+	}
+
+	m := new(metricsWriter)
+	h.writeInbounds(m)
+	h.writeOutbounds(m)
+	h.writeTraffic(m)
+	h.writeDNS(m)
+	h.writeRouter(m)
+	writeRuntime(m)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(m.Bytes())
+}
+
+func (h *MetricsHandler) writeInbounds(m *metricsWriter) {
+	if h.ihm == nil {
+		return
+	}
+	m.family("v2ray_inbound_active_connections", "gauge", "Number of connections being processed by inbound handlers.")
+	for _, handler := range h.ihm.ListHandlers(h.ctx) {
+		m.sample("v2ray_inbound_active_connections", float64(handler.ActiveConnections()), "tag", handler.Tag())
+	}
+}
+
+func (h *MetricsHandler) writeOutbounds(m *metricsWriter) {
+	if h.ohm == nil {
+		return
+	}
+	type muxStat struct {
+		tag               string
+		clients, sessions int
+	}
+	var muxStats []muxStat
+	for _, handler := range h.ohm.ListHandlers() {
+		mh, ok := handler.(muxOutboundHandler)
+		if !ok || mh.MuxClientManager() == nil {
+			continue
+		}
+		clients, sessions := mh.MuxClientManager().Stats()
+		muxStats = append(muxStats, muxStat{tag: handler.Tag(), clients: clients, sessions: sessions})
+	}
+
+	m.family("v2ray_mux_clients", "gauge", "Number of active mux client connections.")
+	for _, s := range muxStats {
+		m.sample("v2ray_mux_clients", float64(s.clients), "tag", s.tag)
+	}
+	m.family("v2ray_mux_sessions", "gauge", "Number of sessions running over mux client connections.")
+	for _, s := range muxStats {
+		m.sample("v2ray_mux_sessions", float64(s.sessions), "tag", s.tag)
+	}
+}
+
+func (h *MetricsHandler) writeTraffic(m *metricsWriter) {
+	if h.stats == nil {
+		return
+	}
+	type traffic struct {
+		name, direction string
+		value           int64
+	}
+	categories := map[string][]traffic{}
+	h.stats.VisitCounters(func(name string, c stats.Counter) bool {
+		// Counter names are in the form of "category>>>name>>>traffic>>>direction".
+		parts := strings.Split(name, ">>>")
+		if len(parts) == 4 && parts[2] == "traffic" {
+			categories[parts[0]] = append(categories[parts[0]], traffic{name: parts[1], direction: parts[3], value: c.Value()})
+		}
+		return true
+	})
+
+	for _, category := range []struct{ name, label string }{
+		{"inbound", "tag"},
+		{"outbound", "tag"},
+		{"user", "email"},
+	} {
+		metric := "v2ray_" + category.name + "_traffic_bytes_total"
+		m.family(metric, "counter", "Bytes transferred through "+category.name+".")
+		for _, t := range categories[category.name] {
+			m.sample(metric, float64(t.value), category.label, t.name, "direction", t.direction)
+		}
+	}
+}
+
+func (h *MetricsHandler) writeDNS(m *metricsWriter) {
+	cache, ok := h.dns.(resolver.CachedServer)
+	if !ok {
+		return
+	}
+	s := cache.CacheStats()
+	m.family("v2ray_dns_cache_size", "gauge", "Number of domains in DNS cache.")
+	m.sample("v2ray_dns_cache_size", float64(s.Size))
+	m.family("v2ray_dns_cache_hits_total", "counter", "Number of DNS queries answered by cache.")
+	m.sample("v2ray_dns_cache_hits_total", float64(s.Hits))
+	m.family("v2ray_dns_cache_misses_total", "counter", "Number of DNS queries sent to name servers.")
+	m.sample("v2ray_dns_cache_misses_total", float64(s.Misses))
+
+	ratio := 0.0
+	if total := s.Hits + s.Misses; total > 0 {
+		ratio = float64(s.Hits) / float64(total)
+	}
+	m.family("v2ray_dns_cache_hit_ratio", "gauge", "Ratio of DNS queries answered by cache.")
+	m.sample("v2ray_dns_cache_hit_ratio", ratio)
+}
+
+func (h *MetricsHandler) writeRouter(m *metricsWriter) {
+	if h.router == nil {
+		return
+	}
+	m.family("v2ray_router_rule_hits_total", "counter", "Number of times a routing rule was matched.")
+	for idx, s := range h.router.RuleStats() {
+		m.sample("v2ray_router_rule_hits_total", float64(s.Hits), "rule", strconv.Itoa(idx), "tag", s.Tag)
+	}
+}
+
+func writeRuntime(m *metricsWriter) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	m.family("go_goroutines", "gauge", "Number of goroutines that currently exist.")
+	m.sample("go_goroutines", float64(runtime.NumGoroutine()))
+	m.family("go_memstats_alloc_bytes", "gauge", "Number of bytes allocated and still in use.")
+	m.sample("go_memstats_alloc_bytes", float64(mem.Alloc))
+	m.family("go_memstats_sys_bytes", "gauge", "Number of bytes obtained from system.")
+	m.sample("go_memstats_sys_bytes", float64(mem.Sys))
+	m.family("go_memstats_heap_objects", "gauge", "Number of allocated objects.")
+	m.sample("go_memstats_heap_objects", float64(mem.HeapObjects))
+	m.family("go_gc_cycles_total", "counter", "Number of completed GC cycles.")
+	m.sample("go_gc_cycles_total", float64(mem.NumGC))
+	m.family("go_gc_pause_seconds_total", "counter", "Total time spent in GC stop-the-world pauses.")
+	m.sample("go_gc_pause_seconds_total", float64(mem.PauseTotalNs)/1e9)
+
+	pool := buf.GetPoolStats()
+	m.family("v2ray_buffer_pool_cached", "gauge", "Number of buffers ready for use in the buffer pool.")
+	m.sample("v2ray_buffer_pool_cached", float64(pool.Cached))
+	m.family("v2ray_buffer_pool_capacity", "gauge", "Maximum number of buffers the buffer pool may cache.")
+	m.sample("v2ray_buffer_pool_capacity", float64(pool.Capacity))
+}
+
+// metricsWriter writes metrics in Prometheus text format.
+type metricsWriter struct {
+	bytes.Buffer
+}
+
+func (m *metricsWriter) family(name string, typ string, help string) {
+	m.WriteString("# HELP " + name + " " + help + "\n")
+	m.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// sample writes a sample of a metric, with labels given as name-value pairs.
+func (m *metricsWriter) sample(name string, value float64, labels ...string) {
+	m.WriteString(name)
+	if len(labels) > 0 {
+		m.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				m.WriteByte(',')
+			}
+			m.WriteString(labels[i])
+			m.WriteString(`="`)
+			m.WriteString(labelReplacer.Replace(labels[i+1]))
+			m.WriteByte('"')
+		}
+		m.WriteByte('}')
+	}
+	m.WriteByte(' ')
+	m.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	m.WriteByte('\n')
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func init() {
+	common.Must(common.RegisterConfig((*Metrics)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewMetricsHandler(ctx, config.(*Metrics))
+	}))
+}
@@ -0,0 +1,86 @@
+package web_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/app/stats"
+	. "v2ray.com/core/app/web"
+	"v2ray.com/core/common/dice"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	_ "v2ray.com/core/main/distro/all"
+	"v2ray.com/core/proxy/dokodemo"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	assert := assert.On(t)
+
+	webPort := v2net.Port(dice.Roll(20000) + 10000)
+	inboundPort := webPort + 1
+
+	server, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&Config{
+				Port: uint32(webPort),
+				Server: []*Server{{
+					Settings: serial.ToTypedMessage(&Metrics{}),
+				}},
+			}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{{
+					Tag:       "direct",
+					PortRange: v2net.SinglePortRange(80),
+				}},
+			}),
+		},
+		Inbound: []*proxyman.InboundHandlerConfig{{
+			Tag: "in",
+			ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+				PortRange: v2net.SinglePortRange(inboundPort),
+				Listen:    v2net.NewIPOrDomain(v2net.LocalHostIP),
+			}),
+			ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+				Address: v2net.NewIPOrDomain(v2net.LocalHostIP),
+				Port:    80,
+				NetworkList: &v2net.NetworkList{
+					Network: []v2net.Network{v2net.Network_TCP},
+				},
+			}),
+		}},
+		Outbound: []*proxyman.OutboundHandlerConfig{{
+			Tag:           "direct",
+			ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+		}},
+	})
+	assert.Error(err).IsNil()
+	assert.Error(server.Start()).IsNil()
+	defer server.Close()
+
+	resp, err := http.Get("http://127.0.0.1:" + webPort.String() + "/metrics")
+	assert.Error(err).IsNil()
+	defer resp.Body.Close()
+	assert.Int(resp.StatusCode).Equals(http.StatusOK)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Error(err).IsNil()
+	content := string(body)
+	assert.String(content).Contains("# TYPE v2ray_inbound_active_connections gauge\n")
+	assert.String(content).Contains("v2ray_inbound_active_connections{tag=\"in\"} 0\n")
+	assert.String(content).Contains("v2ray_router_rule_hits_total{rule=\"0\",tag=\"direct\"} 0\n")
+	assert.String(content).Contains("v2ray_dns_cache_size 0\n")
+	assert.String(content).Contains("go_goroutines ")
+	assert.String(content).Contains("v2ray_buffer_pool_capacity ")
+
+	resp, err = http.Get("http://127.0.0.1:" + webPort.String() + "/other")
+	assert.Error(err).IsNil()
+	resp.Body.Close()
+	assert.Int(resp.StatusCode).Equals(http.StatusNotFound)
+}
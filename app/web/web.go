@@ -1,5 +1,185 @@
 package web
 
+//go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg web -path App,Web
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/common"
+	v2net "v2ray.com/core/common/net"
+)
+
 type WebServer interface {
 	Handle()
 }
+
+// Handler is created from the settings of a Server, and serves requests on some URL paths.
+type Handler interface {
+	http.Handler
+	// Paths returns the URL patterns to be handled, in the format of http.ServeMux.
+	Paths() []string
+}
+
+// Instance is an application that serves HTTP requests.
+type Instance struct {
+	sync.Mutex
+	config        *Config
+	hosts         map[string]*http.ServeMux
+	defaultServer *http.ServeMux
+	server        *http.Server
+}
+
+// New creates a new web Instance with the given config.
+func New(ctx context.Context, config *Config) (*Instance, error) {
+	if config.Port == 0 {
+		return nil, newError("web server port is not specified")
+	}
+
+	s := &Instance{
+		config: config,
+		hosts:  make(map[string]*http.ServeMux),
+	}
+
+	patterns := make(map[*http.ServeMux]map[string]bool)
+	for _, server := range config.Server {
+		if server.Settings == nil {
+			return nil, newError("no settings for web server on domain ", server.Domain)
+		}
+		settings, err := server.Settings.GetInstance()
+		if err != nil {
+			return nil, err
+		}
+		rawHandler, err := common.CreateObject(ctx, settings)
+		if err != nil {
+			return nil, newError("failed to create web handler").Base(err)
+		}
+		handler, ok := rawHandler.(Handler)
+		if !ok {
+			return nil, newError("not a web Handler: ", server.Settings.Type)
+		}
+
+		muxes := make([]*http.ServeMux, 0, len(server.Domain))
+		if len(server.Domain) == 0 {
+			if s.defaultServer == nil {
+				s.defaultServer = http.NewServeMux()
+			}
+			muxes = append(muxes, s.defaultServer)
+		}
+		for _, domain := range server.Domain {
+			domain = strings.ToLower(domain)
+			mux, found := s.hosts[domain]
+			if !found {
+				mux = http.NewServeMux()
+				s.hosts[domain] = mux
+			}
+			muxes = append(muxes, mux)
+		}
+
+		for _, mux := range muxes {
+			for _, path := range handler.Paths() {
+				if patterns[mux][path] {
+					return nil, newError("duplicated web path: ", path)
+				}
+				if patterns[mux] == nil {
+					patterns[mux] = make(map[string]bool)
+				}
+				patterns[mux][path] = true
+				mux.Handle(path, handler)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler. Requests are served by the handlers of the Server matching the request host.
+func (s *Instance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if mux, found := s.hosts[strings.ToLower(host)]; found {
+		mux.ServeHTTP(w, r)
+		return
+	}
+	if s.defaultServer != nil {
+		s.defaultServer.ServeHTTP(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// Interface implements app.Application.
+func (*Instance) Interface() interface{} {
+	return (*Instance)(nil)
+}
+
+// Start implements app.Application.
+func (s *Instance) Start() error {
+	address := v2net.LocalHostIP
+	if s.config.Listen != nil {
+		address = s.config.Listen.AsAddress()
+	}
+
+	listener, err := net.Listen("tcp", v2net.TCPDestination(address, v2net.Port(s.config.Port)).NetAddr())
+	if err != nil {
+		return newError("failed to listen on ", address, ":", s.config.Port).Base(err)
+	}
+	if s.config.Tls != nil {
+		tlsConfig := s.config.Tls.GetTLSConfig()
+		if len(tlsConfig.Certificates) == 0 {
+			listener.Close()
+			return newError("no valid certificate for web server")
+		}
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.server = &http.Server{
+		Handler: s,
+	}
+
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil {
+			log.Trace(newError("web server stopped").Base(err).AtInfo())
+		}
+	}(s.server)
+
+	log.Trace(newError("web server listening on ", listener.Addr()))
+	return nil
+}
+
+// Close implements app.Application.
+func (s *Instance) Close() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.server != nil {
+		s.server.Close()
+		s.server = nil
+	}
+}
+
+// FromSpace returns the web Instance in the given space, or nil if not present.
+func FromSpace(space app.Space) *Instance {
+	a := space.GetApplication((*Instance)(nil))
+	if a == nil {
+		return nil
+	}
+	return a.(*Instance)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
@@ -88,6 +88,16 @@ func (p *BufferPool) Free(buffer *Buffer) {
 	}
 }
 
+// Cached returns the number of buffers in the internal cache.
+func (p *BufferPool) Cached() int {
+	return len(p.chain)
+}
+
+// Capacity returns the size of the internal cache.
+func (p *BufferPool) Capacity() int {
+	return cap(p.chain)
+}
+
 const (
 	// Size of a regular buffer.
 	Size = 2 * 1024
@@ -99,6 +109,25 @@ var (
 	mediumPool Pool
 )
 
+// PoolStats is the status of the pool for regular buffers.
+type PoolStats struct {
+	// Cached is the number of buffers ready for use in the pool.
+	Cached int
+	// Capacity is the maximum number of buffers the pool may cache.
+	Capacity int
+}
+
+// GetPoolStats returns the status of the pool for regular buffers.
+func GetPoolStats() PoolStats {
+	if p, ok := mediumPool.(*BufferPool); ok {
+		return PoolStats{
+			Cached:   p.Cached(),
+			Capacity: p.Capacity(),
+		}
+	}
+	return PoolStats{}
+}
+
 func getDefaultPoolSize() int {
 	switch runtime.GOARCH {
 	case "amd64", "386":
@@ -1,6 +1,7 @@
 package net
 
 import (
+	"encoding/binary"
 	"net"
 
 	"v2ray.com/core/app/log"
@@ -104,6 +105,19 @@ func DomainAddress(domain string) Address {
 	return domainAddress(domain)
 }
 
+// AddressToUint128 returns the IP of the given address as a 128-bit integer in two halves, without allocation as
+// Address.IP() does. An IPv4 address is in the lower 32 bits. ok is false if the address is not an IP.
+func AddressToUint128(address Address) (hi uint64, lo uint64, ok bool) {
+	switch a := address.(type) {
+	case ipv4Address:
+		return 0, uint64(binary.BigEndian.Uint32(a[:])), true
+	case ipv6Address:
+		return binary.BigEndian.Uint64(a[:8]), binary.BigEndian.Uint64(a[8:]), true
+	default:
+		return 0, 0, false
+	}
+}
+
 type ipv4Address [4]byte
 
 func (v ipv4Address) IP() net.IP {
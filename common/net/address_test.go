@@ -72,3 +72,20 @@ func TestNetIPv4Address(t *testing.T) {
 	assert.Address(addr).IsIPv4()
 	assert.Address(addr).EqualsString("1.2.3.4")
 }
+
+func TestAddressToUint128(t *testing.T) {
+	assert := assert.On(t)
+
+	hi, lo, ok := AddressToUint128(IPAddress([]byte{1, 2, 3, 4}))
+	assert.Bool(ok).IsTrue()
+	assert.Int64(int64(hi)).Equals(0)
+	assert.Int64(int64(lo)).Equals(0x01020304)
+
+	hi, lo, ok = AddressToUint128(ParseAddress("2001:db8::1"))
+	assert.Bool(ok).IsTrue()
+	assert.Int64(int64(hi)).Equals(0x20010db800000000)
+	assert.Int64(int64(lo)).Equals(1)
+
+	_, _, ok = AddressToUint128(DomainAddress("v2ray.com"))
+	assert.Bool(ok).IsFalse()
+}
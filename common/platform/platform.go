@@ -2,6 +2,7 @@ package platform
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -40,3 +41,19 @@ func (f EnvFlag) GetValueAsInt(defaultValue int) int {
 func NormalizeEnvName(name string) string {
 	return strings.Replace(strings.ToUpper(strings.TrimSpace(name)), ".", "_", -1)
 }
+
+func getExecutableDir() string {
+	exec, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	return filepath.Dir(exec)
+}
+
+// GetAssetLocation returns the path of the given asset file, such as a GeoIP data file. Assets are in the directory
+// of the executable, unless the directory is set by environment variable "v2ray.location.asset".
+func GetAssetLocation(file string) string {
+	const name = "v2ray.location.asset"
+	assetPath := EnvFlag{Name: name, AltName: NormalizeEnvName(name)}.GetValue(getExecutableDir())
+	return filepath.Join(assetPath, file)
+}
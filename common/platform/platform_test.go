@@ -1,6 +1,8 @@
 package platform_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "v2ray.com/core/common/platform"
@@ -39,3 +41,16 @@ func TestEnvFlag(t *testing.T) {
 		Name: "xxxxx.y",
 	}.GetValueAsInt(10)).Equals(10)
 }
+
+func TestGetAssetLocation(t *testing.T) {
+	assert := assert.On(t)
+
+	exec, err := os.Executable()
+	assert.Error(err).IsNil()
+	loc := GetAssetLocation("t")
+	assert.String(filepath.Dir(loc)).Equals(filepath.Dir(exec))
+
+	os.Setenv("v2ray.location.asset", "/v2ray")
+	defer os.Unsetenv("v2ray.location.asset")
+	assert.String(GetAssetLocation("t")).Equals(filepath.Join("/v2ray", "t"))
+}
@@ -7,11 +7,13 @@ import (
 
 type ActivityTimer interface {
 	Update()
+	// SetTimeout changes the timeout of inactivity. Zero timeout cancels the context immediately.
+	SetTimeout(time.Duration)
 }
 
 type realActivityTimer struct {
 	updated chan bool
-	timeout time.Duration
+	timeout chan time.Duration
 	ctx     context.Context
 	cancel  context.CancelFunc
 }
@@ -23,15 +25,37 @@ func (t *realActivityTimer) Update() {
 	}
 }
 
-func (t *realActivityTimer) run() {
-	ticker := time.NewTicker(t.timeout)
-	defer ticker.Stop()
+func (t *realActivityTimer) SetTimeout(timeout time.Duration) {
+	select {
+	case t.timeout <- timeout:
+	case <-t.ctx.Done():
+	}
+}
+
+func (t *realActivityTimer) run(timeout time.Duration) {
+	if timeout == 0 {
+		t.cancel()
+		return
+	}
+
+	ticker := time.NewTicker(timeout)
+	defer func() {
+		ticker.Stop()
+	}()
 
 	for {
 		select {
 		case <-ticker.C:
 		case <-t.ctx.Done():
 			return
+		case timeout := <-t.timeout:
+			if timeout == 0 {
+				t.cancel()
+				return
+			}
+			ticker.Stop()
+			ticker = time.NewTicker(timeout)
+			continue
 		}
 
 		select {
@@ -49,9 +73,9 @@ func CancelAfterInactivity(ctx context.Context, timeout time.Duration) (context.
 	timer := &realActivityTimer{
 		ctx:     ctx,
 		cancel:  cancel,
-		timeout: timeout,
 		updated: make(chan bool, 1),
+		timeout: make(chan time.Duration),
 	}
-	go timer.run()
+	go timer.run(timeout)
 	return ctx, timer
 }
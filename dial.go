@@ -0,0 +1,200 @@
+package core
+
+import (
+	"context"
+	"io"
+	gonet "net"
+	"sync"
+	"time"
+
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/transport/ray"
+)
+
+// Dial implements Server.
+func (s *simpleServer) Dial(ctx context.Context, dest net.Destination) (gonet.Conn, error) {
+	if !dest.IsValid() {
+		return nil, newError("invalid destination: ", dest)
+	}
+	disp := dispatcher.FromSpace(s.space)
+	if disp == nil {
+		return nil, newError("dispatcher is not found in the space")
+	}
+	stream, err := disp.Dispatch(ctx, dest)
+	if err != nil {
+		return nil, newError("failed to dispatch to ", dest).Base(err)
+	}
+	if dest.Network == net.Network_UDP {
+		return newPacketConnection(stream, dest), nil
+	}
+	return outbound.NewConnection(stream), nil
+}
+
+// packetConnection is a net.Conn over a Ray, that keeps the boundaries of packets. Only read deadlines are supported.
+type packetConnection struct {
+	access       sync.Mutex
+	stream       ray.InboundRay
+	pending      buf.MultiBuffer
+	closed       bool
+	readDeadline time.Time
+	remoteAddr   gonet.Addr
+}
+
+func newPacketConnection(stream ray.InboundRay, dest net.Destination) *packetConnection {
+	var remoteAddr gonet.Addr = &gonet.UDPAddr{
+		IP:   []byte{0, 0, 0, 0},
+		Port: int(dest.Port),
+	}
+	if !dest.Address.Family().IsDomain() {
+		remoteAddr = &gonet.UDPAddr{
+			IP:   dest.Address.IP(),
+			Port: int(dest.Port),
+		}
+	}
+	return &packetConnection{
+		stream:     stream,
+		remoteAddr: remoteAddr,
+	}
+}
+
+// timeoutError is returned by Read() when the read deadline is exceeded.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var errWriteDeadline = newError("write deadline is not supported on packet connection")
+
+// takePacket returns the first pending packet, or nil if there is none.
+func (c *packetConnection) takePacket() (*buf.Buffer, error) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.closed {
+		return nil, io.EOF
+	}
+	if c.pending.IsEmpty() {
+		return nil, nil
+	}
+	return c.pending.SplitFirst(), nil
+}
+
+func (c *packetConnection) readStream() (buf.MultiBuffer, error) {
+	c.access.Lock()
+	deadline := c.readDeadline
+	c.access.Unlock()
+
+	if deadline.IsZero() {
+		return c.stream.InboundOutput().Read()
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return nil, timeoutError{}
+	}
+	mb, err := c.stream.InboundOutput().ReadTimeout(timeout)
+	if err == buf.ErrReadTimeout {
+		return nil, timeoutError{}
+	}
+	return mb, err
+}
+
+// Read implements net.Conn.Read(). Each call reads one packet. Excess bytes are discarded if b is smaller than the packet.
+func (c *packetConnection) Read(b []byte) (int, error) {
+	packet, err := c.takePacket()
+	if err != nil {
+		return 0, err
+	}
+	if packet == nil {
+		mb, err := c.readStream()
+		if err != nil {
+			return 0, err
+		}
+
+		c.access.Lock()
+		if c.closed {
+			c.access.Unlock()
+			mb.Release()
+			return 0, io.EOF
+		}
+		c.pending.AppendMulti(mb)
+		packet = c.pending.SplitFirst()
+		c.access.Unlock()
+	}
+	defer packet.Release()
+	return copy(b, packet.Bytes()), nil
+}
+
+// Write implements net.Conn.Write(). Each call writes one packet.
+func (c *packetConnection) Write(b []byte) (int, error) {
+	c.access.Lock()
+	closed := c.closed
+	c.access.Unlock()
+
+	if closed {
+		return 0, io.ErrClosedPipe
+	}
+	if len(b) > buf.Size {
+		return 0, newError("packet too large: ", len(b))
+	}
+	packet := buf.New()
+	packet.Append(b)
+	if err := c.stream.InboundInput().Write(buf.NewMultiBufferValue(packet)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close implements net.Conn.Close().
+func (c *packetConnection) Close() error {
+	c.access.Lock()
+	if c.closed {
+		c.access.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.pending.Release()
+	c.pending = nil
+	c.access.Unlock()
+
+	c.stream.InboundInput().Close()
+	c.stream.InboundOutput().CloseError()
+	return nil
+}
+
+// LocalAddr implements net.Conn.LocalAddr().
+func (c *packetConnection) LocalAddr() gonet.Addr {
+	return &gonet.UDPAddr{
+		IP:   []byte{0, 0, 0, 0},
+		Port: 0,
+	}
+}
+
+// RemoteAddr implements net.Conn.RemoteAddr().
+func (c *packetConnection) RemoteAddr() gonet.Addr {
+	return c.remoteAddr
+}
+
+// SetDeadline implements net.Conn.SetDeadline(). The read deadline is set, and an error is returned as write deadline
+// is not supported.
+func (c *packetConnection) SetDeadline(t time.Time) error {
+	c.SetReadDeadline(t)
+	return errWriteDeadline
+}
+
+// SetReadDeadline implements net.Conn.SetReadDeadline(). The deadline applies to reads that start after the call.
+func (c *packetConnection) SetReadDeadline(t time.Time) error {
+	c.access.Lock()
+	c.readDeadline = t
+	c.access.Unlock()
+	return nil
+}
+
+// SetWriteDeadline implements net.Conn.SetWriteDeadline(). It is not supported, as writes only block when the buffer
+// of the Ray is full.
+func (c *packetConnection) SetWriteDeadline(t time.Time) error {
+	return errWriteDeadline
+}
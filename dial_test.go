@@ -0,0 +1,157 @@
+package core_test
+
+import (
+	"context"
+	gonet "net"
+	"testing"
+	"time"
+
+	. "v2ray.com/core"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/proxy/blackhole"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/testing/servers/tcp"
+	"v2ray.com/core/testing/servers/udp"
+)
+
+func newDialServer(assert *assert.Assert) Server {
+	server, err := New(&Config{
+		Outbound: []*proxyman.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+			{
+				Tag:           "block",
+				ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+			},
+		},
+	})
+	assert.Error(err).IsNil()
+	assert.Error(server.Start()).IsNil()
+	return server
+}
+
+func TestV2RayDialTCP(t *testing.T) {
+	assert := assert.On(t)
+
+	tcpServer, dest := startEchoServer(assert)
+	defer tcpServer.Close()
+
+	server := newDialServer(assert)
+	defer server.Close()
+
+	conn, err := server.Dial(context.Background(), dest)
+	assert.Error(err).IsNil()
+	defer conn.Close()
+	echo(assert, conn)
+}
+
+func TestV2RayDialUDP(t *testing.T) {
+	assert := assert.On(t)
+
+	udpServer := udp.Server{
+		MsgProcessor: func(data []byte) []byte {
+			return data
+		},
+	}
+	dest, err := udpServer.Start()
+	assert.Error(err).IsNil()
+	defer udpServer.Close()
+
+	server := newDialServer(assert)
+	defer server.Close()
+
+	conn, err := server.Dial(context.Background(), dest)
+	assert.Error(err).IsNil()
+	defer conn.Close()
+
+	for _, payload := range []string{"ping", "pong"} {
+		_, err := conn.Write([]byte(payload))
+		assert.Error(err).IsNil()
+		response := make([]byte, 1024)
+		nBytes, err := conn.Read(response)
+		assert.Error(err).IsNil()
+		assert.String(string(response[:nBytes])).Equals(payload)
+	}
+}
+
+func TestV2RayDialWithOutboundTag(t *testing.T) {
+	assert := assert.On(t)
+
+	tcpServer := &tcp.Server{
+		MsgProcessor: func(data []byte) []byte {
+			return data
+		},
+	}
+	dest, err := tcpServer.Start()
+	assert.Error(err).IsNil()
+	defer tcpServer.Close()
+
+	server := newDialServer(assert)
+	defer server.Close()
+
+	conn, err := server.Dial(proxy.ContextWithOutboundTag(context.Background(), "block"), dest)
+	assert.Error(err).IsNil()
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping"))
+	assert.Error(err).IsNil()
+	_, err = conn.Read(make([]byte, 1024))
+	assert.Error(err).IsNotNil()
+}
+
+func TestV2RayDialUDPReadDeadline(t *testing.T) {
+	assert := assert.On(t)
+
+	udpServer := udp.Server{
+		MsgProcessor: func(data []byte) []byte {
+			return data
+		},
+	}
+	dest, err := udpServer.Start()
+	assert.Error(err).IsNil()
+	defer udpServer.Close()
+
+	server := newDialServer(assert)
+	defer server.Close()
+
+	conn, err := server.Dial(context.Background(), dest)
+	assert.Error(err).IsNil()
+
+	assert.Error(conn.SetReadDeadline(time.Now().Add(time.Millisecond * 200))).IsNil()
+	assert.Error(conn.SetWriteDeadline(time.Now())).IsNotNil()
+	start := time.Now()
+	_, err = conn.Read(make([]byte, 1024))
+	assert.Error(err).IsNotNil()
+	netErr, ok := err.(gonet.Error)
+	assert.Bool(ok).IsTrue()
+	assert.Bool(netErr.Timeout()).IsTrue()
+	assert.Bool(time.Since(start) < time.Second*2).IsTrue()
+
+	assert.Error(conn.SetReadDeadline(time.Time{})).IsNil()
+	_, err = conn.Write([]byte("ping"))
+	assert.Error(err).IsNil()
+	response := make([]byte, 1024)
+	nBytes, err := conn.Read(response)
+	assert.Error(err).IsNil()
+	assert.String(string(response[:nBytes])).Equals("ping")
+
+	// Close while a read is blocking.
+	done := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1024))
+		done <- err
+	}()
+	time.Sleep(time.Millisecond * 100)
+	assert.Error(conn.Close()).IsNil()
+	select {
+	case err := <-done:
+		assert.Error(err).IsNotNil()
+	case <-time.After(time.Second * 2):
+		t.Error("read is not unblocked by close")
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/transport"
 )
 
 // ConfigLoader is an utility to load V2Ray config from external source.
@@ -28,6 +30,67 @@ func LoadConfig(format ConfigFormat, input io.Reader) (*Config, error) {
 	return loader(input)
 }
 
+// MergeConfigs merges multiple configs into one. Inbound and outbound handlers are appended in order,
+// while app settings and transport settings of the same type are merged, with later values taking precedence.
+// It returns an error if a tag is used by more than one inbound or outbound handler.
+func MergeConfigs(configs ...*Config) (*Config, error) {
+	merged := new(Config)
+	inboundTags := make(map[string]bool)
+	outboundTags := make(map[string]bool)
+	apps := make(map[string]proto.Message)
+
+	for _, config := range configs {
+		for _, inbound := range config.Inbound {
+			if len(inbound.Tag) > 0 {
+				if inboundTags[inbound.Tag] {
+					return nil, newError("duplicated inbound tag: ", inbound.Tag)
+				}
+				inboundTags[inbound.Tag] = true
+			}
+			merged.Inbound = append(merged.Inbound, inbound)
+		}
+
+		for _, outbound := range config.Outbound {
+			if len(outbound.Tag) > 0 {
+				if outboundTags[outbound.Tag] {
+					return nil, newError("duplicated outbound tag: ", outbound.Tag)
+				}
+				outboundTags[outbound.Tag] = true
+			}
+			merged.Outbound = append(merged.Outbound, outbound)
+		}
+
+		for _, appSettings := range config.App {
+			settings, err := appSettings.GetInstance()
+			if err != nil {
+				return nil, err
+			}
+			if existing, found := apps[appSettings.Type]; found {
+				proto.Merge(existing, settings)
+				continue
+			}
+			apps[appSettings.Type] = settings
+			merged.App = append(merged.App, appSettings)
+		}
+
+		if config.Transport != nil {
+			if merged.Transport == nil {
+				merged.Transport = proto.Clone(config.Transport).(*transport.Config)
+			} else {
+				proto.Merge(merged.Transport, config.Transport)
+			}
+		}
+
+		merged.Extension = append(merged.Extension, config.Extension...)
+	}
+
+	for idx, appSettings := range merged.App {
+		merged.App[idx] = serial.ToTypedMessage(apps[appSettings.Type])
+	}
+
+	return merged, nil
+}
+
 func loadProtobufConfig(input io.Reader) (*Config, error) {
 	config := new(Config)
 	data, _ := ioutil.ReadAll(input)
@@ -0,0 +1,58 @@
+package core_test
+
+import (
+	"testing"
+
+	. "v2ray.com/core"
+	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/testing/assert"
+)
+
+func TestMergeConfigs(t *testing.T) {
+	assert := assert.On(t)
+
+	c1 := &Config{
+		Inbound: []*proxyman.InboundHandlerConfig{{Tag: "in1"}},
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{{Tag: "direct"}},
+			}),
+		},
+	}
+	c2 := &Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{{Tag: "in2"}},
+		Outbound: []*proxyman.OutboundHandlerConfig{{Tag: "direct"}},
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&router.Config{
+				DomainStrategy: router.Config_IpIfNonMatch,
+				Rule:           []*router.RoutingRule{{Tag: "blocked"}},
+			}),
+			serial.ToTypedMessage(&dns.Config{
+				NameServers: []*v2net.Endpoint{{Address: v2net.NewIPOrDomain(v2net.LocalHostIP)}},
+			}),
+		},
+	}
+
+	config, err := MergeConfigs(c1, c2)
+	assert.Error(err).IsNil()
+	assert.Int(len(config.Inbound)).Equals(2)
+	assert.String(config.Inbound[1].Tag).Equals("in2")
+	assert.Int(len(config.Outbound)).Equals(1)
+	assert.Int(len(config.App)).Equals(2)
+
+	rawRouter, err := config.App[0].GetInstance()
+	assert.Error(err).IsNil()
+	routerConfig := rawRouter.(*router.Config)
+	assert.Int(len(routerConfig.Rule)).Equals(2)
+	assert.String(routerConfig.Rule[1].Tag).Equals("blocked")
+	assert.Bool(routerConfig.DomainStrategy == router.Config_IpIfNonMatch).IsTrue()
+
+	_, err = MergeConfigs(c1, c2, &Config{
+		Inbound: []*proxyman.InboundHandlerConfig{{Tag: "in1"}},
+	})
+	assert.Error(err).IsNotNil()
+}
@@ -3,34 +3,49 @@ package main
 //go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg main -path Main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"v2ray.com/core"
 
 	_ "v2ray.com/core/main/distro/all"
 )
 
+type configFileList []string
+
+func (l *configFileList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *configFileList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 var (
-	configFile string
-	version    = flag.Bool("version", false, "Show current version of V2Ray.")
-	test       = flag.Bool("test", false, "Test config file only, without launching V2Ray server.")
-	format     = flag.String("format", "json", "Format of input file.")
+	configFiles       configFileList
+	defaultConfigFile string
+	version           = flag.Bool("version", false, "Show current version of V2Ray.")
+	test              = flag.Bool("test", false, "Test config file only, without launching V2Ray server.")
+	format            = flag.String("format", "json", "Format of input file.")
+	drain             = flag.Duration("drain", time.Second*10, "Time to wait for active connections to finish on shutdown. Set to 0 to exit immediately. A second signal forces V2Ray to exit.")
 )
 
 func init() {
-	defaultConfigFile := ""
 	workingDir, err := filepath.Abs(filepath.Dir(os.Args[0]))
 	if err == nil {
 		defaultConfigFile = filepath.Join(workingDir, "config.json")
 	}
-	flag.StringVar(&configFile, "config", defaultConfigFile, "Config file for this Point server.")
+	flag.Var(&configFiles, "config", "Config file or directory for this Point server. Can be specified multiple times. Default to config.json next to the executable.")
 }
 
 func GetConfigFormat() core.ConfigFormat {
@@ -44,16 +59,62 @@ func GetConfigFormat() core.ConfigFormat {
 	}
 }
 
-func startV2Ray() (core.Server, error) {
-	if len(configFile) == 0 {
-		return nil, newError("config file is not set")
+func getConfigFileExtension() string {
+	if GetConfigFormat() == core.ConfigFormat_Protobuf {
+		return ".pb"
+	}
+	return ".json"
+}
+
+// expandConfigFiles returns the list of config files to load. Directories are expanded to the files in them
+// with the extension of the config format, in alphabetical order.
+func expandConfigFiles() ([]string, error) {
+	files := []string(configFiles)
+	if len(files) == 0 {
+		if len(defaultConfigFile) == 0 {
+			return nil, newError("config file is not set")
+		}
+		files = []string{defaultConfigFile}
+	}
+
+	var expanded []string
+	for _, file := range files {
+		if file == "stdin:" {
+			expanded = append(expanded, file)
+			continue
+		}
+		file = os.ExpandEnv(file)
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, newError("config file not readable: ", file).Base(err)
+		}
+		if !info.IsDir() {
+			expanded = append(expanded, file)
+			continue
+		}
+		entries, err := ioutil.ReadDir(file)
+		if err != nil {
+			return nil, newError("failed to read config directory: ", file).Base(err)
+		}
+		ext := getConfigFileExtension()
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ext) {
+				expanded = append(expanded, filepath.Join(file, entry.Name()))
+			}
+		}
+	}
+	if len(expanded) == 0 {
+		return nil, newError("no config file found")
 	}
+	return expanded, nil
+}
+
+func loadConfigFile(configFile string) (*core.Config, error) {
 	var configInput io.Reader
 	if configFile == "stdin:" {
 		configInput = os.Stdin
 	} else {
-		fixedFile := os.ExpandEnv(configFile)
-		file, err := os.Open(fixedFile)
+		file, err := os.Open(configFile)
 		if err != nil {
 			return nil, newError("config file not readable").Base(err)
 		}
@@ -64,6 +125,39 @@ func startV2Ray() (core.Server, error) {
 	if err != nil {
 		return nil, newError("failed to read config file: ", configFile).Base(err)
 	}
+	return config, nil
+}
+
+func loadConfig() (*core.Config, error) {
+	files, err := expandConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]*core.Config, 0, len(files))
+	for _, file := range files {
+		config, err := loadConfigFile(file)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	if len(configs) == 1 {
+		return configs[0], nil
+	}
+
+	config, err := core.MergeConfigs(configs...)
+	if err != nil {
+		return nil, newError("failed to merge config files").Base(err)
+	}
+	return config, nil
+}
+
+func startV2Ray() (core.Server, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	server, err := core.New(config)
 	if err != nil {
@@ -73,6 +167,19 @@ func startV2Ray() (core.Server, error) {
 	return server, nil
 }
 
+func reloadV2Ray(server core.Server) error {
+	for _, file := range configFiles {
+		if file == "stdin:" {
+			return newError("config from stdin can't be reloaded")
+		}
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	return server.Reload(config)
+}
+
 func main() {
 	flag.Parse()
 
@@ -98,8 +205,28 @@ func main() {
 	}
 
 	osSignals := make(chan os.Signal, 1)
-	signal.Notify(osSignals, os.Interrupt, os.Kill, syscall.SIGTERM)
+	signal.Notify(osSignals, os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGHUP)
 
-	<-osSignals
-	server.Close()
+	for sig := range osSignals {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		if err := reloadV2Ray(server); err != nil {
+			fmt.Println("Failed to reload", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *drain)
+	go func() {
+		for sig := range osSignals {
+			if sig != syscall.SIGHUP {
+				cancel()
+				return
+			}
+		}
+	}()
+	if err := server.Shutdown(ctx); err != nil && *drain > 0 {
+		fmt.Println("Shutdown before all connections finish:", err)
+	}
+	cancel()
 }
@@ -15,6 +15,7 @@ const (
 	inboundEntryPointKey
 	inboundTagKey
 	resolvedIPsKey
+	outboundTagKey
 )
 
 func ContextWithSource(ctx context.Context, src net.Destination) context.Context {
@@ -62,6 +63,17 @@ func InboundTagFromContext(ctx context.Context) (string, bool) {
 	return v, ok
 }
 
+// ContextWithOutboundTag returns a new context that forces the dispatcher to send traffic through the outbound handler with the given tag,
+// instead of the one chosen by the router.
+func ContextWithOutboundTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, outboundTagKey, tag)
+}
+
+func OutboundTagFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(outboundTagKey).(string)
+	return v, ok
+}
+
 func ContextWithResolveIPs(ctx context.Context, ips []net.Address) context.Context {
 	return context.WithValue(ctx, resolvedIPsKey, ips)
 }
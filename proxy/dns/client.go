@@ -0,0 +1,78 @@
+package dns
+
+import (
+	"context"
+	gonet "net"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dns/resolver"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/ray"
+)
+
+// Client is a DNS outbound. It answers A and AAAA queries sent to it with the DNS app, and forwards other queries
+// upstream directly. It is useful for taking over DNS traffic received by other inbounds, e.g., a dokodemo door on port 53.
+type Client struct {
+	handler
+	config *ClientConfig
+}
+
+// NewClient creates a new DNS outbound based on the given config.
+func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, newError("no space in context")
+	}
+	c := &Client{
+		config: config,
+	}
+	space.OnInitialize(func() error {
+		c.dns = resolver.FromSpace(space)
+		if c.dns == nil {
+			return newError("DNS server is not found in the space")
+		}
+		return nil
+	})
+	return c, nil
+}
+
+// Process implements proxy.Outbound.
+func (c *Client) Process(ctx context.Context, outboundRay ray.OutboundRay, dialer proxy.Dialer) error {
+	destination, ok := proxy.TargetFromContext(ctx)
+	if !ok {
+		return newError("target not specified")
+	}
+	upstream := upstreamOf(c.config.Server, destination, destination.Network)
+
+	dial := func(ctx context.Context, dest net.Destination) (gonet.Conn, error) {
+		return dialer.Dial(ctx, dest)
+	}
+
+	input := outboundRay.OutboundInput()
+	output := outboundRay.OutboundOutput()
+
+	var reader messageReader
+	var writer messageWriter
+	if destination.Network == net.Network_TCP {
+		reader, writer = &streamReader{buf.ToBytesReader(input)}, &streamWriter{buf.ToBytesWriter(output)}
+	} else {
+		reader, writer = &rayPacketReader{reader: input}, &rayPacketWriter{writer: output}
+	}
+
+	if err := c.serve(ctx, reader, writer, upstream, dial, nil); err != nil {
+		input.CloseError()
+		output.CloseError()
+		return newError("connection ends").Base(err)
+	}
+	output.Close()
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ClientConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewClient(ctx, config.(*ClientConfig))
+	}))
+}
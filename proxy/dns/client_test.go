@@ -0,0 +1,198 @@
+package dns_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dns/resolver"
+	"v2ray.com/core/common/buf"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/proxy"
+	. "v2ray.com/core/proxy/dns"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/ray"
+)
+
+type staticDNS map[string][]net.IP
+
+func (staticDNS) Interface() interface{} {
+	return (*resolver.Server)(nil)
+}
+
+func (staticDNS) Start() error {
+	return nil
+}
+
+func (staticDNS) Close() {}
+
+func (s staticDNS) Get(domain string) []net.IP {
+	return s[domain]
+}
+
+type fakeDNS struct {
+	staticDNS
+}
+
+func (fakeDNS) GetFakeIP(domain string) net.IP {
+	return net.IP{198, 18, 0, 1}
+}
+
+func (fakeDNS) GetFakeDomain(ip net.IP) (string, bool) {
+	return "", false
+}
+
+type systemDialer struct{}
+
+func (systemDialer) Dial(ctx context.Context, dest v2net.Destination) (internet.Connection, error) {
+	return net.Dial(dest.Network.SystemString(), dest.NetAddr())
+}
+
+func startUpstream(assert *assert.Assert) (*dns.Server, v2net.Destination) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Error(err).IsNil()
+
+	server := &dns.Server{
+		PacketConn: conn,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			msg := new(dns.Msg).SetReply(r)
+			rr, err := dns.NewRR(r.Question[0].Name + " 60 IN TXT \"upstream\"")
+			assert.Error(err).IsNil()
+			msg.Answer = append(msg.Answer, rr)
+			w.WriteMsg(msg)
+		}),
+	}
+	go server.ActivateAndServe()
+	return server, v2net.DestinationFromAddr(conn.LocalAddr())
+}
+
+func query(assert *assert.Assert, stream ray.InboundRay, name string, qtype uint16) *dns.Msg {
+	msg := new(dns.Msg).SetQuestion(name, qtype)
+	payload, err := msg.Pack()
+	assert.Error(err).IsNil()
+
+	b := buf.New()
+	b.Append(payload)
+	assert.Error(stream.InboundInput().Write(buf.NewMultiBufferValue(b))).IsNil()
+
+	mb, err := stream.InboundOutput().Read()
+	assert.Error(err).IsNil()
+	assert.Int(len(mb)).Equals(1)
+
+	response := new(dns.Msg)
+	assert.Error(response.Unpack(mb[0].Bytes())).IsNil()
+	mb.Release()
+	assert.Uint16(response.Id).Equals(msg.Id)
+	return response
+}
+
+func TestClient(t *testing.T) {
+	assert := assert.On(t)
+
+	upstream, dest := startUpstream(assert)
+	defer upstream.Shutdown()
+
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(staticDNS{
+		"v2ray.com": {net.ParseIP("127.0.0.2"), net.ParseIP("::2")},
+	})).IsNil()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	client, err := NewClient(ctx, &ClientConfig{})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	stream := ray.NewRay(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Process(proxy.ContextWithTarget(ctx, dest), stream, systemDialer{})
+	}()
+
+	response := query(assert, stream, "v2ray.com.", dns.TypeA)
+	assert.Int(response.Rcode).Equals(dns.RcodeSuccess)
+	assert.Int(len(response.Answer)).Equals(1)
+	assert.String(response.Answer[0].(*dns.A).A.String()).Equals("127.0.0.2")
+
+	response = query(assert, stream, "v2ray.com.", dns.TypeAAAA)
+	assert.Int(response.Rcode).Equals(dns.RcodeSuccess)
+	assert.Int(len(response.Answer)).Equals(1)
+	assert.String(response.Answer[0].(*dns.AAAA).AAAA.String()).Equals("::2")
+
+	response = query(assert, stream, "v2ray.test.", dns.TypeA)
+	assert.Int(response.Rcode).Equals(dns.RcodeNameError)
+
+	response = query(assert, stream, "v2ray.com.", dns.TypeTXT)
+	assert.Int(response.Rcode).Equals(dns.RcodeSuccess)
+	assert.Int(len(response.Answer)).Equals(1)
+	assert.String(response.Answer[0].(*dns.TXT).Txt[0]).Equals("upstream")
+
+	stream.InboundInput().Close()
+	assert.Error(<-done).IsNil()
+}
+
+func TestClientServerFailure(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(staticDNS{})).IsNil()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	client, err := NewClient(ctx, &ClientConfig{})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	// Nothing is listening on port 1 of localhost, so the upstream fails.
+	stream := ray.NewRay(ctx)
+	go client.Process(proxy.ContextWithTarget(ctx, v2net.TCPDestination(v2net.LocalHostIP, v2net.Port(1))), stream, systemDialer{})
+
+	msg := new(dns.Msg).SetQuestion("v2ray.com.", dns.TypeMX)
+	payload, err := msg.Pack()
+	assert.Error(err).IsNil()
+	frame := append([]byte{byte(len(payload) >> 8), byte(len(payload))}, payload...)
+	_, err = buf.ToBytesWriter(stream.InboundInput()).Write(frame)
+	assert.Error(err).IsNil()
+
+	reader := buf.ToBytesReader(stream.InboundOutput())
+	var length [2]byte
+	_, err = io.ReadFull(reader, length[:])
+	assert.Error(err).IsNil()
+	b := make([]byte, int(length[0])<<8|int(length[1]))
+	_, err = io.ReadFull(reader, b)
+	assert.Error(err).IsNil()
+
+	response := new(dns.Msg)
+	assert.Error(response.Unpack(b)).IsNil()
+	assert.Int(response.Rcode).Equals(dns.RcodeServerFailure)
+
+	stream.InboundInput().Close()
+}
+
+func TestClientFakeDNS(t *testing.T) {
+	assert := assert.On(t)
+
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(fakeDNS{staticDNS{
+		"v2ray.com": {net.ParseIP("127.0.0.2")},
+	}})).IsNil()
+	ctx := app.ContextWithSpace(context.Background(), space)
+	client, err := NewClient(ctx, &ClientConfig{})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+
+	stream := ray.NewRay(ctx)
+	go client.Process(proxy.ContextWithTarget(ctx, v2net.UDPDestination(v2net.LocalHostIP, v2net.Port(53))), stream, systemDialer{})
+
+	response := query(assert, stream, "v2ray.com.", dns.TypeA)
+	assert.Int(response.Rcode).Equals(dns.RcodeSuccess)
+	assert.Int(len(response.Answer)).Equals(1)
+	assert.String(response.Answer[0].(*dns.A).A.String()).Equals("198.18.0.1")
+
+	// The fake IP is IPv4, so there is no IPv6 address.
+	response = query(assert, stream, "v2ray.com.", dns.TypeAAAA)
+	assert.Int(response.Rcode).Equals(dns.RcodeSuccess)
+	assert.Int(len(response.Answer)).Equals(0)
+
+	stream.InboundInput().Close()
+}
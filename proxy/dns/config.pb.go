@@ -0,0 +1,88 @@
+package dns
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import v2ray_core_common_net "v2ray.com/core/common/net"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// ServerConfig is the config of the DNS inbound, which answers queries from local clients.
+type ServerConfig struct {
+	// Server to forward queries other than A and AAAA to. If not set, queries are forwarded to the original destination
+	// of the connection, if there is one. The network defaults to the one used by the client.
+	Server    *v2ray_core_common_net.Endpoint `protobuf:"bytes,1,opt,name=server" json:"server,omitempty"`
+	UserLevel uint32                          `protobuf:"varint,2,opt,name=user_level,json=userLevel" json:"user_level,omitempty"`
+}
+
+func (m *ServerConfig) Reset()                    { *m = ServerConfig{} }
+func (m *ServerConfig) String() string            { return proto.CompactTextString(m) }
+func (*ServerConfig) ProtoMessage()               {}
+func (*ServerConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ServerConfig) GetServer() *v2ray_core_common_net.Endpoint {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetUserLevel() uint32 {
+	if m != nil {
+		return m.UserLevel
+	}
+	return 0
+}
+
+// ClientConfig is the config of the DNS outbound, which answers queries sent to it, e.g., through a dokodemo door.
+type ClientConfig struct {
+	// Server to forward queries other than A and AAAA to. If not set, queries are forwarded to the original destination.
+	Server *v2ray_core_common_net.Endpoint `protobuf:"bytes,1,opt,name=server" json:"server,omitempty"`
+}
+
+func (m *ClientConfig) Reset()                    { *m = ClientConfig{} }
+func (m *ClientConfig) String() string            { return proto.CompactTextString(m) }
+func (*ClientConfig) ProtoMessage()               {}
+func (*ClientConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ClientConfig) GetServer() *v2ray_core_common_net.Endpoint {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.dns.ServerConfig")
+	proto.RegisterType((*ClientConfig)(nil), "v2ray.core.proxy.dns.ClientConfig")
+}
+
+func init() { proto.RegisterFile("v2ray.com/core/proxy/dns/config.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 228 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x8f, 0x31, 0x4b, 0xc4, 0x40,
+	0x10, 0x85, 0xc9, 0x09, 0x07, 0xae, 0x67, 0x13, 0xae, 0x08, 0x82, 0x78, 0x1c, 0x08, 0x07, 0xc2,
+	0x2c, 0xc4, 0x42, 0x6b, 0xa3, 0xd8, 0x58, 0x1c, 0x11, 0x2c, 0x6c, 0x24, 0xee, 0xce, 0xc9, 0x42,
+	0x76, 0x26, 0xcc, 0xae, 0xc1, 0xfc, 0x25, 0x7f, 0xa5, 0x64, 0xa3, 0x20, 0x62, 0x77, 0xe5, 0xbc,
+	0xf7, 0xe6, 0x7b, 0x3c, 0x75, 0xde, 0x97, 0xd2, 0x0c, 0x60, 0xd8, 0x6b, 0xc3, 0x82, 0xba, 0x13,
+	0xfe, 0x18, 0xb4, 0xa5, 0xa0, 0x0d, 0xd3, 0xce, 0xbd, 0x41, 0x27, 0x1c, 0x39, 0x5f, 0xfe, 0xc4,
+	0x04, 0x21, 0x45, 0xc0, 0x52, 0x38, 0xb9, 0xf8, 0xf3, 0x6c, 0xd8, 0x7b, 0x26, 0x4d, 0x18, 0xb5,
+	0xc5, 0x10, 0x1d, 0x35, 0xd1, 0x31, 0x4d, 0x88, 0xf5, 0x4e, 0x2d, 0x1e, 0x51, 0x7a, 0x94, 0x2a,
+	0x81, 0xf3, 0x2b, 0x35, 0x0f, 0xe9, 0x2e, 0xb2, 0x55, 0xb6, 0x39, 0x2a, 0xcf, 0xe0, 0x57, 0xc7,
+	0x44, 0x02, 0xc2, 0x08, 0x77, 0x64, 0x3b, 0x76, 0x14, 0xeb, 0xef, 0x78, 0x7e, 0xaa, 0xd4, 0x7b,
+	0x40, 0x79, 0x69, 0xb1, 0xc7, 0xb6, 0x98, 0xad, 0xb2, 0xcd, 0x71, 0x7d, 0x38, 0x2a, 0x0f, 0xa3,
+	0xb0, 0xbe, 0x57, 0x8b, 0xaa, 0x75, 0x48, 0x71, 0xcf, 0x9e, 0x9b, 0x6b, 0x55, 0x18, 0xf6, 0xf0,
+	0xdf, 0xf2, 0x6d, 0xf6, 0x7c, 0x60, 0x29, 0x7c, 0xce, 0x96, 0x4f, 0x65, 0xdd, 0x0c, 0x50, 0x8d,
+	0xee, 0x36, 0xb9, 0xb7, 0x14, 0x5e, 0xe7, 0x69, 0xf1, 0xe5, 0xd7, 0x00, 0x06, 0xdd, 0xff, 0x77,
+	0x5d, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,301 @@
+// Package dns provides a DNS inbound and outbound, which answer queries with the DNS app of V2Ray.
+package dns
+
+//go:generate go run $GOPATH/src/v2ray.com/core/tools/generrorgen/main.go -pkg dns -path Proxy,DNS
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	gonet "net"
+	"strings"
+	"sync"
+	"time"
+
+	dnsmsg "github.com/miekg/dns"
+	"v2ray.com/core/app/dns/resolver"
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/signal"
+)
+
+const (
+	// answerTTL is the TTL of records in answers from the DNS app.
+	answerTTL = 60
+
+	// fakeAnswerTTL is the TTL of fake IPs. It is short, as fake IPs may be reused by other domains.
+	fakeAnswerTTL = 1
+
+	// forwardTimeout is the time to wait for a response of a forwarded query.
+	forwardTimeout = time.Second * 8
+)
+
+// dialFunc creates a connection to the given destination.
+type dialFunc func(ctx context.Context, dest net.Destination) (gonet.Conn, error)
+
+// handler answers A and AAAA queries with a DNS server, and forwards other queries upstream.
+type handler struct {
+	dns resolver.Server
+}
+
+// serve reads queries from reader and writes responses to writer, until reader fails. Queries are handled
+// concurrently, so that a slow query doesn't block the following ones. timer, if not nil, is updated on every message.
+func (h *handler) serve(ctx context.Context, reader messageReader, writer messageWriter, upstream net.Destination, dial dialFunc, timer signal.ActivityTimer) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	var writeLock sync.Mutex
+	for {
+		query, err := reader.ReadMessage()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return newError("failed to read query").Base(err)
+		}
+		if timer != nil {
+			timer.Update()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			response := h.handle(ctx, query, upstream, dial)
+			if response == nil {
+				return
+			}
+			writeLock.Lock()
+			defer writeLock.Unlock()
+			if err := writer.WriteMessage(response); err != nil {
+				log.Trace(newError("failed to write response").Base(err).AtWarning())
+			} else if timer != nil {
+				timer.Update()
+			}
+		}()
+	}
+}
+
+// handle returns the packed response of the given query, or nil if there should be no response.
+func (h *handler) handle(ctx context.Context, query []byte, upstream net.Destination, dial dialFunc) []byte {
+	msg := new(dnsmsg.Msg)
+	if err := msg.Unpack(query); err != nil {
+		log.Trace(newError("failed to parse DNS query").Base(err).AtWarning())
+		return nil
+	}
+	if msg.Response {
+		return nil
+	}
+
+	var response *dnsmsg.Msg
+	if len(msg.Question) != 1 || msg.Opcode != dnsmsg.OpcodeQuery {
+		response = new(dnsmsg.Msg).SetRcode(msg, dnsmsg.RcodeNotImplemented)
+	} else if q := msg.Question[0]; q.Qclass == dnsmsg.ClassINET && (q.Qtype == dnsmsg.TypeA || q.Qtype == dnsmsg.TypeAAAA) {
+		response = h.answer(ctx, msg)
+	} else {
+		r, err := h.forward(ctx, query, upstream, dial)
+		if err == nil {
+			return r
+		}
+		log.Trace(newError("failed to forward query for ", q.Name, " to ", upstream).Base(err).AtWarning())
+		response = new(dnsmsg.Msg).SetRcode(msg, dnsmsg.RcodeServerFailure)
+	}
+	response.RecursionAvailable = true
+
+	b, err := response.Pack()
+	if err != nil {
+		log.Trace(newError("failed to build DNS response").Base(err).AtWarning())
+		return nil
+	}
+	return b
+}
+
+// answer answers an A or AAAA query with the DNS server. The response is NXDOMAIN if the domain has no IP, or SERVFAIL
+// if no name server answers. IPs of the other family are dropped, so that the answer follows the query strategy of
+// the DNS server.
+// If the DNS server supports fake DNS, fake IPs are returned instead.
+func (h *handler) answer(ctx context.Context, query *dnsmsg.Msg) *dnsmsg.Msg {
+	domain := strings.TrimSuffix(query.Question[0].Name, ".")
+	if fakeDNS, ok := h.dns.(resolver.FakeDNS); ok {
+		if ip := fakeDNS.GetFakeIP(domain); ip != nil {
+			return reply(query, []gonet.IP{ip}, fakeAnswerTTL)
+		}
+	}
+
+	ips, err := resolver.Resolve(ctx, h.dns, domain)
+	if err == resolver.ErrNotFound {
+		return new(dnsmsg.Msg).SetRcode(query, dnsmsg.RcodeNameError)
+	}
+	if err != nil {
+		log.Trace(newError("failed to resolve ", domain).Base(err).AtWarning())
+		return new(dnsmsg.Msg).SetRcode(query, dnsmsg.RcodeServerFailure)
+	}
+	return reply(query, ips, answerTTL)
+}
+
+// reply creates a response of the given query, with the IPs of the queried family.
+func reply(query *dnsmsg.Msg, ips []gonet.IP, ttl uint32) *dnsmsg.Msg {
+	q := query.Question[0]
+	response := new(dnsmsg.Msg).SetReply(query)
+	header := dnsmsg.RR_Header{
+		Name:   q.Name,
+		Rrtype: q.Qtype,
+		Class:  dnsmsg.ClassINET,
+		Ttl:    ttl,
+	}
+	for _, ip := range ips {
+		ip4 := ip.To4()
+		switch {
+		case q.Qtype == dnsmsg.TypeA && ip4 != nil:
+			response.Answer = append(response.Answer, &dnsmsg.A{Hdr: header, A: ip4})
+		case q.Qtype == dnsmsg.TypeAAAA && ip4 == nil:
+			response.Answer = append(response.Answer, &dnsmsg.AAAA{Hdr: header, AAAA: ip})
+		}
+	}
+	return response
+}
+
+// forward sends the query to upstream, and returns the response as is.
+func (h *handler) forward(ctx context.Context, query []byte, upstream net.Destination, dial dialFunc) ([]byte, error) {
+	if !upstream.IsValid() {
+		return nil, newError("no upstream server")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, forwardTimeout)
+	defer cancel()
+
+	conn, err := dial(ctx, upstream)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	var reader messageReader
+	var writer messageWriter
+	if upstream.Network == net.Network_TCP {
+		reader, writer = &streamReader{conn}, &streamWriter{conn}
+	} else {
+		reader, writer = &packetReader{conn, dnsmsg.MaxMsgSize}, &packetWriter{conn}
+	}
+	if err := writer.WriteMessage(query); err != nil {
+		return nil, err
+	}
+	return reader.ReadMessage()
+}
+
+// upstreamOf returns the destination that queries should be forwarded to. network is the one used by the client.
+func upstreamOf(server *net.Endpoint, original net.Destination, network net.Network) net.Destination {
+	if server != nil && server.Address != nil {
+		dest := server.AsDestination()
+		if dest.Network == net.Network_Unknown {
+			dest.Network = network
+		}
+		if dest.Port == 0 {
+			dest.Port = net.Port(53)
+		}
+		return dest
+	}
+	return original
+}
+
+type messageReader interface {
+	// ReadMessage reads a DNS message.
+	ReadMessage() ([]byte, error)
+}
+
+type messageWriter interface {
+	// WriteMessage writes a DNS message.
+	WriteMessage([]byte) error
+}
+
+// packetReader reads DNS messages from a packet connection, where each Read returns one packet.
+type packetReader struct {
+	io.Reader
+	size int
+}
+
+func (r *packetReader) ReadMessage() ([]byte, error) {
+	b := make([]byte, r.size)
+	n, err := r.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+
+// packetWriter writes DNS messages to a packet connection, where each Write sends one packet.
+type packetWriter struct {
+	io.Writer
+}
+
+func (w *packetWriter) WriteMessage(b []byte) error {
+	_, err := w.Write(b)
+	return err
+}
+
+// streamReader reads DNS messages from a stream, where each message is prefixed with its length in 2 bytes.
+type streamReader struct {
+	io.Reader
+}
+
+func (r *streamReader) ReadMessage() ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	b := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// streamWriter writes DNS messages to a stream, where each message is prefixed with its length in 2 bytes.
+type streamWriter struct {
+	io.Writer
+}
+
+func (w *streamWriter) WriteMessage(b []byte) error {
+	frame := make([]byte, 2+len(b))
+	binary.BigEndian.PutUint16(frame, uint16(len(b)))
+	copy(frame[2:], b)
+	_, err := w.Write(frame)
+	return err
+}
+
+// rayPacketReader reads DNS messages from a ray carrying UDP traffic, where each buffer is one packet.
+type rayPacketReader struct {
+	reader  buf.Reader
+	pending buf.MultiBuffer
+}
+
+func (r *rayPacketReader) ReadMessage() ([]byte, error) {
+	for r.pending.IsEmpty() {
+		mb, err := r.reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		r.pending = mb
+	}
+	packet := r.pending.SplitFirst()
+	defer packet.Release()
+	return append([]byte(nil), packet.Bytes()...), nil
+}
+
+// rayPacketWriter writes DNS messages to a ray carrying UDP traffic, one buffer per packet.
+type rayPacketWriter struct {
+	writer buf.Writer
+}
+
+func (w *rayPacketWriter) WriteMessage(b []byte) error {
+	if len(b) > buf.Size {
+		return newError("DNS message too large: ", len(b))
+	}
+	packet := buf.New()
+	packet.Append(b)
+	return w.writer.Write(buf.NewMultiBufferValue(packet))
+}
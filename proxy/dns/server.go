@@ -0,0 +1,104 @@
+package dns
+
+import (
+	"context"
+	gonet "net"
+	"time"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/dns/resolver"
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport/internet"
+)
+
+// Server is a DNS inbound. It answers A and AAAA queries from local clients with the DNS app,
+// and forwards other queries upstream through the dispatcher.
+type Server struct {
+	handler
+	config        *ServerConfig
+	policyManager policy.Manager
+}
+
+// NewServer creates a new DNS inbound based on the given config.
+func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, newError("no space in context")
+	}
+	s := &Server{
+		config: config,
+	}
+	space.OnInitialize(func() error {
+		s.dns = resolver.FromSpace(space)
+		if s.dns == nil {
+			return newError("DNS server is not found in the space")
+		}
+		s.policyManager = policy.FromSpace(space)
+		if s.policyManager == nil {
+			return newError("Policy not found in space.")
+		}
+		return nil
+	})
+	return s, nil
+}
+
+// Network implements proxy.Inbound.
+func (*Server) Network() net.NetworkList {
+	return net.NetworkList{
+		Network: []net.Network{net.Network_TCP, net.Network_UDP},
+	}
+}
+
+// Process implements proxy.Inbound.
+func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher dispatcher.Interface) error {
+	log.Trace(newError("processing DNS queries from: ", conn.RemoteAddr()).AtDebug())
+
+	original, _ := proxy.OriginalTargetFromContext(ctx)
+	upstream := upstreamOf(s.config.Server, original, network)
+
+	p := s.policyManager.GetPolicy(s.config.UserLevel)
+	ctx = protocol.ContextWithUser(ctx, &protocol.User{
+		Level: s.config.UserLevel,
+	})
+	idle := p.Timeout.ConnectionIdle.DurationOr(time.Minute * 2)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
+
+	dial := func(ctx context.Context, dest net.Destination) (gonet.Conn, error) {
+		stream, err := dispatcher.Dispatch(ctx, dest)
+		if err != nil {
+			return nil, newError("failed to dispatch to ", dest).Base(err)
+		}
+		return outbound.NewConnection(stream), nil
+	}
+
+	var reader messageReader
+	var writer messageWriter
+	if network == net.Network_TCP {
+		reader, writer = &streamReader{conn}, &streamWriter{conn}
+	} else {
+		reader, writer = &packetReader{conn, buf.Size}, &packetWriter{conn}
+	}
+
+	done := signal.ExecuteAsync(func() error {
+		return s.serve(ctx, reader, writer, upstream, dial, timer)
+	})
+	if err := signal.ErrorOrFinish1(ctx, done); err != nil {
+		return newError("connection ends").Base(err)
+	}
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewServer(ctx, config.(*ServerConfig))
+	}))
+}
@@ -0,0 +1,158 @@
+package dns_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/dns/resolver"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	v2net "v2ray.com/core/common/net"
+	. "v2ray.com/core/proxy/dns"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/transport/ray"
+)
+
+// resolveDNS is a staticDNS that fails to resolve domains not in it, as if no name server answers.
+type resolveDNS struct {
+	staticDNS
+}
+
+func (s resolveDNS) Resolve(ctx context.Context, domain string) ([]net.IP, error) {
+	if domain == "nxdomain.v2ray.com" {
+		return nil, resolver.ErrNotFound
+	}
+	if ips := s.staticDNS[domain]; len(ips) > 0 {
+		return ips, nil
+	}
+	return nil, errors.New("no name server answers")
+}
+
+// directDispatcher connects to destinations directly.
+type directDispatcher struct{}
+
+func (directDispatcher) Dispatch(ctx context.Context, dest v2net.Destination) (ray.InboundRay, error) {
+	conn, err := net.Dial(dest.Network.SystemString(), dest.NetAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	stream := ray.NewRay(ctx)
+	go func() {
+		buf.Copy(stream.OutboundInput(), buf.NewWriter(conn))
+		conn.Close()
+	}()
+	go func() {
+		buf.Copy(buf.NewReader(conn), stream.OutboundOutput())
+		stream.OutboundOutput().Close()
+	}()
+	return stream, nil
+}
+
+func newServer(assert *assert.Assert, upstream v2net.Destination) (context.Context, *Server) {
+	space := app.NewSpace()
+	assert.Error(space.AddApplication(resolveDNS{staticDNS{
+		"v2ray.com": {net.ParseIP("127.0.0.2"), net.ParseIP("::2")},
+	}})).IsNil()
+	policyManager, err := policy.NewDefaultManager(context.Background(), &policy.Config{})
+	assert.Error(err).IsNil()
+	assert.Error(space.AddApplication(policyManager)).IsNil()
+
+	ctx := app.ContextWithSpace(context.Background(), space)
+	server, err := NewServer(ctx, &ServerConfig{
+		Server: &v2net.Endpoint{
+			Network: v2net.Network_UDP,
+			Address: v2net.NewIPOrDomain(upstream.Address),
+			Port:    uint32(upstream.Port),
+		},
+	})
+	assert.Error(err).IsNil()
+	assert.Error(space.Initialize()).IsNil()
+	return ctx, server
+}
+
+// exchange sends the query to the server and reads its response, over TCP framing if stream is true.
+func exchange(assert *assert.Assert, conn net.Conn, stream bool, name string, qtype uint16) *dns.Msg {
+	msg := new(dns.Msg).SetQuestion(name, qtype)
+	payload, err := msg.Pack()
+	assert.Error(err).IsNil()
+
+	var b []byte
+	if stream {
+		frame := append([]byte{byte(len(payload) >> 8), byte(len(payload))}, payload...)
+		_, err = conn.Write(frame)
+		assert.Error(err).IsNil()
+		var length [2]byte
+		_, err = io.ReadFull(conn, length[:])
+		assert.Error(err).IsNil()
+		b = make([]byte, int(length[0])<<8|int(length[1]))
+		_, err = io.ReadFull(conn, b)
+		assert.Error(err).IsNil()
+	} else {
+		_, err = conn.Write(payload)
+		assert.Error(err).IsNil()
+		b = make([]byte, buf.Size)
+		n, err := conn.Read(b)
+		assert.Error(err).IsNil()
+		b = b[:n]
+	}
+
+	response := new(dns.Msg)
+	assert.Error(response.Unpack(b)).IsNil()
+	assert.Uint16(response.Id).Equals(msg.Id)
+	return response
+}
+
+func testServer(t *testing.T, network v2net.Network) {
+	assert := assert.On(t)
+
+	upstream, dest := startUpstream(assert)
+	defer upstream.Shutdown()
+
+	ctx, server := newServer(assert, dest)
+
+	client, conn := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Process(ctx, network, conn, directDispatcher{})
+	}()
+
+	stream := network == v2net.Network_TCP
+	response := exchange(assert, client, stream, "v2ray.com.", dns.TypeA)
+	assert.Int(response.Rcode).Equals(dns.RcodeSuccess)
+	assert.Int(len(response.Answer)).Equals(1)
+	assert.String(response.Answer[0].(*dns.A).A.String()).Equals("127.0.0.2")
+
+	response = exchange(assert, client, stream, "v2ray.com.", dns.TypeAAAA)
+	assert.Int(response.Rcode).Equals(dns.RcodeSuccess)
+	assert.Int(len(response.Answer)).Equals(1)
+	assert.String(response.Answer[0].(*dns.AAAA).AAAA.String()).Equals("::2")
+
+	response = exchange(assert, client, stream, "nxdomain.v2ray.com.", dns.TypeA)
+	assert.Int(response.Rcode).Equals(dns.RcodeNameError)
+
+	response = exchange(assert, client, stream, "servfail.v2ray.com.", dns.TypeA)
+	assert.Int(response.Rcode).Equals(dns.RcodeServerFailure)
+
+	// Queries other than A and AAAA are forwarded upstream through the dispatcher.
+	response = exchange(assert, client, stream, "v2ray.com.", dns.TypeTXT)
+	assert.Int(response.Rcode).Equals(dns.RcodeSuccess)
+	assert.Int(len(response.Answer)).Equals(1)
+	assert.String(response.Answer[0].(*dns.TXT).Txt[0]).Equals("upstream")
+
+	client.Close()
+	assert.Error(<-done).IsNil()
+}
+
+func TestServerUDP(t *testing.T) {
+	testServer(t, v2net.Network_UDP)
+}
+
+func TestServerTCP(t *testing.T) {
+	testServer(t, v2net.Network_TCP)
+}
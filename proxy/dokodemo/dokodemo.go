@@ -10,18 +10,21 @@ import (
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport/internet"
 )
 
 type DokodemoDoor struct {
-	config  *Config
-	address net.Address
-	port    net.Port
+	config        *Config
+	address       net.Address
+	port          net.Port
+	policyManager policy.Manager
 }
 
 func New(ctx context.Context, config *Config) (*DokodemoDoor, error) {
@@ -37,9 +40,25 @@ func New(ctx context.Context, config *Config) (*DokodemoDoor, error) {
 		address: config.GetPredefinedAddress(),
 		port:    net.Port(config.Port),
 	}
+	space.OnInitialize(func() error {
+		pm := policy.FromSpace(space)
+		if pm == nil {
+			return newError("Policy not found in space.")
+		}
+		d.policyManager = pm
+		return nil
+	})
 	return d, nil
 }
 
+func (d *DokodemoDoor) policy() *policy.Policy {
+	p := d.policyManager.GetPolicy(d.config.UserLevel)
+	if d.config.Timeout > 0 {
+		p = p.WithConnectionIdle(d.config.Timeout)
+	}
+	return p
+}
+
 func (d *DokodemoDoor) Network() net.NetworkList {
 	return *(d.config.NetworkList)
 }
@@ -60,11 +79,12 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 		return newError("unable to get destination")
 	}
 
-	timeout := time.Second * time.Duration(d.config.Timeout)
-	if timeout == 0 {
-		timeout = time.Minute * 2
-	}
-	ctx, timer := signal.CancelAfterInactivity(ctx, timeout)
+	p := d.policy()
+	ctx = protocol.ContextWithUser(ctx, &protocol.User{
+		Level: d.config.UserLevel,
+	})
+	idle := p.Timeout.ConnectionIdle.DurationOr(time.Minute * 2)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
 
 	inboundRay, err := dispatcher.Dispatch(ctx, dest)
 	if err != nil {
@@ -80,6 +100,7 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 			return newError("failed to transport request").Base(err)
 		}
 
+		timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -94,6 +115,8 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 		if err := buf.Copy(inboundRay.InboundOutput(), writer, buf.UpdateActivity(timer)); err != nil {
 			return newError("failed to transport response").Base(err)
 		}
+
+		timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
 		return nil
 	})
 
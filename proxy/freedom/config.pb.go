@@ -3,7 +3,7 @@ package freedom
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
-import v2ray_core_common_protocol1 "v2ray.com/core/common/protocol"
+import v2ray_core_common_protocol "v2ray.com/core/common/protocol"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -38,7 +38,7 @@ func (x Config_DomainStrategy) String() string {
 func (Config_DomainStrategy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
 
 type DestinationOverride struct {
-	Server *v2ray_core_common_protocol1.ServerEndpoint `protobuf:"bytes,1,opt,name=server" json:"server,omitempty"`
+	Server *v2ray_core_common_protocol.ServerEndpoint `protobuf:"bytes,1,opt,name=server" json:"server,omitempty"`
 }
 
 func (m *DestinationOverride) Reset()                    { *m = DestinationOverride{} }
@@ -46,7 +46,7 @@ func (m *DestinationOverride) String() string            { return proto.CompactT
 func (*DestinationOverride) ProtoMessage()               {}
 func (*DestinationOverride) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
-func (m *DestinationOverride) GetServer() *v2ray_core_common_protocol1.ServerEndpoint {
+func (m *DestinationOverride) GetServer() *v2ray_core_common_protocol.ServerEndpoint {
 	if m != nil {
 		return m.Server
 	}
@@ -54,9 +54,11 @@ func (m *DestinationOverride) GetServer() *v2ray_core_common_protocol1.ServerEnd
 }
 
 type Config struct {
-	DomainStrategy      Config_DomainStrategy `protobuf:"varint,1,opt,name=domain_strategy,json=domainStrategy,enum=v2ray.core.proxy.freedom.Config_DomainStrategy" json:"domain_strategy,omitempty"`
-	Timeout             uint32                `protobuf:"varint,2,opt,name=timeout" json:"timeout,omitempty"`
-	DestinationOverride *DestinationOverride  `protobuf:"bytes,3,opt,name=destination_override,json=destinationOverride" json:"destination_override,omitempty"`
+	DomainStrategy Config_DomainStrategy `protobuf:"varint,1,opt,name=domain_strategy,json=domainStrategy,enum=v2ray.core.proxy.freedom.Config_DomainStrategy" json:"domain_strategy,omitempty"`
+	// Deprecated. Use connection_idle in policy of user_level instead.
+	Timeout             uint32               `protobuf:"varint,2,opt,name=timeout" json:"timeout,omitempty"`
+	DestinationOverride *DestinationOverride `protobuf:"bytes,3,opt,name=destination_override,json=destinationOverride" json:"destination_override,omitempty"`
+	UserLevel           uint32               `protobuf:"varint,4,opt,name=user_level,json=userLevel" json:"user_level,omitempty"`
 }
 
 func (m *Config) Reset()                    { *m = Config{} }
@@ -85,6 +87,13 @@ func (m *Config) GetDestinationOverride() *DestinationOverride {
 	return nil
 }
 
+func (m *Config) GetUserLevel() uint32 {
+	if m != nil {
+		return m.UserLevel
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*DestinationOverride)(nil), "v2ray.core.proxy.freedom.DestinationOverride")
 	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.freedom.Config")
@@ -94,25 +103,27 @@ func init() {
 func init() { proto.RegisterFile("v2ray.com/core/proxy/freedom/config.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 318 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0xcf, 0x4a, 0xc3, 0x40,
-	0x10, 0xc6, 0x4d, 0xc5, 0x14, 0x47, 0xac, 0x65, 0xeb, 0x21, 0x88, 0x87, 0xd2, 0x8b, 0x55, 0x70,
-	0x23, 0xf1, 0x09, 0xec, 0x1f, 0xa1, 0x27, 0x4b, 0x82, 0xa2, 0x5e, 0x62, 0xcc, 0x4e, 0xcb, 0x82,
-	0xd9, 0x09, 0x9b, 0xb5, 0x98, 0x27, 0xf0, 0x5d, 0x7c, 0x4a, 0x71, 0x93, 0xa2, 0x55, 0x73, 0xdb,
-	0x99, 0xfd, 0x7d, 0xdf, 0xcc, 0x37, 0x70, 0xba, 0x0a, 0x74, 0x52, 0xf2, 0x94, 0x32, 0x3f, 0x25,
-	0x8d, 0x7e, 0xae, 0xe9, 0xad, 0xf4, 0x17, 0x1a, 0x51, 0xd8, 0x96, 0x5a, 0xc8, 0x25, 0xcf, 0x35,
-	0x19, 0x62, 0xde, 0x1a, 0xd5, 0xc8, 0x2d, 0xc6, 0x6b, 0xec, 0xe8, 0xe2, 0x97, 0x49, 0x4a, 0x59,
-	0x46, 0xca, 0xb7, 0xb2, 0x94, 0x5e, 0xfc, 0x02, 0xf5, 0x0a, 0x75, 0x5c, 0xe4, 0x98, 0x56, 0x5e,
-	0x83, 0x07, 0xe8, 0x4d, 0xb0, 0x30, 0x52, 0x25, 0x46, 0x92, 0xba, 0x59, 0xa1, 0xd6, 0x52, 0x20,
-	0x1b, 0x81, 0x5b, 0xb1, 0x9e, 0xd3, 0x77, 0x86, 0x7b, 0xc1, 0x19, 0xff, 0x31, 0xb3, 0x72, 0xe5,
-	0x6b, 0x57, 0x1e, 0x59, 0x72, 0xaa, 0x44, 0x4e, 0x52, 0x99, 0xb0, 0x56, 0x0e, 0xde, 0x5b, 0xe0,
-	0x8e, 0xed, 0xde, 0xec, 0x1e, 0x0e, 0x04, 0x65, 0x89, 0x54, 0x71, 0x61, 0x74, 0x62, 0x70, 0x59,
-	0x5a, 0xdf, 0x4e, 0xe0, 0xf3, 0xa6, 0x2c, 0xbc, 0x92, 0xf2, 0x89, 0xd5, 0x45, 0xb5, 0x2c, 0xec,
-	0x88, 0x8d, 0x9a, 0x79, 0xd0, 0x36, 0x32, 0x43, 0x7a, 0x35, 0x5e, 0xab, 0xef, 0x0c, 0xf7, 0xc3,
-	0x75, 0xc9, 0x9e, 0xe0, 0x50, 0x7c, 0x27, 0x8b, 0xa9, 0x8e, 0xe6, 0x6d, 0xdb, 0x40, 0xe7, 0xcd,
-	0x83, 0xff, 0xb9, 0x47, 0xd8, 0x13, 0x7f, 0x9b, 0x83, 0x13, 0xe8, 0x6c, 0x6e, 0xc7, 0x76, 0x61,
-	0xe7, 0x2a, 0x8a, 0x67, 0x51, 0x77, 0x8b, 0x01, 0xb8, 0xb7, 0xd1, 0x34, 0x9e, 0xcd, 0xbb, 0xce,
-	0x68, 0x02, 0xc7, 0x29, 0x65, 0x8d, 0x13, 0xe7, 0xce, 0x63, 0xbb, 0x7e, 0x7e, 0xb4, 0xbc, 0xbb,
-	0x20, 0x4c, 0x4a, 0x3e, 0xfe, 0xa2, 0xe6, 0x96, 0xba, 0xae, 0xbe, 0x9e, 0x5d, 0x7b, 0xf0, 0xcb,
-	0xcf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x45, 0xb3, 0x84, 0x2e, 0x2a, 0x02, 0x00, 0x00,
+	// 338 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xdf, 0x4a, 0xeb, 0x40,
+	0x10, 0xc6, 0x4f, 0x7a, 0xce, 0x49, 0xe9, 0x1c, 0x4e, 0x2d, 0xa9, 0x17, 0x41, 0x14, 0x4a, 0x6f,
+	0xac, 0x82, 0x1b, 0x89, 0x4f, 0x60, 0xff, 0x08, 0x05, 0xc1, 0x92, 0xa0, 0xa8, 0x37, 0x31, 0x66,
+	0xa7, 0x65, 0xa1, 0xd9, 0x09, 0x9b, 0x6d, 0x30, 0x2f, 0xe4, 0x85, 0x4f, 0x29, 0xdd, 0xa4, 0x68,
+	0xa5, 0xbd, 0xcb, 0x4c, 0x7e, 0xdf, 0x37, 0xf3, 0xcd, 0xc2, 0x59, 0xe1, 0xab, 0xb8, 0x64, 0x09,
+	0xa5, 0x5e, 0x42, 0x0a, 0xbd, 0x4c, 0xd1, 0x5b, 0xe9, 0xcd, 0x15, 0x22, 0x37, 0x2d, 0x39, 0x17,
+	0x0b, 0x96, 0x29, 0xd2, 0xe4, 0xb8, 0x1b, 0x54, 0x21, 0x33, 0x18, 0xab, 0xb1, 0xa3, 0xcb, 0x1f,
+	0x26, 0x09, 0xa5, 0x29, 0x49, 0xcf, 0xc8, 0x12, 0x5a, 0x7a, 0x39, 0xaa, 0x02, 0x55, 0x94, 0x67,
+	0x98, 0x54, 0x5e, 0xfd, 0x27, 0xe8, 0x8e, 0x31, 0xd7, 0x42, 0xc6, 0x5a, 0x90, 0xbc, 0x2b, 0x50,
+	0x29, 0xc1, 0xd1, 0x19, 0x82, 0x5d, 0xb1, 0xae, 0xd5, 0xb3, 0x06, 0xff, 0xfc, 0x73, 0xf6, 0x6d,
+	0x66, 0xe5, 0xca, 0x36, 0xae, 0x2c, 0x34, 0xe4, 0x44, 0xf2, 0x8c, 0x84, 0xd4, 0x41, 0xad, 0xec,
+	0xbf, 0x37, 0xc0, 0x1e, 0x99, 0xbd, 0x9d, 0x47, 0x38, 0xe0, 0x94, 0xc6, 0x42, 0x46, 0xb9, 0x56,
+	0xb1, 0xc6, 0x45, 0x69, 0x7c, 0xdb, 0xbe, 0xc7, 0xf6, 0x65, 0x61, 0x95, 0x94, 0x8d, 0x8d, 0x2e,
+	0xac, 0x65, 0x41, 0x9b, 0x6f, 0xd5, 0x8e, 0x0b, 0x4d, 0x2d, 0x52, 0xa4, 0x95, 0x76, 0x1b, 0x3d,
+	0x6b, 0xf0, 0x3f, 0xd8, 0x94, 0xce, 0x0b, 0x1c, 0xf2, 0xaf, 0x64, 0x11, 0xd5, 0xd1, 0xdc, 0xdf,
+	0x26, 0xd0, 0xc5, 0xfe, 0xc1, 0x3b, 0xee, 0x11, 0x74, 0xf9, 0x8e, 0x23, 0x9d, 0x00, 0xac, 0x72,
+	0x54, 0xd1, 0x12, 0x0b, 0x5c, 0xba, 0x7f, 0xcc, 0xf8, 0xd6, 0xba, 0x73, 0xbb, 0x6e, 0xf4, 0x4f,
+	0xa1, 0xbd, 0xbd, 0xbc, 0xd3, 0x82, 0xbf, 0xd7, 0x61, 0x34, 0x0d, 0x3b, 0xbf, 0x1c, 0x00, 0xfb,
+	0x3e, 0x9c, 0x44, 0xd3, 0x59, 0xc7, 0x1a, 0x8e, 0xe1, 0x38, 0xa1, 0x74, 0xef, 0x42, 0x33, 0xeb,
+	0xb9, 0x59, 0x7f, 0x7e, 0x34, 0xdc, 0x07, 0x3f, 0x88, 0x4b, 0x36, 0x5a, 0x53, 0x33, 0x43, 0xdd,
+	0x54, 0xbf, 0x5e, 0x6d, 0xf3, 0x1e, 0x57, 0x9f, 0x03, 0x00, 0x5f, 0x81, 0xbd, 0x32, 0x49, 0x02,
+	0x00, 0x00,
 }
@@ -8,8 +8,9 @@ import (
 	"time"
 
 	"v2ray.com/core/app"
-	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/dns/resolver"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/dice"
@@ -24,8 +25,10 @@ import (
 type Handler struct {
 	domainStrategy Config_DomainStrategy
 	timeout        uint32
-	dns            dns.Server
+	userLevel      uint32
+	dns            resolver.Server
 	destOverride   *DestinationOverride
+	policyManager  policy.Manager
 }
 
 func New(ctx context.Context, config *Config) (*Handler, error) {
@@ -36,11 +39,16 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 	f := &Handler{
 		domainStrategy: config.DomainStrategy,
 		timeout:        config.Timeout,
+		userLevel:      config.UserLevel,
 		destOverride:   config.DestinationOverride,
 	}
 	space.OnInitialize(func() error {
+		f.policyManager = policy.FromSpace(space)
+		if f.policyManager == nil {
+			return newError("Policy not found in space.")
+		}
 		if config.DomainStrategy == Config_USE_IP {
-			f.dns = dns.FromSpace(space)
+			f.dns = resolver.FromSpace(space)
 			if f.dns == nil {
 				return newError("DNS server is not found in the space")
 			}
@@ -50,12 +58,12 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 	return f, nil
 }
 
-func (v *Handler) ResolveIP(destination net.Destination) net.Destination {
+func (v *Handler) ResolveIP(ctx context.Context, destination net.Destination) net.Destination {
 	if !destination.Address.Family().IsDomain() {
 		return destination
 	}
 
-	ips := v.dns.Get(destination.Address.Domain())
+	ips := resolver.LookupIP(ctx, v.dns, destination.Address.Domain())
 	if len(ips) == 0 {
 		log.Trace(newError("DNS returns nil answer. Keep domain as is."))
 		return destination
@@ -89,7 +97,7 @@ func (v *Handler) Process(ctx context.Context, outboundRay ray.OutboundRay, dial
 
 	var conn internet.Connection
 	if v.domainStrategy == Config_USE_IP && destination.Address.Family().IsDomain() {
-		destination = v.ResolveIP(destination)
+		destination = v.ResolveIP(ctx, destination)
 	}
 
 	err := retry.ExponentialBackoff(5, 100).On(func() error {
@@ -105,11 +113,12 @@ func (v *Handler) Process(ctx context.Context, outboundRay ray.OutboundRay, dial
 	}
 	defer conn.Close()
 
-	timeout := time.Second * time.Duration(v.timeout)
-	if timeout == 0 {
-		timeout = time.Minute * 5
+	p := v.policyManager.GetPolicy(v.userLevel)
+	if v.timeout > 0 {
+		p = p.WithConnectionIdle(v.timeout)
 	}
-	ctx, timer := signal.CancelAfterInactivity(ctx, timeout)
+	idle := p.Timeout.ConnectionIdle.DurationOr(time.Minute * 5)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
 
 	requestDone := signal.ExecuteAsync(func() error {
 		var writer buf.Writer
@@ -121,6 +130,7 @@ func (v *Handler) Process(ctx context.Context, outboundRay ray.OutboundRay, dial
 		if err := buf.Copy(input, writer, buf.UpdateActivity(timer)); err != nil {
 			return newError("failed to process request").Base(err)
 		}
+		timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -131,6 +141,7 @@ func (v *Handler) Process(ctx context.Context, outboundRay ray.OutboundRay, dial
 		if err := buf.Copy(v2reader, output, buf.UpdateActivity(timer)); err != nil {
 			return newError("failed to process response").Base(err)
 		}
+		timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -14,17 +14,20 @@ import (
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/errors"
 	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/transport/internet"
 )
 
 // Server is a HTTP proxy server.
 type Server struct {
-	config *ServerConfig
+	config        *ServerConfig
+	policyManager policy.Manager
 }
 
 // NewServer creates a new HTTP inbound handler.
@@ -36,9 +39,25 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 	s := &Server{
 		config: config,
 	}
+	space.OnInitialize(func() error {
+		pm := policy.FromSpace(space)
+		if pm == nil {
+			return newError("Policy not found in space.")
+		}
+		s.policyManager = pm
+		return nil
+	})
 	return s, nil
 }
 
+func (s *Server) policy() *policy.Policy {
+	p := s.policyManager.GetPolicy(s.config.UserLevel)
+	if s.config.Timeout > 0 {
+		p = p.WithConnectionIdle(s.config.Timeout)
+	}
+	return p
+}
+
 func (*Server) Network() v2net.NetworkList {
 	return v2net.NetworkList{
 		Network: []v2net.Network{v2net.Network_TCP},
@@ -74,10 +93,13 @@ func isTimeout(err error) bool {
 }
 
 func (s *Server) Process(ctx context.Context, network v2net.Network, conn internet.Connection, dispatcher dispatcher.Interface) error {
+	ctx = protocol.ContextWithUser(ctx, &protocol.User{
+		Level: s.config.UserLevel,
+	})
 	reader := bufio.NewReaderSize(conn, 2048)
 
 Start:
-	conn.SetReadDeadline(time.Now().Add(time.Second * 16))
+	conn.SetReadDeadline(time.Now().Add(s.policy().Timeout.Handshake.DurationOr(time.Second * 16)))
 
 	request, err := http.ReadRequest(reader)
 	if err != nil {
@@ -127,11 +149,9 @@ func (s *Server) handleConnect(ctx context.Context, request *http.Request, reade
 		return newError("failed to write back OK response").Base(err)
 	}
 
-	timeout := time.Second * time.Duration(s.config.Timeout)
-	if timeout == 0 {
-		timeout = time.Minute * 2
-	}
-	ctx, timer := signal.CancelAfterInactivity(ctx, timeout)
+	p := s.policy()
+	idle := p.Timeout.ConnectionIdle.DurationOr(time.Minute * 2)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
 	ray, err := dispatcher.Dispatch(ctx, dest)
 	if err != nil {
 		return err
@@ -144,6 +164,7 @@ func (s *Server) handleConnect(ctx context.Context, request *http.Request, reade
 		if err := buf.Copy(v2reader, ray.InboundInput(), buf.UpdateActivity(timer)); err != nil {
 			return err
 		}
+		timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -152,6 +173,7 @@ func (s *Server) handleConnect(ctx context.Context, request *http.Request, reade
 		if err := buf.Copy(ray.InboundOutput(), v2writer, buf.UpdateActivity(timer)); err != nil {
 			return err
 		}
+		timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -5,7 +5,9 @@ import (
 	"runtime"
 	"time"
 
+	"v2ray.com/core/app"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
@@ -19,11 +21,17 @@ import (
 
 // Client is a inbound handler for Shadowsocks protocol
 type Client struct {
-	serverPicker protocol.ServerPicker
+	serverPicker  protocol.ServerPicker
+	policyManager policy.Manager
 }
 
 // NewClient create a new Shadowsocks client.
 func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, newError("no space in context")
+	}
+
 	serverList := protocol.NewServerList()
 	for _, rec := range config.Server {
 		serverList.AddServer(protocol.NewServerSpecFromPB(*rec))
@@ -35,6 +43,15 @@ func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
 		serverPicker: protocol.NewRoundRobinServerPicker(serverList),
 	}
 
+	space.OnInitialize(func() error {
+		pm := policy.FromSpace(space)
+		if pm == nil {
+			return newError("Policy not found in space.")
+		}
+		client.policyManager = pm
+		return nil
+	})
+
 	return client, nil
 }
 
@@ -91,7 +108,9 @@ func (v *Client) Process(ctx context.Context, outboundRay ray.OutboundRay, diale
 		request.Option |= RequestOptionOneTimeAuth
 	}
 
-	ctx, timer := signal.CancelAfterInactivity(ctx, time.Minute*2)
+	p := v.policyManager.GetPolicy(user.Level)
+	idle := p.Timeout.ConnectionIdle.DurationOr(time.Minute * 2)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
 
 	if request.Command == protocol.RequestCommandTCP {
 		bufferedWriter := buf.NewBufferedWriter(conn)
@@ -108,6 +127,7 @@ func (v *Client) Process(ctx context.Context, outboundRay ray.OutboundRay, diale
 			if err := buf.Copy(outboundRay.OutboundInput(), bodyWriter, buf.UpdateActivity(timer)); err != nil {
 				return err
 			}
+			timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
 			return nil
 		})
 
@@ -123,6 +143,7 @@ func (v *Client) Process(ctx context.Context, outboundRay ray.OutboundRay, diale
 				return err
 			}
 
+			timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
 			return nil
 		})
 
@@ -144,6 +165,7 @@ func (v *Client) Process(ctx context.Context, outboundRay ray.OutboundRay, diale
 			if err := buf.Copy(outboundRay.OutboundInput(), writer, buf.UpdateActivity(timer)); err != nil {
 				return newError("failed to transport all UDP request").Base(err)
 			}
+			timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
 			return nil
 		})
 
@@ -158,6 +180,7 @@ func (v *Client) Process(ctx context.Context, outboundRay ray.OutboundRay, diale
 			if err := buf.Copy(reader, outboundRay.OutboundOutput(), buf.UpdateActivity(timer)); err != nil {
 				return newError("failed to transport all UDP response").Base(err)
 			}
+			timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
 			return nil
 		})
 
@@ -8,6 +8,7 @@ import (
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
@@ -19,9 +20,10 @@ import (
 )
 
 type Server struct {
-	config  *ServerConfig
-	user    *protocol.User
-	account *ShadowsocksAccount
+	config        *ServerConfig
+	user          *protocol.User
+	account       *ShadowsocksAccount
+	policyManager policy.Manager
 }
 
 // NewServer create a new Shadowsocks server.
@@ -46,6 +48,15 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		account: account,
 	}
 
+	space.OnInitialize(func() error {
+		pm := policy.FromSpace(space)
+		if pm == nil {
+			return newError("Policy not found in space.")
+		}
+		s.policyManager = pm
+		return nil
+	})
+
 	return s, nil
 }
 
@@ -71,6 +82,9 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 }
 
 func (v *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection, dispatcher dispatcher.Interface) error {
+	p := v.policyManager.GetPolicy(v.user.Level)
+	idle := p.Timeout.ConnectionIdle.DurationOr(v.user.GetSettings().PayloadTimeout)
+	sessionCtx, timer := signal.CancelAfterInactivity(ctx, idle)
 	udpServer := udp.NewDispatcher(dispatcher)
 
 	reader := buf.NewReader(conn)
@@ -109,9 +123,17 @@ func (v *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 			}
 			log.Trace(newError("tunnelling request to ", dest))
 
-			ctx = protocol.ContextWithUser(ctx, request.User)
-			udpServer.Dispatch(ctx, dest, data, func(payload *buf.Buffer) {
+			if sessionCtx.Err() != nil {
+				// The session is idle for too long, and its connections are closed. Start a new one.
+				sessionCtx, timer = signal.CancelAfterInactivity(ctx, idle)
+				udpServer = udp.NewDispatcher(dispatcher)
+			}
+			timer.Update()
+			sessionTimer := timer
+
+			udpServer.Dispatch(protocol.ContextWithUser(sessionCtx, request.User), dest, data, func(payload *buf.Buffer) {
 				defer payload.Release()
+				sessionTimer.Update()
 
 				data, err := EncodeUDPPacket(request, payload.Bytes())
 				if err != nil {
@@ -129,7 +151,8 @@ func (v *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 }
 
 func (s *Server) handleConnection(ctx context.Context, conn internet.Connection, dispatcher dispatcher.Interface) error {
-	conn.SetReadDeadline(time.Now().Add(time.Second * 8))
+	p := s.policyManager.GetPolicy(s.user.Level)
+	conn.SetReadDeadline(time.Now().Add(p.Timeout.Handshake.DurationOr(time.Second * 8)))
 	bufferedReader := buf.NewBufferedReader(conn)
 	request, bodyReader, err := ReadTCPSession(s.user, bufferedReader)
 	if err != nil {
@@ -146,8 +169,8 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 
 	ctx = protocol.ContextWithUser(ctx, request.User)
 
-	userSettings := s.user.GetSettings()
-	ctx, timer := signal.CancelAfterInactivity(ctx, userSettings.PayloadTimeout)
+	idle := p.Timeout.ConnectionIdle.DurationOr(s.user.GetSettings().PayloadTimeout)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
 	ray, err := dispatcher.Dispatch(ctx, dest)
 	if err != nil {
 		return err
@@ -177,6 +200,7 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 			return newError("failed to transport all TCP response").Base(err)
 		}
 
+		timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -186,6 +210,7 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 		if err := buf.Copy(bodyReader, ray.InboundInput(), buf.UpdateActivity(timer)); err != nil {
 			return newError("failed to transport all TCP request").Base(err)
 		}
+		timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -5,6 +5,8 @@ import (
 	"runtime"
 	"time"
 
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
@@ -18,11 +20,17 @@ import (
 
 // Client is a Socks5 client.
 type Client struct {
-	serverPicker protocol.ServerPicker
+	serverPicker  protocol.ServerPicker
+	policyManager policy.Manager
 }
 
 // NewClient create a new Socks5 client based on the given config.
 func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	space := app.SpaceFromContext(ctx)
+	if space == nil {
+		return nil, newError("no space in context")
+	}
+
 	serverList := protocol.NewServerList()
 	for _, rec := range config.Server {
 		serverList.AddServer(protocol.NewServerSpecFromPB(*rec))
@@ -31,9 +39,18 @@ func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
 		return nil, newError("0 target server")
 	}
 
-	return &Client{
+	c := &Client{
 		serverPicker: protocol.NewRoundRobinServerPicker(serverList),
-	}, nil
+	}
+	space.OnInitialize(func() error {
+		pm := policy.FromSpace(space)
+		if pm == nil {
+			return newError("Policy not found in space.")
+		}
+		c.policyManager = pm
+		return nil
+	})
+	return c, nil
 }
 
 // Process implements proxy.Outbound.Process.
@@ -74,27 +91,39 @@ func (c *Client) Process(ctx context.Context, ray ray.OutboundRay, dialer proxy.
 		request.Command = protocol.RequestCommandUDP
 	}
 
+	var level uint32
 	user := server.PickUser()
 	if user != nil {
 		request.User = user
+		level = user.Level
 	}
+	p := c.policyManager.GetPolicy(level)
 
 	udpRequest, err := ClientHandshake(request, conn, conn)
 	if err != nil {
 		return newError("failed to establish connection to server").AtWarning().Base(err)
 	}
 
-	ctx, timer := signal.CancelAfterInactivity(ctx, time.Minute*2)
+	idle := p.Timeout.ConnectionIdle.DurationOr(time.Minute * 2)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
 
 	var requestFunc func() error
 	var responseFunc func() error
 	if request.Command == protocol.RequestCommandTCP {
 		requestFunc = func() error {
-			return buf.Copy(ray.OutboundInput(), buf.NewWriter(conn), buf.UpdateActivity(timer))
+			if err := buf.Copy(ray.OutboundInput(), buf.NewWriter(conn), buf.UpdateActivity(timer)); err != nil {
+				return err
+			}
+			timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
+			return nil
 		}
 		responseFunc = func() error {
 			defer ray.OutboundOutput().Close()
-			return buf.Copy(buf.NewReader(conn), ray.OutboundOutput(), buf.UpdateActivity(timer))
+			if err := buf.Copy(buf.NewReader(conn), ray.OutboundOutput(), buf.UpdateActivity(timer)); err != nil {
+				return err
+			}
+			timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
+			return nil
 		}
 	} else if request.Command == protocol.RequestCommandUDP {
 		udpConn, err := dialer.Dial(ctx, udpRequest.Destination())
@@ -103,12 +132,20 @@ func (c *Client) Process(ctx context.Context, ray ray.OutboundRay, dialer proxy.
 		}
 		defer udpConn.Close()
 		requestFunc = func() error {
-			return buf.Copy(ray.OutboundInput(), buf.NewSequentialWriter(NewUDPWriter(request, udpConn)), buf.UpdateActivity(timer))
+			if err := buf.Copy(ray.OutboundInput(), buf.NewSequentialWriter(NewUDPWriter(request, udpConn)), buf.UpdateActivity(timer)); err != nil {
+				return err
+			}
+			timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
+			return nil
 		}
 		responseFunc = func() error {
 			defer ray.OutboundOutput().Close()
 			reader := &UDPReader{reader: udpConn}
-			return buf.Copy(reader, ray.OutboundOutput(), buf.UpdateActivity(timer))
+			if err := buf.Copy(reader, ray.OutboundOutput(), buf.UpdateActivity(timer)); err != nil {
+				return err
+			}
+			timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
+			return nil
 		}
 	}
 
@@ -9,6 +9,7 @@ import (
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
@@ -21,7 +22,8 @@ import (
 
 // Server is a SOCKS 5 proxy server
 type Server struct {
-	config *ServerConfig
+	config        *ServerConfig
+	policyManager policy.Manager
 }
 
 // NewServer creates a new Server object.
@@ -33,9 +35,25 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 	s := &Server{
 		config: config,
 	}
+	space.OnInitialize(func() error {
+		pm := policy.FromSpace(space)
+		if pm == nil {
+			return newError("Policy not found in space.")
+		}
+		s.policyManager = pm
+		return nil
+	})
 	return s, nil
 }
 
+func (s *Server) policy() *policy.Policy {
+	p := s.policyManager.GetPolicy(s.config.UserLevel)
+	if s.config.Timeout > 0 {
+		p = p.WithConnectionIdle(s.config.Timeout)
+	}
+	return p
+}
+
 func (s *Server) Network() net.NetworkList {
 	list := net.NetworkList{
 		Network: []net.Network{net.Network_TCP},
@@ -58,7 +76,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 }
 
 func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispatcher dispatcher.Interface) error {
-	conn.SetReadDeadline(time.Now().Add(time.Second * 8))
+	conn.SetReadDeadline(time.Now().Add(s.policy().Timeout.Handshake.DurationOr(time.Second * 8)))
 	reader := buf.NewBufferedReader(conn)
 
 	inboundDest, ok := proxy.InboundEntryPointFromContext(ctx)
@@ -106,11 +124,12 @@ func (*Server) handleUDP() error {
 }
 
 func (v *Server) transport(ctx context.Context, reader io.Reader, writer io.Writer, dest net.Destination, dispatcher dispatcher.Interface) error {
-	timeout := time.Second * time.Duration(v.config.Timeout)
-	if timeout == 0 {
-		timeout = time.Minute * 2
-	}
-	ctx, timer := signal.CancelAfterInactivity(ctx, timeout)
+	p := v.policy()
+	ctx = protocol.ContextWithUser(ctx, &protocol.User{
+		Level: v.config.UserLevel,
+	})
+	idle := p.Timeout.ConnectionIdle.DurationOr(time.Minute * 2)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
 
 	ray, err := dispatcher.Dispatch(ctx, dest)
 	if err != nil {
@@ -127,6 +146,7 @@ func (v *Server) transport(ctx context.Context, reader io.Reader, writer io.Writ
 		if err := buf.Copy(v2reader, input, buf.UpdateActivity(timer)); err != nil {
 			return newError("failed to transport all TCP request").Base(err)
 		}
+		timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -135,6 +155,7 @@ func (v *Server) transport(ctx context.Context, reader io.Reader, writer io.Writ
 		if err := buf.Copy(output, v2writer, buf.UpdateActivity(timer)); err != nil {
 			return newError("failed to transport all TCP response").Base(err)
 		}
+		timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
 		return nil
 	})
 
@@ -150,6 +171,11 @@ func (v *Server) transport(ctx context.Context, reader io.Reader, writer io.Writ
 }
 
 func (v *Server) handleUDPPayload(ctx context.Context, conn internet.Connection, dispatcher dispatcher.Interface) error {
+	ctx = protocol.ContextWithUser(ctx, &protocol.User{
+		Level: v.config.UserLevel,
+	})
+	idle := v.policy().Timeout.ConnectionIdle.DurationOr(time.Minute * 2)
+	sessionCtx, timer := signal.CancelAfterInactivity(ctx, idle)
 	udpServer := udp.NewDispatcher(dispatcher)
 
 	if source, ok := proxy.SourceFromContext(ctx); ok {
@@ -180,10 +206,19 @@ func (v *Server) handleUDPPayload(ctx context.Context, conn internet.Connection,
 				log.Access(source, request.Destination, log.AccessAccepted, "")
 			}
 
+			if sessionCtx.Err() != nil {
+				// The session is idle for too long, and its connections are closed. Start a new one.
+				sessionCtx, timer = signal.CancelAfterInactivity(ctx, idle)
+				udpServer = udp.NewDispatcher(dispatcher)
+			}
+			timer.Update()
+			sessionTimer := timer
+
 			dataBuf := buf.New()
 			dataBuf.Append(data)
-			udpServer.Dispatch(ctx, request.Destination(), dataBuf, func(payload *buf.Buffer) {
+			udpServer.Dispatch(sessionCtx, request.Destination(), dataBuf, func(payload *buf.Buffer) {
 				defer payload.Release()
+				sessionTimer.Update()
 
 				log.Trace(newError("writing back UDP response with ", payload.Len(), " bytes").AtDebug())
 
@@ -12,6 +12,7 @@ import (
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
@@ -79,6 +80,7 @@ type Handler struct {
 	usersByEmail          *userByEmail
 	detours               *DetourConfig
 	sessionHistory        *encoding.SessionHistory
+	policyManager         policy.Manager
 }
 
 func New(ctx context.Context, config *Config) (*Handler, error) {
@@ -106,6 +108,10 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 		if handler.inboundHandlerManager == nil {
 			return newError("InboundHandlerManager is not found is space")
 		}
+		handler.policyManager = policy.FromSpace(space)
+		if handler.policyManager == nil {
+			return newError("Policy is not found in space")
+		}
 		return nil
 	})
 
@@ -174,7 +180,8 @@ func transferResponse(timer signal.ActivityTimer, session *encoding.ServerSessio
 
 // Process implements proxy.Inbound.Process().
 func (v *Handler) Process(ctx context.Context, network net.Network, connection internet.Connection, dispatcher dispatcher.Interface) error {
-	if err := connection.SetReadDeadline(time.Now().Add(time.Second * 8)); err != nil {
+	// User level is unknown before handshake, so handshake timeout of level 0 is used.
+	if err := connection.SetReadDeadline(time.Now().Add(v.policyManager.GetPolicy(0).Timeout.Handshake.DurationOr(time.Second * 8))); err != nil {
 		return err
 	}
 
@@ -201,11 +208,12 @@ func (v *Handler) Process(ctx context.Context, network net.Network, connection i
 
 	common.Must(connection.SetReadDeadline(time.Time{}))
 
-	userSettings := request.User.GetSettings()
+	p := v.policyManager.GetPolicy(request.User.Level)
 
 	ctx = protocol.ContextWithUser(ctx, request.User)
 
-	ctx, timer := signal.CancelAfterInactivity(ctx, userSettings.PayloadTimeout)
+	idle := p.Timeout.ConnectionIdle.DurationOr(request.User.GetSettings().PayloadTimeout)
+	ctx, timer := signal.CancelAfterInactivity(ctx, idle)
 	ray, err := dispatcher.Dispatch(ctx, request.Destination())
 	if err != nil {
 		return newError("failed to dispatch request to ", request.Destination()).Base(err)
@@ -217,7 +225,11 @@ func (v *Handler) Process(ctx context.Context, network net.Network, connection i
 	reader.SetBuffered(false)
 
 	requestDone := signal.ExecuteAsync(func() error {
-		return transferRequest(timer, session, request, reader, input)
+		if err := transferRequest(timer, session, request, reader, input); err != nil {
+			return err
+		}
+		timer.SetTimeout(p.Timeout.DownlinkOnly.DurationOr(idle))
+		return nil
 	})
 
 	responseDone := signal.ExecuteAsync(func() error {
@@ -227,7 +239,11 @@ func (v *Handler) Process(ctx context.Context, network net.Network, connection i
 		response := &protocol.ResponseHeader{
 			Command: v.generateCommand(ctx, request),
 		}
-		return transferResponse(timer, session, request, response, output, writer)
+		if err := transferResponse(timer, session, request, response, output, writer); err != nil {
+			return err
+		}
+		timer.SetTimeout(p.Timeout.UplinkOnly.DurationOr(idle))
+		return nil
 	})
 
 	if err := signal.ErrorOrFinish2(ctx, requestDone, responseDone); err != nil {
@@ -0,0 +1,414 @@
+package core
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/app"
+	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/proxyman"
+)
+
+// diffInbounds compares two lists of inbound handler configs. It returns the tags of handlers to be removed,
+// and the configs of handlers to be added. Unchanged handlers are left untouched.
+func diffInbounds(current []*proxyman.InboundHandlerConfig, next []*proxyman.InboundHandlerConfig) ([]string, []*proxyman.InboundHandlerConfig, error) {
+	tagged := make(map[string]*proxyman.InboundHandlerConfig)
+	var untagged []*proxyman.InboundHandlerConfig
+	for _, c := range current {
+		if len(c.Tag) > 0 {
+			tagged[c.Tag] = c
+		} else {
+			untagged = append(untagged, c)
+		}
+	}
+
+	var toRemove []string
+	var toAdd []*proxyman.InboundHandlerConfig
+	seen := make(map[string]bool)
+
+	for _, c := range next {
+		if len(c.Tag) == 0 {
+			matched := false
+			for idx, u := range untagged {
+				if u != nil && proto.Equal(u, c) {
+					untagged[idx] = nil
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				toAdd = append(toAdd, c)
+			}
+			continue
+		}
+
+		if seen[c.Tag] {
+			return nil, nil, newError("duplicated inbound tag: ", c.Tag)
+		}
+		seen[c.Tag] = true
+
+		existing, found := tagged[c.Tag]
+		if found && proto.Equal(existing, c) {
+			continue
+		}
+		if found {
+			toRemove = append(toRemove, c.Tag)
+		}
+		toAdd = append(toAdd, c)
+	}
+
+	for _, u := range untagged {
+		if u != nil {
+			return nil, nil, newError("untagged inbound handlers can't be removed or changed by reloading")
+		}
+	}
+
+	for _, c := range current {
+		if len(c.Tag) > 0 && !seen[c.Tag] {
+			toRemove = append(toRemove, c.Tag)
+		}
+	}
+
+	return toRemove, toAdd, nil
+}
+
+// diffOutbounds compares two lists of outbound handler configs. It returns the tags of handlers to be removed, and
+// the configs of handlers to be added. Only handlers whose config changed are replaced. As the first handler is the
+// default one, if the first config changes, the current default handler is removed first, and the new first handler
+// is added first, so that it becomes the default one.
+func diffOutbounds(current []*proxyman.OutboundHandlerConfig, next []*proxyman.OutboundHandlerConfig) ([]string, []*proxyman.OutboundHandlerConfig, error) {
+	tagged := make(map[string]*proxyman.OutboundHandlerConfig)
+	var untagged []*proxyman.OutboundHandlerConfig
+	for _, c := range current {
+		if len(c.Tag) > 0 {
+			tagged[c.Tag] = c
+		} else {
+			untagged = append(untagged, c)
+		}
+	}
+
+	var toRemove []string
+	var toAdd []*proxyman.OutboundHandlerConfig
+	removed := make(map[string]bool)
+	remove := func(c *proxyman.OutboundHandlerConfig) error {
+		if len(c.Tag) == 0 {
+			return newError("untagged outbound handlers can't be removed or changed by reloading")
+		}
+		if !removed[c.Tag] {
+			removed[c.Tag] = true
+			toRemove = append(toRemove, c.Tag)
+		}
+		return nil
+	}
+
+	defaultChanged := len(current) > 0 && len(next) > 0 && !proto.Equal(current[0], next[0])
+	if defaultChanged {
+		if err := remove(current[0]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	seen := make(map[string]bool)
+	for idx, c := range next {
+		if len(c.Tag) == 0 {
+			matched := false
+			for i, u := range untagged {
+				if u != nil && proto.Equal(u, c) && !(idx == 0 && defaultChanged) {
+					untagged[i] = nil
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				toAdd = append(toAdd, c)
+			}
+			continue
+		}
+
+		if seen[c.Tag] {
+			return nil, nil, newError("duplicated outbound tag: ", c.Tag)
+		}
+		seen[c.Tag] = true
+
+		existing, found := tagged[c.Tag]
+		if found && !removed[c.Tag] && proto.Equal(existing, c) && !(idx == 0 && defaultChanged) {
+			continue
+		}
+		if found {
+			if err := remove(existing); err != nil {
+				return nil, nil, err
+			}
+		}
+		toAdd = append(toAdd, c)
+	}
+
+	for _, u := range untagged {
+		if u != nil {
+			return nil, nil, newError("untagged outbound handlers can't be removed or changed by reloading")
+		}
+	}
+
+	for _, c := range current {
+		if len(c.Tag) > 0 && !seen[c.Tag] {
+			if err := remove(c); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return toRemove, toAdd, nil
+}
+
+// reloadApps applies the app configs in the new config to apps that support reloading.
+func (s *simpleServer) reloadApps(config *Config) error {
+	nextConfigs := make(map[reflect.Type]proto.Message)
+	for _, appSettings := range config.App {
+		settings, err := appSettings.GetInstance()
+		if err != nil {
+			return err
+		}
+		nextConfigs[reflect.TypeOf(settings)] = settings
+	}
+
+	for t, application := range s.apps {
+		next, found := nextConfigs[t]
+		if !found {
+			// Apps created with default settings are not in config.
+			if !proto.Equal(s.appConfigs[t], reflect.New(t.Elem()).Interface().(proto.Message)) {
+				log.Trace(newError("settings of ", t, " are removed from config, but the app can't be removed. Keeping its current settings.").AtWarning())
+			}
+			continue
+		}
+		if proto.Equal(s.appConfigs[t], next) {
+			continue
+		}
+		r, ok := application.(app.Reloadable)
+		if !ok {
+			log.Trace(newError("settings of ", t, " changed, but it doesn't support reloading. Restart V2Ray to apply.").AtWarning())
+			continue
+		}
+		if err := r.Reload(next); err != nil {
+			return newError("failed to reload ", t).Base(err)
+		}
+		s.appConfigs[t] = next
+		log.Trace(newError("reloaded ", t).AtInfo())
+	}
+
+	for t := range nextConfigs {
+		if _, found := s.apps[t]; !found {
+			log.Trace(newError("new app ", t, " is added to config. Restart V2Ray to apply.").AtWarning())
+		}
+	}
+
+	return nil
+}
+
+// validateInbound checks the settings of an inbound handler config before any handler is changed.
+func validateInbound(config *proxyman.InboundHandlerConfig) error {
+	if config.ReceiverSettings == nil || config.ProxySettings == nil {
+		return newError("inbound handler ", config.Tag, " has no receiver or proxy settings")
+	}
+	settings, err := config.ReceiverSettings.GetInstance()
+	if err != nil {
+		return newError("invalid receiver settings of inbound handler ", config.Tag).Base(err)
+	}
+	if _, ok := settings.(*proxyman.ReceiverConfig); !ok {
+		return newError("inbound handler ", config.Tag, " has no ReceiverConfig")
+	}
+	if _, err := config.ProxySettings.GetInstance(); err != nil {
+		return newError("invalid proxy settings of inbound handler ", config.Tag).Base(err)
+	}
+	return nil
+}
+
+// validateOutbound checks the settings of an outbound handler config before any handler is changed.
+func validateOutbound(config *proxyman.OutboundHandlerConfig) error {
+	if config.ProxySettings == nil {
+		return newError("outbound handler ", config.Tag, " has no proxy settings")
+	}
+	if _, err := config.ProxySettings.GetInstance(); err != nil {
+		return newError("invalid proxy settings of outbound handler ", config.Tag).Base(err)
+	}
+	if config.SenderSettings != nil {
+		if _, err := config.SenderSettings.GetInstance(); err != nil {
+			return newError("invalid sender settings of outbound handler ", config.Tag).Base(err)
+		}
+	}
+	return nil
+}
+
+// handlerReload changes handlers one by one, and keeps the configs of running handlers up to date after each step,
+// so that they describe the actual handlers even if the reload fails halfway.
+type handlerReload struct {
+	ctx       context.Context
+	ihm       proxyman.InboundHandlerManager
+	ohm       proxyman.OutboundHandlerManager
+	inbounds  []*proxyman.InboundHandlerConfig
+	outbounds []*proxyman.OutboundHandlerConfig
+
+	removedInbounds  []*proxyman.InboundHandlerConfig
+	removedOutbounds []*proxyman.OutboundHandlerConfig
+	addedInbounds    []string
+	addedOutbounds   []string
+}
+
+func (h *handlerReload) removeInbound(tag string) error {
+	if err := h.ihm.RemoveHandler(h.ctx, tag); err != nil {
+		return newError("failed to remove inbound handler: ", tag).Base(err)
+	}
+	for idx, c := range h.inbounds {
+		if c.Tag == tag {
+			h.removedInbounds = append(h.removedInbounds, c)
+			h.inbounds = append(h.inbounds[:idx:idx], h.inbounds[idx+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (h *handlerReload) removeOutbound(tag string) error {
+	if err := h.ohm.RemoveHandler(h.ctx, tag); err != nil {
+		return newError("failed to remove outbound handler: ", tag).Base(err)
+	}
+	for idx, c := range h.outbounds {
+		if c.Tag == tag {
+			h.removedOutbounds = append(h.removedOutbounds, c)
+			h.outbounds = append(h.outbounds[:idx:idx], h.outbounds[idx+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (h *handlerReload) addInbound(config *proxyman.InboundHandlerConfig) error {
+	if err := h.ihm.AddHandler(h.ctx, config); err != nil {
+		return newError("failed to add inbound handler: ", config.Tag).Base(err)
+	}
+	h.inbounds = append(h.inbounds[:len(h.inbounds):len(h.inbounds)], config)
+	h.addedInbounds = append(h.addedInbounds, config.Tag)
+	return nil
+}
+
+func (h *handlerReload) addOutbound(config *proxyman.OutboundHandlerConfig) error {
+	if err := h.ohm.AddHandler(h.ctx, config); err != nil {
+		return newError("failed to add outbound handler: ", config.Tag).Base(err)
+	}
+	h.outbounds = append(h.outbounds[:len(h.outbounds):len(h.outbounds)], config)
+	h.addedOutbounds = append(h.addedOutbounds, config.Tag)
+	return nil
+}
+
+// rollback removes the handlers added, and adds back the handlers removed. Handlers that can't be rolled back are
+// left as they are, and are still described by the configs of running handlers.
+func (h *handlerReload) rollback() {
+	removedInbounds, removedOutbounds := h.removedInbounds, h.removedOutbounds
+
+	for idx := len(h.addedInbounds) - 1; idx >= 0; idx-- {
+		if tag := h.addedInbounds[idx]; len(tag) > 0 {
+			if err := h.removeInbound(tag); err != nil {
+				log.Trace(newError("failed to roll back").Base(err).AtError())
+			}
+		}
+	}
+	for idx := len(h.addedOutbounds) - 1; idx >= 0; idx-- {
+		if tag := h.addedOutbounds[idx]; len(tag) > 0 {
+			if err := h.removeOutbound(tag); err != nil {
+				log.Trace(newError("failed to roll back").Base(err).AtError())
+			}
+		}
+	}
+
+	for _, c := range removedOutbounds {
+		if err := h.addOutbound(c); err != nil {
+			log.Trace(newError("failed to roll back").Base(err).AtError())
+		}
+	}
+	for _, c := range removedInbounds {
+		if err := h.addInbound(c); err != nil {
+			log.Trace(newError("failed to roll back").Base(err).AtError())
+		}
+	}
+}
+
+func (h *handlerReload) apply(inboundsToRemove []string, inboundsToAdd []*proxyman.InboundHandlerConfig, outboundsToRemove []string, outboundsToAdd []*proxyman.OutboundHandlerConfig) error {
+	for _, tag := range inboundsToRemove {
+		if err := h.removeInbound(tag); err != nil {
+			return err
+		}
+	}
+	for _, tag := range outboundsToRemove {
+		if err := h.removeOutbound(tag); err != nil {
+			return err
+		}
+	}
+	for _, c := range outboundsToAdd {
+		if err := h.addOutbound(c); err != nil {
+			return err
+		}
+	}
+	for _, c := range inboundsToAdd {
+		if err := h.addInbound(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload implements Server. New handler configs are validated before any handler is changed. Handlers are changed
+// before apps are reloaded, so that apps may refer to new handlers, e.g., outbounds of balancers in the router. If a
+// handler fails to be changed, for example its port is in use, or an app fails to reload, the handlers changed so far
+// are rolled back. Apps already reloaded are kept with their new settings.
+func (s *simpleServer) Reload(config *Config) error {
+	s.Lock()
+	defer s.Unlock()
+
+	inboundsToRemove, inboundsToAdd, err := diffInbounds(s.config.Inbound, config.Inbound)
+	if err != nil {
+		return err
+	}
+	outboundsToRemove, outboundsToAdd, err := diffOutbounds(s.config.Outbound, config.Outbound)
+	if err != nil {
+		return err
+	}
+	for _, c := range inboundsToAdd {
+		if err := validateInbound(c); err != nil {
+			return err
+		}
+	}
+	for _, c := range outboundsToAdd {
+		if err := validateOutbound(c); err != nil {
+			return err
+		}
+	}
+
+	if !proto.Equal(s.config.Transport, config.Transport) {
+		log.Trace(newError("transport settings changed. Restart V2Ray to apply.").AtWarning())
+	}
+
+	h := &handlerReload{
+		ctx:       s.ctx,
+		ihm:       proxyman.InboundHandlerManagerFromSpace(s.space),
+		ohm:       proxyman.OutboundHandlerManagerFromSpace(s.space),
+		inbounds:  s.config.Inbound,
+		outbounds: s.config.Outbound,
+	}
+	err = h.apply(inboundsToRemove, inboundsToAdd, outboundsToRemove, outboundsToAdd)
+	if err == nil {
+		err = s.reloadApps(config)
+	}
+	if err != nil {
+		h.rollback()
+		running := proto.Clone(s.config).(*Config)
+		running.Inbound = h.inbounds
+		running.Outbound = h.outbounds
+		s.config = running
+		return err
+	}
+
+	s.config = config
+	log.Trace(newError("V2Ray reloaded: ", len(inboundsToRemove), " inbound(s) removed, ", len(inboundsToAdd), " inbound(s) added, ",
+		len(outboundsToRemove), " outbound(s) removed, ", len(outboundsToAdd), " outbound(s) added").AtWarning())
+	return nil
+}
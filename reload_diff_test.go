@@ -0,0 +1,99 @@
+package core
+
+import (
+	"testing"
+
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/blackhole"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+)
+
+func outboundConfigs(tags ...string) []*proxyman.OutboundHandlerConfig {
+	configs := make([]*proxyman.OutboundHandlerConfig, 0, len(tags))
+	for _, tag := range tags {
+		configs = append(configs, &proxyman.OutboundHandlerConfig{
+			Tag:           tag,
+			ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+		})
+	}
+	return configs
+}
+
+func TestDiffOutbounds(t *testing.T) {
+	assert := assert.On(t)
+
+	changed := func(tag string) *proxyman.OutboundHandlerConfig {
+		return &proxyman.OutboundHandlerConfig{
+			Tag:           tag,
+			ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+		}
+	}
+	tagsOf := func(configs []*proxyman.OutboundHandlerConfig) []string {
+		tags := make([]string, 0, len(configs))
+		for _, c := range configs {
+			tags = append(tags, c.Tag)
+		}
+		return tags
+	}
+
+	cases := []struct {
+		current  []*proxyman.OutboundHandlerConfig
+		next     []*proxyman.OutboundHandlerConfig
+		toRemove []string
+		toAdd    []string
+	}{
+		{
+			// Handlers after a changed one are kept.
+			current:  outboundConfigs("a", "b", "c"),
+			next:     append(outboundConfigs("a"), changed("b"), outboundConfigs("c")[0]),
+			toRemove: []string{"b"},
+			toAdd:    []string{"b"},
+		},
+		{
+			current:  outboundConfigs("a", "b", "c"),
+			next:     outboundConfigs("a", "c", "d"),
+			toRemove: []string{"b"},
+			toAdd:    []string{"d"},
+		},
+		{
+			// The new default handler is added first.
+			current:  outboundConfigs("a", "b", "c"),
+			next:     append([]*proxyman.OutboundHandlerConfig{changed("a")}, outboundConfigs("b", "c")...),
+			toRemove: []string{"a"},
+			toAdd:    []string{"a"},
+		},
+		{
+			current:  outboundConfigs("a", "b", "c"),
+			next:     outboundConfigs("c", "a", "b"),
+			toRemove: []string{"a", "c"},
+			toAdd:    []string{"c", "a"},
+		},
+		{
+			current:  outboundConfigs("a", "b"),
+			next:     outboundConfigs("b"),
+			toRemove: []string{"a", "b"},
+			toAdd:    []string{"b"},
+		},
+	}
+
+	for _, c := range cases {
+		toRemove, toAdd, err := diffOutbounds(c.current, c.next)
+		assert.Error(err).IsNil()
+		assert.Int(len(toRemove)).Equals(len(c.toRemove))
+		for idx, tag := range c.toRemove {
+			assert.String(toRemove[idx]).Equals(tag)
+		}
+		added := tagsOf(toAdd)
+		assert.Int(len(added)).Equals(len(c.toAdd))
+		for idx, tag := range c.toAdd {
+			assert.String(added[idx]).Equals(tag)
+		}
+	}
+
+	_, _, err := diffOutbounds(outboundConfigs("a", ""), outboundConfigs("a"))
+	assert.Error(err).IsNotNil()
+	_, _, err = diffOutbounds(outboundConfigs("a"), outboundConfigs("a", "b", "b"))
+	assert.Error(err).IsNotNil()
+}
@@ -0,0 +1,178 @@
+package core_test
+
+import (
+	"net"
+	"testing"
+
+	. "v2ray.com/core"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/dice"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	_ "v2ray.com/core/main/distro/all"
+	"v2ray.com/core/proxy/dokodemo"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+)
+
+func dokodemoInbound(tag string, port v2net.Port) *proxyman.InboundHandlerConfig {
+	return &proxyman.InboundHandlerConfig{
+		Tag: tag,
+		ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+			PortRange: v2net.SinglePortRange(port),
+			Listen:    v2net.NewIPOrDomain(v2net.LocalHostIP),
+		}),
+		ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+			Address: v2net.NewIPOrDomain(v2net.LocalHostIP),
+			Port:    uint32(0),
+			NetworkList: &v2net.NetworkList{
+				Network: []v2net.Network{v2net.Network_TCP},
+			},
+		}),
+	}
+}
+
+func isListening(port v2net.Port) bool {
+	conn, err := net.Dial("tcp", v2net.TCPDestination(v2net.LocalHostIP, port).NetAddr())
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func TestV2RayReload(t *testing.T) {
+	assert := assert.On(t)
+
+	port1 := v2net.Port(dice.Roll(20000) + 10000)
+	port2 := port1 + 1
+	outbound := &proxyman.OutboundHandlerConfig{
+		Tag:           "direct",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	}
+
+	server, err := New(&Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{dokodemoInbound("in1", port1)},
+		Outbound: []*proxyman.OutboundHandlerConfig{outbound},
+	})
+	assert.Error(err).IsNil()
+	assert.Error(server.Start()).IsNil()
+	defer server.Close()
+
+	assert.Bool(isListening(port1)).IsTrue()
+	assert.Bool(isListening(port2)).IsFalse()
+
+	assert.Error(server.Reload(&Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{dokodemoInbound("in2", port2)},
+		Outbound: []*proxyman.OutboundHandlerConfig{outbound},
+	})).IsNil()
+
+	assert.Bool(isListening(port1)).IsFalse()
+	assert.Bool(isListening(port2)).IsTrue()
+
+	assert.Error(server.Reload(&Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{dokodemoInbound("", port1)},
+		Outbound: []*proxyman.OutboundHandlerConfig{outbound},
+	})).IsNil()
+
+	assert.Error(server.Reload(&Config{
+		Outbound: []*proxyman.OutboundHandlerConfig{outbound},
+	})).IsNotNil()
+}
+
+func TestV2RayReloadRollback(t *testing.T) {
+	assert := assert.On(t)
+
+	port1 := v2net.Port(dice.Roll(20000) + 10000)
+	port2 := port1 + 1
+	port3 := port1 + 2
+	outbound := &proxyman.OutboundHandlerConfig{
+		Tag:           "direct",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	}
+
+	listener, err := net.Listen("tcp", v2net.TCPDestination(v2net.LocalHostIP, port3).NetAddr())
+	assert.Error(err).IsNil()
+	defer listener.Close()
+
+	server, err := New(&Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{dokodemoInbound("in1", port1)},
+		Outbound: []*proxyman.OutboundHandlerConfig{outbound},
+	})
+	assert.Error(err).IsNil()
+	assert.Error(server.Start()).IsNil()
+	defer server.Close()
+
+	// The port of in2 is in use, so that in1 is rolled back to its old port.
+	assert.Error(server.Reload(&Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{dokodemoInbound("in1", port2), dokodemoInbound("in2", port3)},
+		Outbound: []*proxyman.OutboundHandlerConfig{outbound},
+	})).IsNotNil()
+	assert.Bool(isListening(port1)).IsTrue()
+	assert.Bool(isListening(port2)).IsFalse()
+
+	// Invalid configs are rejected before any handler is changed.
+	assert.Error(server.Reload(&Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{{Tag: "in2"}},
+		Outbound: []*proxyman.OutboundHandlerConfig{outbound},
+	})).IsNotNil()
+	assert.Bool(isListening(port1)).IsTrue()
+
+	listener.Close()
+	assert.Error(server.Reload(&Config{
+		Inbound:  []*proxyman.InboundHandlerConfig{dokodemoInbound("in1", port2), dokodemoInbound("in2", port3)},
+		Outbound: []*proxyman.OutboundHandlerConfig{outbound},
+	})).IsNil()
+	assert.Bool(isListening(port1)).IsFalse()
+	assert.Bool(isListening(port2)).IsTrue()
+	assert.Bool(isListening(port3)).IsTrue()
+}
+
+func TestV2RayReloadBalancer(t *testing.T) {
+	assert := assert.On(t)
+
+	direct := &proxyman.OutboundHandlerConfig{
+		Tag:           "direct",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	}
+	exit := &proxyman.OutboundHandlerConfig{
+		Tag:           "exit",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	}
+	routerConfig := func(outbounds ...string) *serial.TypedMessage {
+		return serial.ToTypedMessage(&router.Config{
+			BalancingRule: []*router.BalancingRule{
+				{Tag: "exits", OutboundTag: outbounds},
+			},
+		})
+	}
+
+	server, err := New(&Config{
+		App:      []*serial.TypedMessage{routerConfig("direct")},
+		Outbound: []*proxyman.OutboundHandlerConfig{direct},
+	})
+	assert.Error(err).IsNil()
+	assert.Error(server.Start()).IsNil()
+	defer server.Close()
+
+	// The balancer may refer to an outbound added in the same reload.
+	assert.Error(server.Reload(&Config{
+		App:      []*serial.TypedMessage{routerConfig("direct", "exit")},
+		Outbound: []*proxyman.OutboundHandlerConfig{direct, exit},
+	})).IsNil()
+
+	// Unknown outbounds are rejected, and the outbound added in the failed reload is rolled back.
+	exit2 := &proxyman.OutboundHandlerConfig{
+		Tag:           "exit2",
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	}
+	assert.Error(server.Reload(&Config{
+		App:      []*serial.TypedMessage{routerConfig("exit2", "missing")},
+		Outbound: []*proxyman.OutboundHandlerConfig{direct, exit, exit2},
+	})).IsNotNil()
+	assert.Error(server.Reload(&Config{
+		App:      []*serial.TypedMessage{routerConfig("exit2")},
+		Outbound: []*proxyman.OutboundHandlerConfig{direct, exit, exit2},
+	})).IsNil()
+}
@@ -0,0 +1,128 @@
+package core_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	. "v2ray.com/core"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/dice"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/dokodemo"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/testing/assert"
+	"v2ray.com/core/testing/servers/tcp"
+)
+
+func startForwardingServer(assert *assert.Assert, dest v2net.Destination, port v2net.Port) Server {
+	inbound := dokodemoInbound("in", port)
+	inbound.ProxySettings = serial.ToTypedMessage(&dokodemo.Config{
+		Address: v2net.NewIPOrDomain(dest.Address),
+		Port:    uint32(dest.Port),
+		NetworkList: &v2net.NetworkList{
+			Network: []v2net.Network{v2net.Network_TCP},
+		},
+	})
+
+	server, err := New(&Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&policy.Config{
+				Level: map[uint32]*policy.Policy{
+					0: {
+						Timeout: &policy.Policy_Timeout{
+							UplinkOnly:   &policy.Second{Value: 1},
+							DownlinkOnly: &policy.Second{Value: 1},
+						},
+					},
+				},
+			}),
+		},
+		Inbound: []*proxyman.InboundHandlerConfig{inbound},
+		Outbound: []*proxyman.OutboundHandlerConfig{{
+			ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+		}},
+	})
+	assert.Error(err).IsNil()
+	assert.Error(server.Start()).IsNil()
+	return server
+}
+
+func echo(assert *assert.Assert, conn net.Conn) {
+	payload := []byte("ping")
+	_, err := conn.Write(payload)
+	assert.Error(err).IsNil()
+	response := make([]byte, 1024)
+	nBytes, err := conn.Read(response)
+	assert.Error(err).IsNil()
+	assert.Bytes(response[:nBytes]).Equals(payload)
+}
+
+func startEchoServer(assert *assert.Assert) (*tcp.Server, v2net.Destination) {
+	tcpServer := &tcp.Server{
+		MsgProcessor: func(data []byte) []byte {
+			return data
+		},
+	}
+	dest, err := tcpServer.Start()
+	assert.Error(err).IsNil()
+	return tcpServer, dest
+}
+
+func TestV2RayShutdown(t *testing.T) {
+	assert := assert.On(t)
+
+	tcpServer, dest := startEchoServer(assert)
+	defer tcpServer.Close()
+
+	port := v2net.Port(dice.Roll(20000) + 10000)
+	server := startForwardingServer(assert, dest, port)
+
+	conn, err := net.Dial("tcp", v2net.TCPDestination(v2net.LocalHostIP, port).NetAddr())
+	assert.Error(err).IsNil()
+	echo(assert, conn)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Shutdown(context.Background())
+	}()
+
+	// Existing connection keeps working while the server is draining.
+	time.Sleep(time.Millisecond * 500)
+	echo(assert, conn)
+
+	// The port is released for a new process during draining.
+	listener, err := net.Listen("tcp", v2net.TCPDestination(v2net.LocalHostIP, port).NetAddr())
+	assert.Error(err).IsNil()
+	listener.Close()
+
+	conn.Close()
+	select {
+	case err := <-done:
+		assert.Error(err).IsNil()
+	case <-time.After(time.Second * 5):
+		t.Error("server didn't shut down after all connections finished")
+	}
+}
+
+func TestV2RayShutdownTimeout(t *testing.T) {
+	assert := assert.On(t)
+
+	tcpServer, dest := startEchoServer(assert)
+	defer tcpServer.Close()
+
+	port := v2net.Port(dice.Roll(20000) + 10000)
+	server := startForwardingServer(assert, dest, port)
+
+	conn, err := net.Dial("tcp", v2net.TCPDestination(v2net.LocalHostIP, port).NetAddr())
+	assert.Error(err).IsNil()
+	defer conn.Close()
+	echo(assert, conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
+	defer cancel()
+	assert.Error(server.Shutdown(ctx)).IsNotNil()
+}
@@ -0,0 +1,163 @@
+// +build generate
+
+// geoipdat_gen generates a GeoIP data file for routing, from the delegation stats of regional Internet registries.
+// Usage: go run geoipdat_gen.go -o geoip.dat
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common/errors"
+)
+
+var (
+	output = flag.String("o", "geoip.dat", "Path of the generated GeoIP data file.")
+
+	statsFiles = []string{
+		"http://ftp.afrinic.net/pub/stats/afrinic/delegated-afrinic-extended-latest",
+		"http://ftp.apnic.net/apnic/stats/apnic/delegated-apnic-extended-latest",
+		"http://ftp.arin.net/pub/stats/arin/delegated-arin-extended-latest",
+		"http://ftp.lacnic.net/pub/stats/lacnic/delegated-lacnic-extended-latest",
+		"http://ftp.ripe.net/pub/stats/ripencc/delegated-ripencc-extended-latest",
+	}
+
+	privateNetworks = []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.0.0.0/24",
+		"192.0.2.0/24",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"198.51.100.0/24",
+		"203.0.113.0/24",
+		"224.0.0.0/4",
+		"240.0.0.0/4",
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+	}
+)
+
+func main() {
+	flag.Parse()
+
+	list := &router.GeoIPList{
+		Country: make(map[string]*router.CountryIPRange),
+	}
+	for _, url := range statsFiles {
+		if err := parseStats(url, list); err != nil {
+			log.Fatalf("Failed to parse %s: %v", url, err)
+		}
+	}
+
+	private := &router.CountryIPRange{}
+	for _, cidr := range privateNetworks {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatalf("Invalid network %s: %v", cidr, err)
+		}
+		ones, _ := network.Mask.Size()
+		ip := network.IP
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		private.Ips = append(private.Ips, &router.CIDR{
+			Ip:     []byte(ip),
+			Prefix: uint32(ones),
+		})
+	}
+	list.Country["private"] = private
+
+	content, err := proto.Marshal(list)
+	if err != nil {
+		log.Fatalf("Failed to marshal GeoIP list: %v", err)
+	}
+	if err := ioutil.WriteFile(*output, content, 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *output, err)
+	}
+}
+
+// parseStats adds IP ranges in the given delegation stats file to the list. Lines are in the form of
+// "registry|cc|type|start|value|date|status[|extensions...]".
+func parseStats(url string, list *router.GeoIPList) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return errors.New("unexpected status ", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		parts := strings.Split(strings.TrimSpace(scanner.Text()), "|")
+		if len(parts) < 7 || len(parts[1]) != 2 {
+			continue
+		}
+		if status := parts[6]; status != "allocated" && status != "assigned" {
+			continue
+		}
+		country := strings.ToLower(parts[1])
+		ip := net.ParseIP(parts[3])
+		value, err := strconv.ParseUint(parts[4], 10, 32)
+		if ip == nil || err != nil {
+			continue
+		}
+
+		ipRange, found := list.Country[country]
+		if !found {
+			ipRange = &router.CountryIPRange{}
+			list.Country[country] = ipRange
+		}
+		switch parts[2] {
+		case "ipv4":
+			ipRange.Ips = append(ipRange.Ips, splitIPv4Range(ip.To4(), uint32(value))...)
+		case "ipv6":
+			ipRange.Ips = append(ipRange.Ips, &router.CIDR{
+				Ip:     []byte(ip.To16()),
+				Prefix: uint32(value),
+			})
+		}
+	}
+	return scanner.Err()
+}
+
+// splitIPv4Range returns the CIDRs covering count addresses from the given IP. IPv4 ranges in delegation stats
+// are not necessarily aligned to a power of 2.
+func splitIPv4Range(ip net.IP, count uint32) []*router.CIDR {
+	var cidrs []*router.CIDR
+	start := uint64(binary.BigEndian.Uint32(ip))
+	remaining := uint64(count)
+	for remaining > 0 {
+		size := uint64(1)
+		prefix := uint32(32)
+		for prefix > 0 && start%(size*2) == 0 && size*2 <= remaining {
+			size *= 2
+			prefix--
+		}
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(start))
+		cidrs = append(cidrs, &router.CIDR{
+			Ip:     b,
+			Prefix: prefix,
+		})
+		start += size
+		remaining -= size
+	}
+	return cidrs
+}
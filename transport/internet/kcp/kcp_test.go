@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -73,3 +74,55 @@ func TestDialAndListen(t *testing.T) {
 
 	listerner.Close()
 }
+
+func TestListenerStopAccepting(t *testing.T) {
+	assert := assert.On(t)
+
+	var accepted int32
+	listener, err := NewListener(internet.ContextWithTransportSettings(context.Background(), &Config{}), v2net.LocalHostIP, v2net.Port(0), func(ctx context.Context, conn internet.Connection) bool {
+		atomic.AddInt32(&accepted, 1)
+		go func(c internet.Connection) {
+			io.Copy(c, c)
+			c.Close()
+		}(conn)
+		return true
+	})
+	assert.Error(err).IsNil()
+	defer listener.Close()
+	port := v2net.Port(listener.Addr().(*net.UDPAddr).Port)
+
+	ctx := internet.ContextWithTransportSettings(context.Background(), &Config{})
+	echo := func(conn internet.Connection) {
+		_, err := conn.Write([]byte("ping"))
+		assert.Error(err).IsNil()
+		b := make([]byte, 4)
+		_, err = io.ReadFull(conn, b)
+		assert.Error(err).IsNil()
+		assert.String(string(b)).Equals("ping")
+	}
+
+	conn1, err := DialKCP(ctx, v2net.UDPDestination(v2net.LocalHostIP, port))
+	assert.Error(err).IsNil()
+	echo(conn1)
+
+	listener.StopAccepting()
+
+	// Existing sessions keep working, while new ones are dropped.
+	echo(conn1)
+	conn2, err := DialKCP(ctx, v2net.UDPDestination(v2net.LocalHostIP, port))
+	assert.Error(err).IsNil()
+	conn2.Write([]byte("ping"))
+	time.Sleep(time.Second)
+	assert.Int(int(atomic.LoadInt32(&accepted))).Equals(1)
+	conn2.Close()
+
+	// The port is released after the last session ends.
+	conn1.Close()
+	for i := 0; i < 60 && listener.ActiveConnections() > 0; i++ {
+		time.Sleep(500 * time.Millisecond)
+	}
+	assert.Int(listener.ActiveConnections()).Equals(0)
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}, Port: int(port)})
+	assert.Error(err).IsNil()
+	udpConn.Close()
+}
@@ -82,6 +82,7 @@ type Listener struct {
 	header    internet.PacketHeader
 	security  cipher.AEAD
 	addConn   internet.AddConnection
+	stopped   bool
 }
 
 func NewListener(ctx context.Context, address v2net.Address, port v2net.Port, addConn internet.AddConnection) (*Listener, error) {
@@ -159,7 +160,7 @@ func (v *Listener) OnReceive(payload *buf.Buffer, src v2net.Destination, origina
 	conn, found := v.sessions[id]
 
 	if !found {
-		if cmd == CommandTerminate {
+		if cmd == CommandTerminate || v.stopped {
 			return
 		}
 		writer := &Writer{
@@ -205,7 +206,23 @@ func (v *Listener) Remove(id ConnectionID) {
 	default:
 		v.Lock()
 		delete(v.sessions, id)
+		drained := v.stopped && len(v.sessions) == 0
 		v.Unlock()
+		if drained {
+			v.hub.Close()
+		}
+	}
+}
+
+// StopAccepting implements internet.AcceptStopper. Packets of new sessions are dropped, and the UDP socket is closed
+// after the last session ends.
+func (v *Listener) StopAccepting() {
+	v.Lock()
+	v.stopped = true
+	drained := len(v.sessions) == 0
+	v.Unlock()
+	if drained {
+		v.hub.Close()
 	}
 }
 
@@ -29,6 +29,14 @@ type Listener interface {
 	Addr() net.Addr
 }
 
+// AcceptStopper is implemented by listeners whose connections share the listening socket, e.g., mKCP. Closing such a
+// listener terminates all its connections.
+type AcceptStopper interface {
+	// StopAccepting makes the listener reject new connections. The socket is released after all existing
+	// connections end.
+	StopAccepting()
+}
+
 func ListenTCP(ctx context.Context, address v2net.Address, port v2net.Port, conns chan<- Connection) (Listener, error) {
 	settings := StreamSettingsFromContext(ctx)
 	protocol := settings.GetEffectiveProtocol()
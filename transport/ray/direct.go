@@ -10,12 +10,27 @@ import (
 	"v2ray.com/core/common/platform"
 )
 
+// Option is an option for creating a Ray.
+type Option func(*directRay)
+
+// WithBufferSize sets the maximum size of buffered data in each direction of the Ray, in bytes. 0 for unlimited.
+func WithBufferSize(size uint64) Option {
+	return func(r *directRay) {
+		r.Input.sizeLimit = size
+		r.Output.sizeLimit = size
+	}
+}
+
 // NewRay creates a new Ray for direct traffic transport.
-func NewRay(ctx context.Context) Ray {
-	return &directRay{
+func NewRay(ctx context.Context, opts ...Option) Ray {
+	r := &directRay{
 		Input:  NewStream(ctx),
 		Output: NewStream(ctx),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 type directRay struct {
@@ -54,11 +69,13 @@ type Stream struct {
 	access      sync.RWMutex
 	data        buf.MultiBuffer
 	size        uint64
+	sizeLimit   uint64
 	ctx         context.Context
 	readSignal  chan bool
 	writeSignal chan bool
 	close       bool
 	err         bool
+	counters    []StatCounter
 }
 
 func NewStream(ctx context.Context) *Stream {
@@ -67,6 +84,7 @@ func NewStream(ctx context.Context) *Stream {
 		readSignal:  make(chan bool, 1),
 		writeSignal: make(chan bool, 1),
 		size:        0,
+		sizeLimit:   streamSizeLimit,
 	}
 }
 
@@ -148,7 +166,7 @@ func (s *Stream) Write(data buf.MultiBuffer) error {
 		return nil
 	}
 
-	for streamSizeLimit > 0 && s.size >= streamSizeLimit {
+	for s.sizeLimit > 0 && s.size >= s.sizeLimit {
 		select {
 		case <-s.ctx.Done():
 			return io.ErrClosedPipe
@@ -171,17 +189,28 @@ func (s *Stream) Write(data buf.MultiBuffer) error {
 		return io.ErrClosedPipe
 	}
 
+	size := data.Len()
 	if s.data == nil {
 		s.data = data
 	} else {
 		s.data.AppendMulti(data)
 	}
-	s.size += uint64(data.Len())
+	s.size += uint64(size)
+	for _, c := range s.counters {
+		c.Add(int64(size))
+	}
 	s.notifyWrite()
 
 	return nil
 }
 
+// AddCounter implements RayStream.
+func (s *Stream) AddCounter(c StatCounter) {
+	s.access.Lock()
+	s.counters = append(s.counters, c)
+	s.access.Unlock()
+}
+
 func (s *Stream) notifyRead() {
 	select {
 	case s.readSignal <- true:
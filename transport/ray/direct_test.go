@@ -47,3 +47,31 @@ func TestStreamClose(t *testing.T) {
 	_, err = stream.Read()
 	assert.Error(err).Equals(io.EOF)
 }
+
+type testCounter struct {
+	value int64
+}
+
+func (c *testCounter) Add(delta int64) int64 {
+	c.value += delta
+	return c.value
+}
+
+func TestStreamCounter(t *testing.T) {
+	assert := assert.On(t)
+
+	counter := new(testCounter)
+	stream := NewStream(context.Background())
+	stream.AddCounter(counter)
+
+	b1 := buf.New()
+	b1.AppendBytes('a', 'b', 'c')
+	assert.Error(stream.Write(buf.NewMultiBufferValue(b1))).IsNil()
+	assert.Int64(counter.value).Equals(3)
+
+	stream.Close()
+	b2 := buf.New()
+	b2.AppendBytes('d')
+	assert.Error(stream.Write(buf.NewMultiBufferValue(b2))).Equals(io.ErrClosedPipe)
+	assert.Int64(counter.value).Equals(3)
+}
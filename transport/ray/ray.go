@@ -33,9 +33,16 @@ type Ray interface {
 	OutboundRay
 }
 
+// StatCounter is a counter for the number of bytes written into a stream.
+type StatCounter interface {
+	Add(int64) int64
+}
+
 type RayStream interface {
 	Close()
 	CloseError()
+	// AddCounter registers a counter to be increased by the size of every successful write.
+	AddCounter(StatCounter)
 }
 
 type InputStream interface {
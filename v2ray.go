@@ -2,11 +2,18 @@ package core
 
 import (
 	"context"
+	gonet "net"
+	"reflect"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
 
 	"v2ray.com/core/app"
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/dns"
+	"v2ray.com/core/app/dns/resolver"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
@@ -20,6 +27,20 @@ type Server interface {
 
 	// Close closes the V2Ray server. All inbound and outbound connections will be closed immediately.
 	Close()
+
+	// Shutdown gracefully closes the V2Ray server. Inbound handlers stop accepting new connections first,
+	// and existing connections are allowed to finish until the given context is done. Then the server is closed.
+	Shutdown(ctx context.Context) error
+
+	// Dial creates a connection to the given destination through the dispatcher of the server, as if the connection
+	// came from an inbound handler. The connection is routed by the router, unless an outbound tag is set in ctx
+	// by proxy.ContextWithOutboundTag(). For UDP destinations, each Read and Write on the connection handles one packet.
+	// The connection is closed when ctx is done.
+	Dial(ctx context.Context, dest net.Destination) (gonet.Conn, error)
+
+	// Reload applies a new config to the running server. Only the inbound and outbound handlers
+	// and the apps whose config changed are affected. Connections in flight are kept.
+	Reload(config *Config) error
 }
 
 // New creates a new V2Ray server with given config.
@@ -29,13 +50,22 @@ func New(config *Config) (Server, error) {
 
 // simpleServer shell of V2Ray.
 type simpleServer struct {
-	space app.Space
+	sync.Mutex
+	space      app.Space
+	ctx        context.Context
+	config     *Config
+	apps       map[reflect.Type]app.Application
+	appConfigs map[reflect.Type]proto.Message
 }
 
 // newSimpleServer returns a new Point server based on given configuration.
 // The server is not started at this point.
 func newSimpleServer(config *Config) (*simpleServer, error) {
-	var server = new(simpleServer)
+	var server = &simpleServer{
+		config:     config,
+		apps:       make(map[reflect.Type]app.Application),
+		appConfigs: make(map[reflect.Type]proto.Message),
+	}
 
 	if err := config.Transport.Apply(); err != nil {
 		return nil, err
@@ -45,6 +75,7 @@ func newSimpleServer(config *Config) (*simpleServer, error) {
 	ctx := app.ContextWithSpace(context.Background(), space)
 
 	server.space = space
+	server.ctx = ctx
 
 	for _, appSettings := range config.App {
 		settings, err := appSettings.GetInstance()
@@ -58,6 +89,7 @@ func newSimpleServer(config *Config) (*simpleServer, error) {
 		if err := space.AddApplication(application); err != nil {
 			return nil, err
 		}
+		server.trackApp(settings, application)
 	}
 
 	if log.FromSpace(space) == nil {
@@ -96,7 +128,7 @@ func newSimpleServer(config *Config) (*simpleServer, error) {
 		inboundHandlerManager = o.(proxyman.InboundHandlerManager)
 	}
 
-	if dns.FromSpace(space) == nil {
+	if resolver.FromSpace(space) == nil {
 		dnsConfig := &dns.Config{
 			NameServers: []*net.Endpoint{{
 				Address: net.NewIPOrDomain(net.LocalHostDomain),
@@ -107,6 +139,17 @@ func newSimpleServer(config *Config) (*simpleServer, error) {
 			return nil, err
 		}
 		common.Must(space.AddApplication(d))
+		server.trackApp(dnsConfig, d)
+	}
+
+	if policy.FromSpace(space) == nil {
+		policyConfig := new(policy.Config)
+		p, err := app.CreateAppFromConfig(ctx, policyConfig)
+		if err != nil {
+			return nil, err
+		}
+		common.Must(space.AddApplication(p))
+		server.trackApp(policyConfig, p)
 	}
 
 	disp := dispatcher.FromSpace(space)
@@ -138,10 +181,28 @@ func newSimpleServer(config *Config) (*simpleServer, error) {
 	return server, nil
 }
 
+// trackApp records the config of an app, so that the app can be reloaded later.
+func (s *simpleServer) trackApp(config proto.Message, application app.Application) {
+	t := reflect.TypeOf(config)
+	s.apps[t] = application
+	s.appConfigs[t] = config
+}
+
 func (s *simpleServer) Close() {
 	s.space.Close()
 }
 
+func (s *simpleServer) Shutdown(ctx context.Context) error {
+	log.Trace(newError("V2Ray shutting down").AtWarning())
+
+	var err error
+	if ihm := proxyman.InboundHandlerManagerFromSpace(s.space); ihm != nil {
+		err = ihm.Drain(ctx)
+	}
+	s.Close()
+	return err
+}
+
 func (s *simpleServer) Start() error {
 	if err := s.space.Start(); err != nil {
 		return err